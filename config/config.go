@@ -6,6 +6,13 @@ type Config struct {
 	DB                     common.DatabaseConfig
 	Server                 common.ServerConfig
 	Auth                   common.AuthConfig
+	OIDC                   common.OIDCConfig
+	Audit                  common.AuditConfig
+	Avatar                 common.AvatarConfig
+	UserDeletion           common.UserDeletionConfig
+	UserImport             common.UserImportConfig
+	CompanyArchive         common.CompanyArchiveConfig
+	CompanyDocument        common.CompanyDocumentConfig
 	AdminMigration         common.AdminMigrationConfig
 	Minio                  common.MinioConfig
 	Mail                   common.MainMail
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type AuditController struct {
+	logger       *zap.Logger
+	auditService *service.AuditService
+}
+
+func NewAuditController(logger *zap.Logger, auditService *service.AuditService) *AuditController {
+	return &AuditController{
+		logger:       logger,
+		auditService: auditService,
+	}
+}
+
+// GetEvents admin-api
+// @Summary      Get audit log
+// @Description  Get recorded changes with their actor, target, changed fields, client IP and request ID
+// @Tags         Audit
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Success      200  {object}  model.GetAuditEventsResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "No access"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /audit [get]
+func (a *AuditController) GetEvents(c *gin.Context) {
+	events, total, serviceErr := a.auditService.GetEvents(dataProcessing.GetOptions(c), c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.GetAuditEventsResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Total:  total,
+		Events: events,
+	})
+}
@@ -1,27 +1,38 @@
 package controller
 
 import (
+	"fmt"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/middleware"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/helpers"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/service"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"net/http"
 )
 
 type AuthController struct {
-	logger  *zap.Logger
-	service *service.AuthService
+	logger                   *zap.Logger
+	service                  *service.AuthService
+	passwordResetService     *service.PasswordResetService
+	emailVerificationService *service.EmailVerificationService
 }
 
 func NewAuthController(
 	logger *zap.Logger,
-	service *service.AuthService) *AuthController {
+	service *service.AuthService,
+	passwordResetService *service.PasswordResetService,
+	emailVerificationService *service.EmailVerificationService) *AuthController {
 	return &AuthController{
-		logger:  logger,
-		service: service,
+		logger:                   logger,
+		service:                  service,
+		passwordResetService:     passwordResetService,
+		emailVerificationService: emailVerificationService,
 	}
 }
 
@@ -59,13 +70,16 @@ func (a *AuthController) Register(c *gin.Context) {
 
 // Login User authorisation user-api
 // @Summary      User authorisation
-// @Description  User authorisation
+// @Description  User authorisation. With two-factor authentication on, only a challenge token is returned, see /user/login/2fa. A generated password has to be changed first, see /user/login/password
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Param payload body model.LoginRequest true "User request"
 // @Success      200  {object}  model.LoginResponse "OK"
 // @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      401  {object}  base.ResponseFailure "Invalid email or password"
+// @Failure      423  {object}  base.ResponseFailure "Account is temporarily locked"
+// @Failure      429  {object}  base.ResponseFailure "Too many login attempts"
 // @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
 // @Router       /user/login [post]
 func (a *AuthController) Login(c *gin.Context) {
@@ -75,21 +89,72 @@ func (a *AuthController) Login(c *gin.Context) {
 		return
 	}
 
-	deviceID := helpers.GetDeviceID(c)
+	response, serviceErr := a.service.Login(&payload, helpers.GetClientInfo(c), c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
 
-	token, refresh, serviceErr := a.service.Login(&payload, deviceID, c)
+	response.Status = http.StatusText(http.StatusOK)
+	c.JSON(http.StatusOK, response)
+}
+
+// LoginTwoFactor Second step of user authorisation user-api
+// @Summary      Two-factor authorisation
+// @Description  Exchange the challenge token from /user/login and a TOTP or backup code for the tokens
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param payload body model.TwoFactorLoginRequest true "User request"
+// @Success      200  {object}  model.LoginResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      401  {object}  base.ResponseFailure "Invalid challenge or code"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/login/2fa [post]
+func (a *AuthController) LoginTwoFactor(c *gin.Context) {
+	var payload model.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	response, serviceErr := a.service.LoginTwoFactor(&payload, helpers.GetClientInfo(c), c)
 	if serviceErr != nil {
 		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.LoginResponse{
-		ResponseOK: base.ResponseOK{
-			Status: http.StatusText(http.StatusOK),
-		},
-		JWT:          *token,
-		RefreshToken: *refresh,
-	})
+	response.Status = http.StatusText(http.StatusOK)
+	c.JSON(http.StatusOK, response)
+}
+
+// LoginPasswordChange Password change on the first login user-api
+// @Summary      Change generated password
+// @Description  Exchange the challenge token from /user/login and a new password for the tokens. Users with a generated password have to change it on the first login. With two-factor authentication on, a two-factor challenge is returned instead
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param payload body model.PasswordChangeLoginRequest true "User request"
+// @Success      200  {object}  model.LoginResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      401  {object}  base.ResponseFailure "Invalid challenge"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/login/password [post]
+func (a *AuthController) LoginPasswordChange(c *gin.Context) {
+	var payload model.PasswordChangeLoginRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	response, serviceErr := a.service.LoginPasswordChange(&payload, helpers.GetClientInfo(c), c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	response.Status = http.StatusText(http.StatusOK)
+	c.JSON(http.StatusOK, response)
 }
 
 // Logout Unauthorized users user-api
@@ -113,7 +178,9 @@ func (a *AuthController) Logout(c *gin.Context) {
 		return
 	}
 
-	if serviceErr := a.service.Logout(payload.RefreshToken, c); serviceErr != nil {
+	claims, _ := c.Get(middleware.ClaimsKey)
+
+	if serviceErr := a.service.Logout(payload.RefreshToken, claims.(*auth.Claims), c); serviceErr != nil {
 		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
 		return
 	}
@@ -141,7 +208,7 @@ func (a *AuthController) RecreateJWT(c *gin.Context) {
 		return
 	}
 
-	token, newRefresh, _, serviceErr := a.service.RefreshJWT(payload.RefreshToken, c)
+	token, newRefresh, _, serviceErr := a.service.RefreshJWT(payload.RefreshToken, helpers.GetClientInfo(c), c)
 	if serviceErr != nil {
 		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
 		return
@@ -152,6 +219,243 @@ func (a *AuthController) RecreateJWT(c *gin.Context) {
 			Status: http.StatusText(http.StatusOK),
 		},
 		JWT:          *token,
-		RefreshToken: *newRefresh,
+		RefreshToken: newRefresh,
 	})
 }
+
+// ForgotPassword Request password reset user-api
+// @Summary      Request password reset
+// @Description  Send a one-time password reset link to the email. The response is the same whether the email is registered or not. A link is sent at most once per cooldown
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param payload body model.ForgotPasswordRequest true "User request"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/password/forgot [post]
+func (a *AuthController) ForgotPassword(c *gin.Context) {
+	var payload model.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.passwordResetService.RequestReset(&payload, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// ResetPassword Confirm password reset user-api
+// @Summary      Confirm password reset
+// @Description  Set a new password using the token from the reset email. All sessions of the user are signed out
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param payload body model.ResetPasswordRequest true "User request"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/password/reset [post]
+func (a *AuthController) ResetPassword(c *gin.Context) {
+	var payload model.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.passwordResetService.ConfirmReset(&payload, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// VerifyEmail Confirm email user-api
+// @Summary      Confirm email
+// @Description  Confirm email using the token from the verification email. A pending email change takes effect
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param payload body model.VerifyEmailRequest true "User request"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      409  {object}  base.ResponseFailure "Email is already taken"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/email/verify [post]
+func (a *AuthController) VerifyEmail(c *gin.Context) {
+	var payload model.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.emailVerificationService.Confirm(payload.Token, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// ResendEmailVerification Resend email confirmation user-api
+// @Summary      Resend email confirmation
+// @Description  Send the confirmation link again, to the pending email if an email change was requested
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      409  {object}  base.ResponseFailure "Email is already verified"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/email/verify/resend [post]
+func (a *AuthController) ResendEmailVerification(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+
+	if serviceErr := a.emailVerificationService.Resend(userID.(uuid.UUID), c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// GetSessions user-api
+// @Summary      Get active sessions
+// @Description  Get active sessions of the current user, the one the request was made with is marked as current
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Success      200  {object}  model.GetSessionsResponse "OK"
+// @Failure      401  {object}  base.ResponseFailure "Unauthorized"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/sessions [get]
+func (a *AuthController) GetSessions(c *gin.Context) {
+	claims, _ := c.Get(middleware.ClaimsKey)
+
+	sessions, serviceErr := a.service.GetSessions(claims.(*auth.Claims).UserID, claims.(*auth.Claims).SessionID, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.GetSessionsResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Sessions: sessions,
+	})
+}
+
+// RevokeSession user-api
+// @Summary      Revoke session
+// @Description  Revoke session of the current user
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        session-id path string true "Session ID"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      404  {object}  base.ResponseFailure "Session not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/sessions/{session-id} [delete]
+func (a *AuthController) RevokeSession(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+	sessionID, err := uuid.Parse(c.Params.ByName("session-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.service.RevokeSession(userID.(uuid.UUID), sessionID, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// RevokeOtherSessions user-api
+// @Summary      Revoke all sessions except current
+// @Description  Revoke every session of the current user except the one the request was made with
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      401  {object}  base.ResponseFailure "Unauthorized"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/sessions [delete]
+func (a *AuthController) RevokeOtherSessions(c *gin.Context) {
+	claims, _ := c.Get(middleware.ClaimsKey)
+
+	if serviceErr := a.service.RevokeOtherSessions(claims.(*auth.Claims).UserID, claims.(*auth.Claims).SessionID, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// GetLockoutEvents admin-api
+// @Summary      Get login lockouts
+// @Description  Get temporary lockouts of accounts and client IPs caused by repeated failed logins
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Success      200  {object}  model.GetLockoutEventsResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/lockouts [get]
+func (a *AuthController) GetLockoutEvents(c *gin.Context) {
+	events, total, serviceErr := a.service.GetLockoutEvents(dataProcessing.GetOptions(c), c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.GetLockoutEventsResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Total:  total,
+		Events: events,
+	})
+}
+
+// JWKS Public keys of access tokens
+// @Summary      JSON Web Key Set
+// @Description  Public keys used to verify access tokens issued by this service
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {object}  auth.JWKSet "OK"
+// @Router       /.well-known/jwks.json [get]
+func (a *AuthController) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(auth.JWKSMaxAge.Seconds())))
+	c.JSON(http.StatusOK, a.service.JWKS())
+}
@@ -3,6 +3,7 @@
 import (
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/middleware"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/service"
@@ -36,6 +37,7 @@ func NewCandidateController(logger *zap.Logger, candidateService *service.Candid
 // @Param        payload body   model.AddNewCandidateRequest true "User data"
 // @Success      200  {object}  base.ResponseOK "OK"
 // @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Not a member of the company"
 // @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
 // @Router       /candidate/vacancy/{vacancy-id} [post]
 func (a *CandidateController) CreateCandidate(c *gin.Context) {
@@ -69,10 +71,12 @@ func (a *CandidateController) CreateCandidate(c *gin.Context) {
 
 // RetrieveCandidate
 // @Summary      Retrieve Candidate
-// @Description  Retrieve Candidate
+// @Description  Retrieve Candidate. Name and email are hidden unless the user may read candidates of the company.
 // @Tags         Candidate
 // @Accept       json
 // @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string false "Authorization"
 // @Param        candidate-id path string true "Candidate id"
 // @Success      200  {object}  model.RetrieveCandidateResponse "OK"
 // @Failure      400  {object}  base.ResponseFailure "Bad request"
@@ -85,7 +89,7 @@ func (a *CandidateController) RetrieveCandidate(c *gin.Context) {
 		return
 	}
 
-	candidate, serviceErr := a.candidateService.RetrieveCandidate(candidateId, c)
+	candidate, serviceErr := a.candidateService.RetrieveCandidate(candidateId, middleware.OptionalUserID(c), c)
 	if serviceErr != nil {
 		c.JSON(http.StatusBadRequest, serviceErr)
 		return
@@ -101,16 +105,20 @@ func (a *CandidateController) RetrieveCandidate(c *gin.Context) {
 
 // GetCandidates
 // @Summary      Get Candidates
-// @Description  Get Candidates
+// @Description  Get Candidates of the companies in which the user may read candidates
 // @Tags         Candidate
 // @Accept       json
 // @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
 // @Success      200  {object}   model.GetCandidatesResponse "OK"
 // @Failure      400  {object}  base.ResponseFailure "Bad request"
 // @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
 // @Router       /candidate [get]
 func (a *CandidateController) GetCandidates(c *gin.Context) {
-	candidates, serviceErr := a.candidateService.GetCandidate(dataProcessing.GetOptions(c), c)
+	userID, _ := c.Get(middleware.UserIDKey)
+
+	candidates, serviceErr := a.candidateService.GetCandidate(userID.(uuid.UUID), dataProcessing.GetOptions(c), c)
 	if serviceErr != nil {
 		c.JSON(http.StatusBadRequest, serviceErr)
 		return
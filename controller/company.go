@@ -13,6 +13,24 @@
 	"net/http"
 )
 
+// companySlugPath returns the path the company with the slug is retrieved by.
+func companySlugPath(slug string) string {
+	return "/api/company/by-slug/" + slug
+}
+
+// companyIncludes reads the include query parameter, the defaults are used when it is not given.
+func companyIncludes(c *gin.Context, defaults model.CompanyIncludes) (model.CompanyIncludes, *base.ServiceError) {
+	value, ok := c.GetQuery("include")
+	if !ok {
+		return defaults, nil
+	}
+
+	return service.ParseCompanyIncludes(value)
+}
+
+// retrieveCompanyIncludes load the whole company unless the client asks for less.
+var retrieveCompanyIncludes = model.CompanyIncludes{Users: true, Vacancies: true, Candidates: true}
+
 type CompanyController struct {
 	logger         *zap.Logger
 	companyService *service.CompanyService
@@ -75,6 +93,7 @@ func (a *CompanyController) CreateCompany(c *gin.Context) {
 // @Param        file formData file true "file"
 // @Success      200  {object}  base.ResponseOK "OK"
 // @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Not a member of the company"
 // @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
 // @Router       /company/{company-id}/logo [post]
 func (a *CompanyController) UploadLogo(c *gin.Context) {
@@ -116,8 +135,9 @@ func (a *CompanyController) UploadLogo(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Security ApiKeyAuth
-// @param Authorization header string true "Authorization"
+// @param Authorization header string false "Authorization"
 // @Param        company-id path string true "Company id"
+// @Param        include query string false "Comma separated children to load: users, vacancies, candidates. All of them by default"
 // @Success      200  {object}  model.RetrieveCompanyResponse "OK"
 // @Failure      400  {object}  base.ResponseFailure "Bad request"
 // @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
@@ -129,7 +149,13 @@ func (a *CompanyController) RetrieveCompany(c *gin.Context) {
 		return
 	}
 
-	company, serviceErr := a.companyService.RetrieveCompany(companyId, c)
+	includes, serviceErr := companyIncludes(c, retrieveCompanyIncludes)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	company, serviceErr := a.companyService.RetrieveCompany(companyId, middleware.OptionalUserID(c), includes, c)
 	if serviceErr != nil {
 		c.JSON(http.StatusBadRequest, base.ResponseFailure{
 			Status:  http.StatusText(http.StatusBadRequest),
@@ -147,6 +173,60 @@ func (a *CompanyController) RetrieveCompany(c *gin.Context) {
 	})
 }
 
+// RetrieveCompanyBySlug
+// @Summary      Retrieve Company by slug
+// @Description  Retrieve the company by its slug. A former slug redirects to the current one
+// @Tags         Company
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string false "Authorization"
+// @Param        company-slug path string true "Company slug"
+// @Param        include query string false "Comma separated children to load: users, vacancies, candidates. All of them by default"
+// @Success      200  {object}  model.RetrieveCompanyResponse "OK"
+// @Success      301  "Moved to the current slug"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/by-slug/{company-slug} [get]
+func (a *CompanyController) RetrieveCompanyBySlug(c *gin.Context) {
+	slug := c.Param("company-slug")
+
+	includes, serviceErr := companyIncludes(c, retrieveCompanyIncludes)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	companyID, currentSlug, serviceErr := a.companyService.GetCompanyIDBySlug(slug, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	if currentSlug != slug {
+		location := companySlugPath(currentSlug)
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+
+	company, serviceErr := a.companyService.RetrieveCompany(companyID, middleware.OptionalUserID(c), includes, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.RetrieveCompanyResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Company: *company,
+	})
+}
+
 // GetCompany
 // @Summary      Get Company
 // @Description  Get Company
@@ -154,13 +234,20 @@ func (a *CompanyController) RetrieveCompany(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Security ApiKeyAuth
-// @param Authorization header string true "Authorization"
+// @param Authorization header string false "Authorization"
+// @Param        include query string false "Comma separated children to load: users, vacancies, candidates. None by default"
 // @Success      200  {object}  model.GetCompanyResponse "OK"
 // @Failure      400  {object}  base.ResponseFailure "Bad request"
 // @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
 // @Router       /company [get]
 func (a *CompanyController) GetCompany(c *gin.Context) {
-	companies, serviceErr := a.companyService.GetCompany(dataProcessing.GetOptions(c), c)
+	includes, serviceErr := companyIncludes(c, model.CompanyIncludes{})
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	companies, serviceErr := a.companyService.GetCompany(dataProcessing.GetOptions(c), middleware.OptionalUserID(c), includes, c)
 	if serviceErr != nil {
 		c.JSON(http.StatusBadRequest, base.ResponseFailure{
 			Status:  http.StatusText(http.StatusBadRequest),
@@ -177,3 +264,110 @@ func (a *CompanyController) GetCompany(c *gin.Context) {
 		Companies: companies,
 	})
 }
+
+// GetStats
+// @Summary      Get Company stats
+// @Description  Get hiring numbers of the company: vacancies, candidates per vacancy, new candidates in the last 7 and 30 days, average days since application and the salary range of open vacancies
+// @Tags         Company
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Success      200  {object}  model.GetCompanyStatsResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id}/stats [get]
+func (a *CompanyController) GetStats(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	stats, serviceErr := a.companyService.GetStats(companyID, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.GetCompanyStatsResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Stats: *stats,
+	})
+}
+
+// UpdateCompany
+// @Summary      Update Company
+// @Description  Change the name and description of the company, omitted fields are kept
+// @Tags         Company
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Param        payload body   model.UpdateCompanyRequest true "Company data"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id} [patch]
+func (a *CompanyController) UpdateCompany(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	var payload model.UpdateCompanyRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.companyService.UpdateCompany(companyID, &payload, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// ArchiveCompany
+// @Summary      Archive Company
+// @Description  Archive the company: it is hidden, its vacancies are closed, pending invitations deleted and the logo removed. Members keep reading candidates until the company is purged after the retention period
+// @Tags         Company
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id} [delete]
+func (a *CompanyController) ArchiveCompany(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.companyService.ArchiveCompany(companyID, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/middleware"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// documentFormOverhead is the room left in an upload request for the form fields and multipart boundaries.
+const documentFormOverhead = 1 << 20
+
+type CompanyDocumentController struct {
+	logger                 *zap.Logger
+	companyDocumentService *service.CompanyDocumentService
+}
+
+func NewCompanyDocumentController(logger *zap.Logger, companyDocumentService *service.CompanyDocumentService) *CompanyDocumentController {
+	return &CompanyDocumentController{
+		logger:                 logger,
+		companyDocumentService: companyDocumentService,
+	}
+}
+
+// UploadDocument
+// @Summary      Upload a document
+// @Description  Upload a PDF, DOCX or XLSX file as a document of the company. The title defaults to the file name
+// @Tags         Company document
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Param        file formData file true "Document file"
+// @Param        type formData string true "Document type" Enums(contract, nda, requisites, other)
+// @Param        title formData string false "Title"
+// @Param        valid_from formData string false "Valid from, YYYY-MM-DD"
+// @Param        valid_until formData string false "Valid until, YYYY-MM-DD"
+// @Success      200  {object}  base.ResponseOKWithID "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      404  {object}  base.ResponseFailure "Company not found"
+// @Failure      413  {object}  base.ResponseFailure "File is too large"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id}/documents [post]
+func (a *CompanyDocumentController) UploadDocument(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+	companyID, err := uuid.Parse(c.Param("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	// the body is limited before the form is parsed, so a large file is never buffered to disk
+	maxFileSize := a.companyDocumentService.MaxFileSize()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxFileSize+documentFormOverhead)
+
+	var payload model.UploadCompanyDocumentRequest
+	if err := c.ShouldBind(&payload); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, base.ResponseFailure{
+				Status:  http.StatusText(http.StatusRequestEntityTooLarge),
+				Blame:   base.BlameUser,
+				Message: fmt.Sprintf("a document can't be larger than %d bytes", maxFileSize),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, base.ResponseFailure{
+			Status:  http.StatusText(http.StatusBadRequest),
+			Blame:   base.BlameUser,
+			Message: "failed to read file",
+		})
+		return
+	}
+	defer file.Close()
+
+	id, serviceErr := a.companyDocumentService.UploadDocument(companyID, userID.(uuid.UUID), file, header.Filename, header.Size, &payload, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOKWithID{
+		ID:     *id,
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// GetDocuments
+// @Summary      Get documents
+// @Description  Get documents of the company, the newest first. Download URLs are given only by the document retrieval
+// @Tags         Company document
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Success      200  {object}  model.GetCompanyDocumentsResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id}/documents [get]
+func (a *CompanyDocumentController) GetDocuments(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	documents, serviceErr := a.companyDocumentService.GetDocuments(companyID, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.GetCompanyDocumentsResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Documents: documents,
+	})
+}
+
+// RetrieveDocument
+// @Summary      Retrieve a document
+// @Description  Retrieve the document of the company with a presigned download URL
+// @Tags         Company document
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Param        document-id path string true "Document id"
+// @Success      200  {object}  model.RetrieveCompanyDocumentResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id}/documents/{document-id} [get]
+func (a *CompanyDocumentController) RetrieveDocument(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	documentID, err := uuid.Parse(c.Param("document-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	document, serviceErr := a.companyDocumentService.RetrieveDocument(companyID, documentID, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.RetrieveCompanyDocumentResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Document: *document,
+	})
+}
+
+// DeleteDocument
+// @Summary      Delete a document
+// @Description  Delete the document of the company together with its file
+// @Tags         Company document
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Param        document-id path string true "Document id"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id}/documents/{document-id} [delete]
+func (a *CompanyDocumentController) DeleteDocument(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	documentID, err := uuid.Parse(c.Param("document-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.companyDocumentService.DeleteDocument(companyID, documentID, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
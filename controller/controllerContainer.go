@@ -6,26 +6,54 @@
 )
 
 type Container struct {
-	AuthController      *AuthController
-	UserController      *UserController
-	CompanyController   *CompanyController
-	VacancyController   *VacancyController
-	CandidateController *CandidateController
+	AuthController            *AuthController
+	UserController            *UserController
+	CompanyController         *CompanyController
+	CompanyDocumentController *CompanyDocumentController
+	VacancyController         *VacancyController
+	CandidateController       *CandidateController
+	InvitationController      *InvitationController
+	MembershipController      *MembershipController
+	TwoFactorController       *TwoFactorController
+	ServiceAccountController  *ServiceAccountController
+	OIDCController            *OIDCController
+	RoleController            *RoleController
+	AuditController           *AuditController
 }
 
 func NewControllerContainer(
 	logger *zap.Logger,
 	authService *service.AuthService,
+	passwordResetService *service.PasswordResetService,
+	emailVerificationService *service.EmailVerificationService,
 	userService *service.UserService,
+	userExportService *service.UserExportService,
+	userImportService *service.UserImportService,
 	companyService *service.CompanyService,
+	companyDocumentService *service.CompanyDocumentService,
 	vacancyService *service.VacancyService,
 	candidateService *service.CandidateService,
+	invitationService *service.InvitationService,
+	membershipService *service.MembershipService,
+	twoFactorService *service.TwoFactorService,
+	serviceAccountService *service.ServiceAccountService,
+	oidcService *service.OIDCService,
+	roleService *service.RoleService,
+	auditService *service.AuditService,
 ) *Container {
 	return &Container{
-		AuthController:      NewAuthController(logger, authService),
-		UserController:      NewUserController(logger, userService),
-		CompanyController:   NewCompanyController(logger, companyService),
-		VacancyController:   NewVacancyController(logger, vacancyService),
-		CandidateController: NewCandidateController(logger, candidateService),
+		AuthController:            NewAuthController(logger, authService, passwordResetService, emailVerificationService),
+		UserController:            NewUserController(logger, userService, userExportService, userImportService),
+		CompanyController:         NewCompanyController(logger, companyService),
+		CompanyDocumentController: NewCompanyDocumentController(logger, companyDocumentService),
+		VacancyController:         NewVacancyController(logger, vacancyService),
+		CandidateController:       NewCandidateController(logger, candidateService),
+		InvitationController:      NewInvitationController(logger, invitationService),
+		MembershipController:      NewMembershipController(logger, membershipService, invitationService),
+		TwoFactorController:       NewTwoFactorController(logger, twoFactorService),
+		ServiceAccountController:  NewServiceAccountController(logger, serviceAccountService),
+		OIDCController:            NewOIDCController(logger, oidcService),
+		RoleController:            NewRoleController(logger, roleService),
+		AuditController:           NewAuditController(logger, auditService),
 	}
 }
@@ -0,0 +1,205 @@
+package controller
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/middleware"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type InvitationController struct {
+	logger            *zap.Logger
+	invitationService *service.InvitationService
+}
+
+func NewInvitationController(logger *zap.Logger, invitationService *service.InvitationService) *InvitationController {
+	return &InvitationController{
+		logger:            logger,
+		invitationService: invitationService,
+	}
+}
+
+// CreateInvitation
+// @Summary      Invite a member
+// @Description  Send an invitation into the company with a role. Requires the permission to manage members
+// @Tags         Invitation
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Param        payload body   model.CreateInvitationRequest true "Invitation data"
+// @Success      200  {object}  base.ResponseOKWithID "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      409  {object}  base.ResponseFailure "User is already registered"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id}/invitations [post]
+func (a *InvitationController) CreateInvitation(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+	companyID, err := uuid.Parse(c.Params.ByName("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	var payload model.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	id, serviceErr := a.invitationService.Invite(userID.(uuid.UUID), companyID, &payload, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOKWithID{
+		Status: http.StatusText(http.StatusOK),
+		ID:     *id,
+	})
+}
+
+// GetInvitations
+// @Summary      Get pending invitations
+// @Description  Get pending invitations of the company. Requires the permission to manage members
+// @Tags         Invitation
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Success      200  {object}  model.GetInvitationsResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id}/invitations [get]
+func (a *InvitationController) GetInvitations(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Params.ByName("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	invitations, serviceErr := a.invitationService.GetInvitations(companyID, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.GetInvitationsResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Invitations: invitations,
+	})
+}
+
+// RevokeInvitation
+// @Summary      Revoke invitation
+// @Description  Revoke a pending invitation of the company. Requires the permission to manage members
+// @Tags         Invitation
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Param        invitation-id path string true "Invitation id"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id}/invitations/{invitation-id} [delete]
+func (a *InvitationController) RevokeInvitation(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Params.ByName("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	invitationID, err := uuid.Parse(c.Params.ByName("invitation-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.invitationService.RevokeInvitation(companyID, invitationID, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// AcceptInvitation
+// @Summary      Accept invitation
+// @Description  Create the account of the invitee using the token from the invitation email and join the company
+// @Tags         Invitation
+// @Accept       json
+// @Produce      json
+// @Param        payload body   model.AcceptInvitationRequest true "Invitee data"
+// @Success      200  {object}  base.ResponseOKWithID "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      409  {object}  base.ResponseFailure "Email is already taken"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /invitation/accept [post]
+func (a *InvitationController) AcceptInvitation(c *gin.Context) {
+	var payload model.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	id, serviceErr := a.invitationService.Accept(&payload, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOKWithID{
+		Status: http.StatusText(http.StatusOK),
+		ID:     *id,
+	})
+}
+
+// JoinCompany
+// @Summary      Join a company
+// @Description  Accept the invitation sent to the signed in user by a member manager and become a member of the company
+// @Tags         Invitation
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        payload body   model.JoinCompanyRequest true "Invitation token"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      409  {object}  base.ResponseFailure "User is a member of a company already"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /invitation/join [post]
+func (a *InvitationController) JoinCompany(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+
+	var payload model.JoinCompanyRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.invitationService.Join(userID.(uuid.UUID), &payload, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/middleware"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type MembershipController struct {
+	logger            *zap.Logger
+	membershipService *service.MembershipService
+	invitationService *service.InvitationService
+}
+
+func NewMembershipController(
+	logger *zap.Logger,
+	membershipService *service.MembershipService,
+	invitationService *service.InvitationService) *MembershipController {
+	return &MembershipController{
+		logger:            logger,
+		membershipService: membershipService,
+		invitationService: invitationService,
+	}
+}
+
+// GetMembers
+// @Summary      Get members
+// @Description  Get the owner and members of the company with their roles. Only members and member managers can see them
+// @Tags         Membership
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Success      200  {object}  model.GetMembersResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id}/members [get]
+func (a *MembershipController) GetMembers(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+	companyID, err := uuid.Parse(c.Params.ByName("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	members, serviceErr := a.membershipService.GetMembers(userID.(uuid.UUID), companyID, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.GetMembersResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Members: members,
+	})
+}
+
+// AddMember
+// @Summary      Add a member
+// @Description  Invite a registered user, who is not a member of any company, to join the company with a role. The user becomes a member after accepting the emailed invitation
+// @Tags         Membership
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Param        payload body   model.AddMemberRequest true "Member data"
+// @Success      200  {object}  base.ResponseOKWithID "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      409  {object}  base.ResponseFailure "User is a member of a company already"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id}/members [post]
+func (a *MembershipController) AddMember(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+	companyID, err := uuid.Parse(c.Params.ByName("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	var payload model.AddMemberRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	id, serviceErr := a.invitationService.InviteUser(userID.(uuid.UUID), companyID, &payload, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOKWithID{
+		Status: http.StatusText(http.StatusOK),
+		ID:     *id,
+	})
+}
+
+// RemoveMember
+// @Summary      Remove a member
+// @Description  Remove the member from the company and revoke the roles granted within it. The owner can't be removed
+// @Tags         Membership
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Param        user-id path string true "User id"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      409  {object}  base.ResponseFailure "User is the owner"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id}/members/{user-id} [delete]
+func (a *MembershipController) RemoveMember(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Params.ByName("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	userID, err := uuid.Parse(c.Params.ByName("user-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.membershipService.RemoveMember(companyID, userID, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// Leave
+// @Summary      Leave the company
+// @Description  Leave the company, the roles granted within it are revoked. The owner has to transfer the ownership first
+// @Tags         Membership
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      404  {object}  base.ResponseFailure "Not a member"
+// @Failure      409  {object}  base.ResponseFailure "User is the owner"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id}/leave [post]
+func (a *MembershipController) Leave(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+	companyID, err := uuid.Parse(c.Params.ByName("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.membershipService.Leave(userID.(uuid.UUID), companyID, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// TransferOwnership
+// @Summary      Transfer ownership
+// @Description  Make a member the owner of the company. The former owner keeps the requested role or leaves the company
+// @Tags         Membership
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        company-id path string true "Company id"
+// @Param        payload body   model.TransferOwnershipRequest true "New owner"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      409  {object}  base.ResponseFailure "User is not a member"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/{company-id}/owner [post]
+func (a *MembershipController) TransferOwnership(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Params.ByName("company-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	var payload model.TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.membershipService.TransferOwnership(companyID, &payload, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/helpers"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type OIDCController struct {
+	logger      *zap.Logger
+	oidcService *service.OIDCService
+}
+
+func NewOIDCController(logger *zap.Logger, oidcService *service.OIDCService) *OIDCController {
+	return &OIDCController{
+		logger:      logger,
+		oidcService: oidcService,
+	}
+}
+
+// GetProviders
+// @Summary      Get single sign-on providers
+// @Description  Get names of the configured OpenID Connect providers
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  model.GetOIDCProvidersResponse "OK"
+// @Router       /user/login/oidc [get]
+func (a *OIDCController) GetProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, model.GetOIDCProvidersResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Providers: a.oidcService.GetProviders(),
+	})
+}
+
+// Begin
+// @Summary      Start single sign-on
+// @Description  Get the authorization URL of the provider the user has to be redirected to. The provider returns the user to the configured redirect URL with code and state
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        provider path string true "Provider name"
+// @Success      200  {object}  model.OIDCAuthorizationResponse "OK"
+// @Failure      404  {object}  base.ResponseFailure "Unknown provider"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/login/oidc/{provider} [get]
+func (a *OIDCController) Begin(c *gin.Context) {
+	authorizationURL, serviceErr := a.oidcService.Begin(c.Param("provider"), c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.OIDCAuthorizationResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		AuthorizationURL: authorizationURL,
+	})
+}
+
+// Callback
+// @Summary      Finish single sign-on
+// @Description  Exchange code and state returned by the provider for the tokens. Like /user/login it may require a second factor
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        provider path string true "Provider name"
+// @Param        payload body   model.OIDCCallbackRequest true "Code and state"
+// @Success      200  {object}  model.LoginResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      401  {object}  base.ResponseFailure "Sign-on failed"
+// @Failure      403  {object}  base.ResponseFailure "User is not registered"
+// @Failure      404  {object}  base.ResponseFailure "Unknown provider"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/login/oidc/{provider}/callback [post]
+func (a *OIDCController) Callback(c *gin.Context) {
+	var payload model.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	response, serviceErr := a.oidcService.Callback(c.Param("provider"), &payload, helpers.GetClientInfo(c), c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	response.Status = http.StatusText(http.StatusOK)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/middleware"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type RoleController struct {
+	logger      *zap.Logger
+	roleService *service.RoleService
+}
+
+func NewRoleController(logger *zap.Logger, roleService *service.RoleService) *RoleController {
+	return &RoleController{
+		logger:      logger,
+		roleService: roleService,
+	}
+}
+
+// GetRoles
+// @Summary      Get roles
+// @Description  Get roles with their permissions
+// @Tags         Role
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Success      200  {object}  model.GetRolesResponse "OK"
+// @Failure      403  {object}  base.ResponseFailure "No access"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /role [get]
+func (a *RoleController) GetRoles(c *gin.Context) {
+	roles, serviceErr := a.roleService.GetRoles(c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.GetRolesResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Roles: roles,
+	})
+}
+
+// GetUserRoles
+// @Summary      Get roles of the user
+// @Description  Get roles granted to the user, globally and within companies
+// @Tags         Role
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        user-id path string true "User ID"
+// @Success      200  {object}  model.GetRoleGrantsResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "No access"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/{user-id}/roles [get]
+func (a *RoleController) GetUserRoles(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	roles, serviceErr := a.roleService.GetUserRoles(userID, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.GetRoleGrantsResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Roles: roles,
+	})
+}
+
+// GrantRole
+// @Summary      Grant role
+// @Description  Grant a role to the user. Company roles require company_id, platform_admin must not have it
+// @Tags         Role
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        user-id path string true "User ID"
+// @Param        payload body   model.GrantRoleRequest true "Role"
+// @Success      200  {object}  base.ResponseOKWithID "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "No access"
+// @Failure      404  {object}  base.ResponseFailure "User or company not found"
+// @Failure      409  {object}  base.ResponseFailure "Already granted"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/{user-id}/roles [post]
+func (a *RoleController) GrantRole(c *gin.Context) {
+	granterID, _ := c.Get(middleware.UserIDKey)
+
+	userID, err := uuid.Parse(c.Param("user-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	var payload model.GrantRoleRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	id, serviceErr := a.roleService.Grant(granterID.(uuid.UUID), userID, &payload, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOKWithID{
+		ID:     *id,
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// RevokeRole
+// @Summary      Revoke role
+// @Description  Revoke a role of the user. The last platform administrator can't be revoked
+// @Tags         Role
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        user-id path string true "User ID"
+// @Param        role-grant-id path string true "Role grant ID"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "No access"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      409  {object}  base.ResponseFailure "Last platform administrator"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/{user-id}/roles/{role-grant-id} [delete]
+func (a *RoleController) RevokeRole(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	grantID, err := uuid.Parse(c.Param("role-grant-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.roleService.Revoke(userID, grantID, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
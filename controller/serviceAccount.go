@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/middleware"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type ServiceAccountController struct {
+	logger                *zap.Logger
+	serviceAccountService *service.ServiceAccountService
+}
+
+func NewServiceAccountController(logger *zap.Logger, serviceAccountService *service.ServiceAccountService) *ServiceAccountController {
+	return &ServiceAccountController{
+		logger:                logger,
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+// CreateServiceAccount
+// @Summary      Create service account
+// @Description  Create a service account for a machine client. A company id restricts its API keys to that company
+// @Tags         ServiceAccount
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        payload body   model.CreateServiceAccountRequest true "Service account data"
+// @Success      200  {object}  base.ResponseOKWithID "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      404  {object}  base.ResponseFailure "Company not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /service-account [post]
+func (a *ServiceAccountController) CreateServiceAccount(c *gin.Context) {
+	adminID, _ := c.Get(middleware.UserIDKey)
+
+	var payload model.CreateServiceAccountRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	id, serviceErr := a.serviceAccountService.CreateServiceAccount(adminID.(uuid.UUID), &payload, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOKWithID{
+		ID:     *id,
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// GetServiceAccounts
+// @Summary      Get service accounts
+// @Description  Get service accounts with their API keys. Keys themselves are never returned
+// @Tags         ServiceAccount
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Success      200  {object}  model.GetServiceAccountsResponse "OK"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /service-account [get]
+func (a *ServiceAccountController) GetServiceAccounts(c *gin.Context) {
+	accounts, serviceErr := a.serviceAccountService.GetServiceAccounts(c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.GetServiceAccountsResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		ServiceAccounts: accounts,
+	})
+}
+
+// DeleteServiceAccount
+// @Summary      Delete service account
+// @Description  Delete a service account, its API keys stop working immediately
+// @Tags         ServiceAccount
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        service-account-id path string true "Service account id"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /service-account/{service-account-id} [delete]
+func (a *ServiceAccountController) DeleteServiceAccount(c *gin.Context) {
+	serviceAccountID, err := uuid.Parse(c.Param("service-account-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.serviceAccountService.DeleteServiceAccount(serviceAccountID, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// CreateAPIKey
+// @Summary      Create API key
+// @Description  Issue an API key of the service account. The key is shown only once, it is sent as "Authorization: Bearer <key>"
+// @Tags         ServiceAccount
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        service-account-id path string true "Service account id"
+// @Param        payload body   model.CreateAPIKeyRequest true "API key data"
+// @Success      200  {object}  model.CreateAPIKeyResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /service-account/{service-account-id}/keys [post]
+func (a *ServiceAccountController) CreateAPIKey(c *gin.Context) {
+	serviceAccountID, err := uuid.Parse(c.Param("service-account-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	var payload model.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	key, apiKey, serviceErr := a.serviceAccountService.CreateAPIKey(serviceAccountID, &payload, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CreateAPIKeyResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Key:    key,
+		APIKey: *apiKey,
+	})
+}
+
+// RevokeAPIKey
+// @Summary      Revoke API key
+// @Description  Revoke an API key of the service account
+// @Tags         ServiceAccount
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        service-account-id path string true "Service account id"
+// @Param        key-id path string true "API key id"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /service-account/{service-account-id}/keys/{key-id} [delete]
+func (a *ServiceAccountController) RevokeAPIKey(c *gin.Context) {
+	serviceAccountID, err := uuid.Parse(c.Param("service-account-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	keyID, err := uuid.Parse(c.Param("key-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.serviceAccountService.RevokeAPIKey(serviceAccountID, keyID, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
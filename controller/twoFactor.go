@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/middleware"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type TwoFactorController struct {
+	logger           *zap.Logger
+	twoFactorService *service.TwoFactorService
+}
+
+func NewTwoFactorController(logger *zap.Logger, twoFactorService *service.TwoFactorService) *TwoFactorController {
+	return &TwoFactorController{
+		logger:           logger,
+		twoFactorService: twoFactorService,
+	}
+}
+
+// Enroll
+// @Summary      Start two-factor enrollment
+// @Description  Generate a TOTP secret and its provisioning URI for an authenticator app. Two-factor authentication is enabled after confirmation
+// @Tags         TwoFactor
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Success      200  {object}  model.TwoFactorEnrollResponse "OK"
+// @Failure      409  {object}  base.ResponseFailure "Already enabled"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/2fa/enroll [post]
+func (a *TwoFactorController) Enroll(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+
+	response, serviceErr := a.twoFactorService.Enroll(userID.(uuid.UUID), c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Confirm
+// @Summary      Confirm two-factor enrollment
+// @Description  Enable two-factor authentication with a code from the authenticator app. Returns backup codes, they are shown only once
+// @Tags         TwoFactor
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        payload body   model.TwoFactorCodeRequest true "TOTP code"
+// @Success      200  {object}  model.BackupCodesResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      401  {object}  base.ResponseFailure "Invalid code"
+// @Failure      409  {object}  base.ResponseFailure "Enrollment not started"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/2fa/confirm [post]
+func (a *TwoFactorController) Confirm(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+
+	var payload model.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	codes, serviceErr := a.twoFactorService.Confirm(userID.(uuid.UUID), payload.Code, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.BackupCodesResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		BackupCodes: codes,
+	})
+}
+
+// RegenerateBackupCodes
+// @Summary      Regenerate backup codes
+// @Description  Replace backup codes with new ones, the old ones stop working
+// @Tags         TwoFactor
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        payload body   model.TwoFactorCodeRequest true "TOTP or backup code"
+// @Success      200  {object}  model.BackupCodesResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      401  {object}  base.ResponseFailure "Invalid code"
+// @Failure      409  {object}  base.ResponseFailure "Not enabled"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/2fa/backup-codes [post]
+func (a *TwoFactorController) RegenerateBackupCodes(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+
+	var payload model.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	codes, serviceErr := a.twoFactorService.RegenerateBackupCodes(userID.(uuid.UUID), payload.Code, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.BackupCodesResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		BackupCodes: codes,
+	})
+}
+
+// Disable
+// @Summary      Disable two-factor authentication
+// @Description  Disable two-factor authentication, the password and a TOTP or backup code are required
+// @Tags         TwoFactor
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        payload body   model.DisableTwoFactorRequest true "Password and code"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      401  {object}  base.ResponseFailure "Invalid password or code"
+// @Failure      409  {object}  base.ResponseFailure "Not enabled"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/2fa/disable [post]
+func (a *TwoFactorController) Disable(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+
+	var payload model.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.twoFactorService.Disable(userID.(uuid.UUID), &payload, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
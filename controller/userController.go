@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"fmt"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/middleware"
@@ -11,25 +12,32 @@
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"net/http"
+	"strconv"
 )
 
 type UserController struct {
-	logger      *zap.Logger
-	userService *service.UserService
+	logger            *zap.Logger
+	userService       *service.UserService
+	userExportService *service.UserExportService
+	userImportService *service.UserImportService
 }
 
 func NewUserController(
 	logger *zap.Logger,
-	userService *service.UserService) *UserController {
+	userService *service.UserService,
+	userExportService *service.UserExportService,
+	userImportService *service.UserImportService) *UserController {
 	return &UserController{
-		logger:      logger,
-		userService: userService,
+		logger:            logger,
+		userService:       userService,
+		userExportService: userExportService,
+		userImportService: userImportService,
 	}
 }
 
 // Get user-api
 // @Summary      Get all users
-// @Description  Get all users
+// @Description  Get all users. A key of a company service account gets members of its company only
 // @Tags         User
 // @Accept       json
 // @Produce      json
@@ -40,7 +48,7 @@ func NewUserController(
 // @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
 // @Router       /user/get [get]
 func (a *UserController) Get(c *gin.Context) {
-	users, total, serviceErr := a.userService.Get(dataProcessing.GetOptions(c), c)
+	users, total, serviceErr := a.userService.Get(dataProcessing.GetOptions(c), middleware.RestrictedCompanyID(c), c)
 	if serviceErr != nil {
 		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
 		return
@@ -57,7 +65,7 @@ func (a *UserController) Get(c *gin.Context) {
 
 // DeleteUser user-api
 // @Summary      Delete User
-// @Description  Delete User
+// @Description  Delete the user with companies the user owns alone and sign the user out. The user can be restored within the restore window
 // @Tags         User
 // @Accept       json
 // @Produce      json
@@ -66,10 +74,12 @@ func (a *UserController) Get(c *gin.Context) {
 // @Param        user-id path string true "User ID"
 // @Success      200  {object}  base.ResponseOK "OK"
 // @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      409  {object}  base.ResponseFailure "User owns a company with members"
 // @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
 // @Router       /user/{user-id}/delete [delete]
 func (a *UserController) DeleteUser(c *gin.Context) {
-	adminID, _ := c.Get(middleware.AdminsID)
+	adminID, _ := c.Get(middleware.UserIDKey)
 	userID, err := uuid.Parse(c.Params.ByName("user-id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
@@ -161,15 +171,17 @@ func (a *UserController) RetrieveUser(c *gin.Context) {
 
 // GetUserByIdList private-user-api
 // @Summary      Retrieve user information by id list
-// @Description  Retrieve user information by id list
-// @Tags         Users
+// @Description  Retrieve users by id list, unknown ids are skipped. At most 100 ids can be requested at once. A key of a company service account gets members of its company only
+// @Tags         User
 // @Accept       json
 // @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
 // @Param        payload body   model.UsersByIdListRequest true "User data"
 // @Success      200  {object}  model.GetUsersResponse "OK"
 // @Failure      400  {object}  base.ResponseFailure "Bad request"
 // @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
-// @Router       /usersByIdList [post]
+// @Router       /user/lookup [post]
 func (a *UserController) GetUserByIdList(c *gin.Context) {
 	var payload model.UsersByIdListRequest
 	if err := c.ShouldBindJSON(&payload); err != nil {
@@ -180,17 +192,8 @@ func (a *UserController) GetUserByIdList(c *gin.Context) {
 		})
 		return
 	}
-	if len(payload.Ids) == 0 {
-		c.JSON(http.StatusOK, model.GetUsersResponse{
-			ResponseOK: base.ResponseOK{
-				Status: http.StatusText(http.StatusOK),
-			},
-			Total: int64(len(payload.Ids)),
-			Users: []model.UserObject{},
-		})
-	}
 
-	res, serviceErr := a.userService.GetUsersById(payload.Ids, c)
+	res, serviceErr := a.userService.GetUsersById(payload.Ids, middleware.RestrictedCompanyID(c), c)
 	if serviceErr != nil {
 		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
 		return
@@ -199,7 +202,7 @@ func (a *UserController) GetUserByIdList(c *gin.Context) {
 		ResponseOK: base.ResponseOK{
 			Status: http.StatusText(http.StatusOK),
 		},
-		Total: int64(len(payload.Ids)),
+		Total: int64(len(res)),
 		Users: res,
 	})
 }
@@ -228,3 +231,231 @@ func (a *UserController) GetUserById(c *gin.Context) {
 		User: *user,
 	})
 }
+
+// UploadAvatar user-api
+// @Summary      Upload avatar
+// @Description  Upload a picture of the authorised user, it replaces the previous one
+// @Tags         User
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        file formData file true "file"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/avatar [post]
+func (a *UserController) UploadAvatar(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, base.ResponseFailure{
+			Status:  http.StatusText(http.StatusBadRequest),
+			Blame:   base.BlameUser,
+			Message: "failed to read file",
+		})
+		return
+	}
+	defer file.Close()
+
+	if serviceErr := a.userService.UploadAvatar(userID.(uuid.UUID), file, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// DeleteAvatar user-api
+// @Summary      Delete avatar
+// @Description  Delete the picture of the authorised user, the generated one is shown instead
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/avatar [delete]
+func (a *UserController) DeleteAvatar(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+
+	if serviceErr := a.userService.DeleteAvatar(userID.(uuid.UUID), c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// GetDefaultAvatar user-api
+// @Summary      Get generated avatar
+// @Description  Get the PNG picture generated for the user, it is shown until the user uploads one
+// @Tags         User
+// @Produce      png
+// @Param        user-id path string true "User ID"
+// @Success      200  {file}    binary "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/{user-id}/avatar/default [get]
+func (a *UserController) GetDefaultAvatar(c *gin.Context) {
+	userID, err := uuid.Parse(c.Params.ByName("user-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	avatar, serviceErr := a.userService.GetDefaultAvatar(userID)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	// the picture never changes for the user
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Data(http.StatusOK, "image/png", avatar)
+}
+
+// GetDeletedUsers admin-api
+// @Summary      Get deleted users
+// @Description  Get deleted users which can still be restored, with the time they are purged at
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Success      200  {object}  model.GetDeletedUsersResponse "OK"
+// @Failure      403  {object}  base.ResponseFailure "No access"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/deleted [get]
+func (a *UserController) GetDeletedUsers(c *gin.Context) {
+	users, serviceErr := a.userService.GetDeletedUsers(c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.GetDeletedUsersResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Users: users,
+	})
+}
+
+// RestoreUser admin-api
+// @Summary      Restore User
+// @Description  Restore a deleted user together with companies deleted along with the user
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        user-id path string true "User ID"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "No access"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      410  {object}  base.ResponseFailure "Restore window has passed"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/{user-id}/restore [post]
+func (a *UserController) RestoreUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Params.ByName("user-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.userService.RestoreUser(userID, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// ExportData user-api
+// @Summary      Export my data
+// @Description  Download a JSON archive of everything stored about the authorised user
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Success      200  {object}  model.UserExportObject "OK"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/export [get]
+func (a *UserController) ExportData(c *gin.Context) {
+	userID, _ := c.Get(middleware.UserIDKey)
+
+	export, serviceErr := a.userExportService.Export(userID.(uuid.UUID), c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"user-%s.json\"", export.Profile.ID))
+	c.JSON(http.StatusOK, export)
+}
+
+// ImportUsers admin-api
+// @Summary      Import users
+// @Description  Register users listed in a CSV file with name, email and, when importing into a company, role columns. Generated passwords are emailed to the users, they have to change them on the first login. Invalid rows are skipped, a dry run only validates the file
+// @Tags         User
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        file formData file true "CSV file"
+// @Param        company_id query string false "Company the users become members of"
+// @Param        dry_run query bool false "Only validate the file"
+// @Success      200  {object}  model.UserImportResponse "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "No access"
+// @Failure      404  {object}  base.ResponseFailure "Company not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /user/import [post]
+func (a *UserController) ImportUsers(c *gin.Context) {
+	var companyID *uuid.UUID
+	if value := c.Query("company_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+			return
+		}
+		companyID = &id
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, base.ResponseFailure{
+			Status:  http.StatusText(http.StatusBadRequest),
+			Blame:   base.BlameUser,
+			Message: "failed to read file",
+		})
+		return
+	}
+	defer file.Close()
+
+	response, serviceErr := a.userImportService.Import(file, companyID, dryRun, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	response.Status = http.StatusText(http.StatusOK)
+	c.JSON(http.StatusOK, response)
+}
@@ -3,6 +3,7 @@
 import (
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/middleware"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/service"
@@ -38,6 +39,7 @@ func NewVacancyController(
 // @Param        payload body   model.CreateNewVacancyRequest true "User data"
 // @Success      200  {object}  base.ResponseOK "OK"
 // @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Not a member of the company"
 // @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
 // @Router       /vacancy/company/{company-id} [post]
 func (a *VacancyController) CreateVacancy(c *gin.Context) {
@@ -76,7 +78,7 @@ func (a *VacancyController) CreateVacancy(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Security ApiKeyAuth
-// @param Authorization header string true "Authorization"
+// @param Authorization header string false "Authorization"
 // @Param        vacancy-id path string true "Vacancy id"
 // @Success      200  {object}  model.RetrieveVacancyResponse "OK"
 // @Failure      400  {object}  base.ResponseFailure "Bad request"
@@ -89,7 +91,7 @@ func (a *VacancyController) RetrieveVacancy(c *gin.Context) {
 		return
 	}
 
-	vacancy, serviceErr := a.vacancyService.RetrieveVacancy(vacancyId, c)
+	vacancy, serviceErr := a.vacancyService.RetrieveVacancy(vacancyId, middleware.OptionalUserID(c), c)
 	if serviceErr != nil {
 		c.JSON(http.StatusBadRequest, serviceErr)
 		return
@@ -103,6 +105,90 @@ func (a *VacancyController) RetrieveVacancy(c *gin.Context) {
 	})
 }
 
+// UpdateVacancy
+// @Summary      Update Vacancy
+// @Description  Change the details of the open vacancy, omitted fields are kept. A new name gives the vacancy a new slug
+// @Tags         Vacancy
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        vacancy-id path string true "Vacancy id"
+// @Param        payload body   model.UpdateVacancyRequest true "Vacancy data"
+// @Success      200  {object}  base.ResponseOK "OK"
+// @Failure      400  {object}  base.ResponseFailure "Bad request"
+// @Failure      403  {object}  base.ResponseFailure "Forbidden"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      409  {object}  base.ResponseFailure "Vacancy is closed"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /vacancy/{vacancy-id} [patch]
+func (a *VacancyController) UpdateVacancy(c *gin.Context) {
+	vacancyID, err := uuid.Parse(c.Param("vacancy-id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	var payload model.UpdateVacancyRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, api.GeneralParsingError())
+		return
+	}
+
+	if serviceErr := a.vacancyService.UpdateVacancy(vacancyID, &payload, c); serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, base.ResponseOK{
+		Status: http.StatusText(http.StatusOK),
+	})
+}
+
+// RetrieveVacancyBySlug
+// @Summary      Retrieve Vacancy by slug
+// @Description  Retrieve the vacancy by the slugs of its company and itself. Former slugs redirect to the current ones
+// @Tags         Vacancy
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @param Authorization header string false "Authorization"
+// @Param        company-slug path string true "Company slug"
+// @Param        vacancy-slug path string true "Vacancy slug"
+// @Success      200  {object}  model.RetrieveVacancyResponse "OK"
+// @Success      301  "Moved to the current slugs"
+// @Failure      404  {object}  base.ResponseFailure "Not found"
+// @Failure      500  {object}  base.ResponseFailure "Internal error (server fault)"
+// @Router       /company/by-slug/{company-slug}/vacancy/{vacancy-slug} [get]
+func (a *VacancyController) RetrieveVacancyBySlug(c *gin.Context) {
+	companySlug := c.Param("company-slug")
+	vacancySlug := c.Param("vacancy-slug")
+
+	vacancyID, currentCompanySlug, currentVacancySlug, serviceErr := a.vacancyService.GetVacancyIDBySlug(companySlug, vacancySlug, c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	if currentCompanySlug != companySlug || currentVacancySlug != vacancySlug {
+		c.Redirect(http.StatusMovedPermanently, companySlugPath(currentCompanySlug)+"/vacancy/"+currentVacancySlug)
+		return
+	}
+
+	vacancy, serviceErr := a.vacancyService.RetrieveVacancy(vacancyID, middleware.OptionalUserID(c), c)
+	if serviceErr != nil {
+		c.JSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.RetrieveVacancyResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Vacancy: *vacancy,
+	})
+}
+
 // GetVacancy
 // @Summary      Get Vacancy
 // @Description  Get Vacancy
@@ -15,14 +15,34 @@
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/user/field/update": {
-            "post": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Public keys used to verify access tokens issued by this service",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "JSON Web Key Set",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.JWKSet"
+                        }
+                    }
+                }
+            }
+        },
+        "/audit": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update User All Fields",
+                "description": "Get recorded changes with their actor, target, changed fields, client IP and request ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -30,9 +50,9 @@
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Audit"
                 ],
-                "summary": "Update User All Fields",
+                "summary": "Get audit log",
                 "parameters": [
                     {
                         "type": "string",
@@ -40,22 +60,13 @@
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "description": "User data",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.UpdateUserAllFieldRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/base.ResponseOK"
+                            "$ref": "#/definitions/model.GetAuditEventsResponse"
                         }
                     },
                     "400": {
@@ -64,6 +75,12 @@
                             "$ref": "#/definitions/base.ResponseFailure"
                         }
                     },
+                    "403": {
+                        "description": "No access",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
                     "500": {
                         "description": "Internal error (server fault)",
                         "schema": {
@@ -73,14 +90,14 @@
                 }
             }
         },
-        "/user/get": {
+        "/candidate": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get all users",
+                "description": "Get Candidates of the companies in which the user may read candidates",
                 "consumes": [
                     "application/json"
                 ],
@@ -88,9 +105,9 @@
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Candidate"
                 ],
-                "summary": "Get all users",
+                "summary": "Get Candidates",
                 "parameters": [
                     {
                         "type": "string",
@@ -104,7 +121,7 @@
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.GetUsersResponse"
+                            "$ref": "#/definitions/model.GetCandidatesResponse"
                         }
                     },
                     "400": {
@@ -122,9 +139,14 @@
                 }
             }
         },
-        "/user/login": {
+        "/candidate/vacancy/{vacancy-id}": {
             "post": {
-                "description": "User authorisation",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create Candidate",
                 "consumes": [
                     "application/json"
                 ],
@@ -132,17 +154,31 @@
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Candidate"
                 ],
-                "summary": "User authorisation",
+                "summary": "Create Candidate",
                 "parameters": [
                     {
-                        "description": "User request",
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Vacancy id",
+                        "name": "vacancy-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User data",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.LoginRequest"
+                            "$ref": "#/definitions/model.AddNewCandidateRequest"
                         }
                     }
                 ],
@@ -150,7 +186,7 @@
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.LoginResponse"
+                            "$ref": "#/definitions/base.ResponseOK"
                         }
                     },
                     "400": {
@@ -159,6 +195,12 @@
                             "$ref": "#/definitions/base.ResponseFailure"
                         }
                     },
+                    "403": {
+                        "description": "Not a member of the company",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
                     "500": {
                         "description": "Internal error (server fault)",
                         "schema": {
@@ -168,14 +210,14 @@
                 }
             }
         },
-        "/user/logout": {
-            "post": {
+        "/candidate/{candidate-id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Unauthorized users",
+                "description": "Retrieve Candidate. Name and email are hidden unless the user may read candidates of the company.",
                 "consumes": [
                     "application/json"
                 ],
@@ -183,32 +225,29 @@
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Candidate"
                 ],
-                "summary": "Unauthorized users",
+                "summary": "Retrieve Candidate",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Authorization",
                         "name": "Authorization",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     },
                     {
-                        "description": "User request",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.RecreateJWTRequest"
-                        }
+                        "type": "string",
+                        "description": "Candidate id",
+                        "name": "candidate-id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/base.ResponseOK"
+                            "$ref": "#/definitions/model.RetrieveCandidateResponse"
                         }
                     },
                     "400": {
@@ -226,9 +265,14 @@
                 }
             }
         },
-        "/user/refresh": {
-            "post": {
-                "description": "Re-create refresh token",
+        "/company": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get Company",
                 "consumes": [
                     "application/json"
                 ],
@@ -236,25 +280,28 @@
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Company"
                 ],
-                "summary": "Re-create refresh token",
+                "summary": "Get Company",
                 "parameters": [
                     {
-                        "description": "User request",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.RecreateJWTRequest"
-                        }
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated children to load: users, vacancies, candidates. None by default",
+                        "name": "include",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.LoginResponse"
+                            "$ref": "#/definitions/model.GetCompanyResponse"
                         }
                     },
                     "400": {
@@ -270,16 +317,14 @@
                         }
                     }
                 }
-            }
-        },
-        "/user/register": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "User registration",
+                "description": "Create Company",
                 "consumes": [
                     "application/json"
                 ],
@@ -287,40 +332,110 @@
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Company"
                 ],
-                "summary": "User registration",
+                "summary": "Create Company",
                 "parameters": [
                     {
-                        "description": "User request",
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "User data",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.RegisterRequest"
+                            "$ref": "#/definitions/model.CreateCompanyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
                         }
                     },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/by-slug/{company-slug}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve the company by its slug. A former slug redirects to the current one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company"
+                ],
+                "summary": "Retrieve Company by slug",
+                "parameters": [
                     {
                         "type": "string",
                         "description": "Authorization",
                         "name": "Authorization",
-                        "in": "header",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company slug",
+                        "name": "company-slug",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated children to load: users, vacancies, candidates. All of them by default",
+                        "name": "include",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/base.ResponseOKWithID"
+                            "$ref": "#/definitions/model.RetrieveCompanyResponse"
                         }
                     },
+                    "301": {
+                        "description": "Moved to the current slug"
+                    },
                     "400": {
                         "description": "Bad request",
                         "schema": {
                             "$ref": "#/definitions/base.ResponseFailure"
                         }
                     },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
                     "500": {
                         "description": "Internal error (server fault)",
                         "schema": {
@@ -330,14 +445,14 @@
                 }
             }
         },
-        "/user/retrieve": {
+        "/company/by-slug/{company-slug}/vacancy/{vacancy-slug}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve data of an authorised user",
+                "description": "Retrieve the vacancy by the slugs of its company and itself. Former slugs redirect to the current ones",
                 "consumes": [
                     "application/json"
                 ],
@@ -345,15 +460,28 @@
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Vacancy"
                 ],
-                "summary": "Retrieve data of an authorised user",
+                "summary": "Retrieve Vacancy by slug",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Authorization",
                         "name": "Authorization",
-                        "in": "header",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company slug",
+                        "name": "company-slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Vacancy slug",
+                        "name": "vacancy-slug",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -361,11 +489,14 @@
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.GetUserResponse"
+                            "$ref": "#/definitions/model.RetrieveVacancyResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "301": {
+                        "description": "Moved to the current slugs"
+                    },
+                    "404": {
+                        "description": "Not found",
                         "schema": {
                             "$ref": "#/definitions/base.ResponseFailure"
                         }
@@ -379,14 +510,14 @@
                 }
             }
         },
-        "/user/{user-id}/delete": {
-            "delete": {
+        "/company/{company-id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete User",
+                "description": "Retrieve Company",
                 "consumes": [
                     "application/json"
                 ],
@@ -394,30 +525,35 @@
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Company"
                 ],
-                "summary": "Delete User",
+                "summary": "Retrieve Company",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Authorization",
                         "name": "Authorization",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     },
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user-id",
+                        "description": "Company id",
+                        "name": "company-id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated children to load: users, vacancies, candidates. All of them by default",
+                        "name": "include",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/base.ResponseOK"
+                            "$ref": "#/definitions/model.RetrieveCompanyResponse"
                         }
                     },
                     "400": {
@@ -433,11 +569,14 @@
                         }
                     }
                 }
-            }
-        },
-        "/usersByIdList": {
-            "post": {
-                "description": "Retrieve user information by id list",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Archive the company: it is hidden, its vacancies are closed, pending invitations deleted and the logo removed. Members keep reading candidates until the company is purged after the retention period",
                 "consumes": [
                     "application/json"
                 ],
@@ -445,25 +584,30 @@
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Company"
                 ],
-                "summary": "Retrieve user information by id list",
+                "summary": "Archive Company",
                 "parameters": [
                     {
-                        "description": "User data",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.UsersByIdListRequest"
-                        }
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.GetUsersResponse"
+                            "$ref": "#/definitions/base.ResponseOK"
                         }
                     },
                     "400": {
@@ -472,6 +616,18 @@
                             "$ref": "#/definitions/base.ResponseFailure"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
                     "500": {
                         "description": "Internal error (server fault)",
                         "schema": {
@@ -479,13 +635,3929 @@
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "base.Blame": {
-            "type": "string",
-            "enum": [
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Change the name and description of the company, omitted fields are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company"
+                ],
+                "summary": "Update Company",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Company data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateCompanyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{company-id}/documents": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get documents of the company, the newest first. Download URLs are given only by the document retrieval",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company document"
+                ],
+                "summary": "Get documents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetCompanyDocumentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Upload a PDF, DOCX or XLSX file as a document of the company. The title defaults to the file name",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company document"
+                ],
+                "summary": "Upload a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Document file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "contract",
+                            "nda",
+                            "requisites",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "Document type",
+                        "name": "type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Title",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Valid from, YYYY-MM-DD",
+                        "name": "valid_from",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Valid until, YYYY-MM-DD",
+                        "name": "valid_until",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOKWithID"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Company not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "413": {
+                        "description": "File is too large",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{company-id}/documents/{document-id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve the document of the company with a presigned download URL",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company document"
+                ],
+                "summary": "Retrieve a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document id",
+                        "name": "document-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RetrieveCompanyDocumentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete the document of the company together with its file",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company document"
+                ],
+                "summary": "Delete a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document id",
+                        "name": "document-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{company-id}/invitations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get pending invitations of the company. Requires the permission to manage members",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitation"
+                ],
+                "summary": "Get pending invitations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetInvitationsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Send an invitation into the company with a role. Requires the permission to manage members",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitation"
+                ],
+                "summary": "Invite a member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Invitation data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateInvitationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOKWithID"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "User is already registered",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{company-id}/invitations/{invitation-id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revoke a pending invitation of the company. Requires the permission to manage members",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitation"
+                ],
+                "summary": "Revoke invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invitation id",
+                        "name": "invitation-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{company-id}/leave": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Leave the company, the roles granted within it are revoked. The owner has to transfer the ownership first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Membership"
+                ],
+                "summary": "Leave the company",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not a member",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "User is the owner",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{company-id}/logo": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Upload Logo",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company"
+                ],
+                "summary": "Upload Logo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a member of the company",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{company-id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the owner and members of the company with their roles. Only members and member managers can see them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Membership"
+                ],
+                "summary": "Get members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetMembersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Invite a registered user, who is not a member of any company, to join the company with a role. The user becomes a member after accepting the emailed invitation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Membership"
+                ],
+                "summary": "Add a member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Member data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AddMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOKWithID"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "User is a member of a company already",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{company-id}/members/{user-id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove the member from the company and revoke the roles granted within it. The owner can't be removed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Membership"
+                ],
+                "summary": "Remove a member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User id",
+                        "name": "user-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "User is the owner",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{company-id}/owner": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Make a member the owner of the company. The former owner keeps the requested role or leaves the company",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Membership"
+                ],
+                "summary": "Transfer ownership",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New owner",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TransferOwnershipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "User is not a member",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{company-id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get hiring numbers of the company: vacancies, candidates per vacancy, new candidates in the last 7 and 30 days, average days since application and the salary range of open vacancies",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company"
+                ],
+                "summary": "Get Company stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetCompanyStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitation/accept": {
+            "post": {
+                "description": "Create the account of the invitee using the token from the invitation email and join the company",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitation"
+                ],
+                "summary": "Accept invitation",
+                "parameters": [
+                    {
+                        "description": "Invitee data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AcceptInvitationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOKWithID"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "Email is already taken",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitation/join": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accept the invitation sent to the signed in user by a member manager and become a member of the company",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitation"
+                ],
+                "summary": "Join a company",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Invitation token",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.JoinCompanyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "User is a member of a company already",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/role": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get roles with their permissions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Get roles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetRolesResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "No access",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/service-account": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get service accounts with their API keys. Keys themselves are never returned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ServiceAccount"
+                ],
+                "summary": "Get service accounts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetServiceAccountsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a service account for a machine client. A company id restricts its API keys to that company",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ServiceAccount"
+                ],
+                "summary": "Create service account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Service account data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateServiceAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOKWithID"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Company not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/service-account/{service-account-id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a service account, its API keys stop working immediately",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ServiceAccount"
+                ],
+                "summary": "Delete service account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service account id",
+                        "name": "service-account-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/service-account/{service-account-id}/keys": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Issue an API key of the service account. The key is shown only once, it is sent as \"Authorization: Bearer \u003ckey\u003e\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ServiceAccount"
+                ],
+                "summary": "Create API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service account id",
+                        "name": "service-account-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "API key data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateAPIKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/service-account/{service-account-id}/keys/{key-id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revoke an API key of the service account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ServiceAccount"
+                ],
+                "summary": "Revoke API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service account id",
+                        "name": "service-account-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key id",
+                        "name": "key-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa/backup-codes": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replace backup codes with new ones, the old ones stop working",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TwoFactor"
+                ],
+                "summary": "Regenerate backup codes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "TOTP or backup code",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BackupCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid code",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "Not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Enable two-factor authentication with a code from the authenticator app. Returns backup codes, they are shown only once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TwoFactor"
+                ],
+                "summary": "Confirm two-factor enrollment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "TOTP code",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BackupCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid code",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "Enrollment not started",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Disable two-factor authentication, the password and a TOTP or backup code are required",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TwoFactor"
+                ],
+                "summary": "Disable two-factor authentication",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Password and code",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DisableTwoFactorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid password or code",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "Not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Generate a TOTP secret and its provisioning URI for an authenticator app. Two-factor authentication is enabled after confirmation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TwoFactor"
+                ],
+                "summary": "Start two-factor enrollment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TwoFactorEnrollResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/avatar": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Upload a picture of the authorised user, it replaces the previous one",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Upload avatar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete the picture of the authorised user, the generated one is shown instead",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete avatar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/deleted": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get deleted users which can still be restored, with the time they are purged at",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get deleted users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetDeletedUsersResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "No access",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/email/verify": {
+            "post": {
+                "description": "Confirm email using the token from the verification email. A pending email change takes effect",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Confirm email",
+                "parameters": [
+                    {
+                        "description": "User request",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "Email is already taken",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/email/verify/resend": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Send the confirmation link again, to the pending email if an email change was requested",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Resend email confirmation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "409": {
+                        "description": "Email is already verified",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Download a JSON archive of everything stored about the authorised user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Export my data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.UserExportObject"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/field/update": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update User All Fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update User All Fields",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "User data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateUserAllFieldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/get": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get all users. A key of a company service account gets members of its company only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get all users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetUsersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Register users listed in a CSV file with name, email and, when importing into a company, role columns. Generated passwords are emailed to the users, they have to change them on the first login. Invalid rows are skipped, a dry run only validates the file",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Import users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company the users become members of",
+                        "name": "company_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the file",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.UserImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "No access",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Company not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/lockouts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get temporary lockouts of accounts and client IPs caused by repeated failed logins",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get login lockouts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetLockoutEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login": {
+            "post": {
+                "description": "User authorisation. With two-factor authentication on, only a challenge token is returned, see /user/login/2fa. A generated password has to be changed first, see /user/login/password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User authorisation",
+                "parameters": [
+                    {
+                        "description": "User request",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid email or password",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "423": {
+                        "description": "Account is temporarily locked",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many login attempts",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login/2fa": {
+            "post": {
+                "description": "Exchange the challenge token from /user/login and a TOTP or backup code for the tokens",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Two-factor authorisation",
+                "parameters": [
+                    {
+                        "description": "User request",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TwoFactorLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid challenge or code",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login/oidc": {
+            "get": {
+                "description": "Get names of the configured OpenID Connect providers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get single sign-on providers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetOIDCProvidersResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login/oidc/{provider}": {
+            "get": {
+                "description": "Get the authorization URL of the provider the user has to be redirected to. The provider returns the user to the configured redirect URL with code and state",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Start single sign-on",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.OIDCAuthorizationResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown provider",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login/oidc/{provider}/callback": {
+            "post": {
+                "description": "Exchange code and state returned by the provider for the tokens. Like /user/login it may require a second factor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Finish single sign-on",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Code and state",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.OIDCCallbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "401": {
+                        "description": "Sign-on failed",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not registered",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown provider",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login/password": {
+            "post": {
+                "description": "Exchange the challenge token from /user/login and a new password for the tokens. Users with a generated password have to change it on the first login. With two-factor authentication on, a two-factor challenge is returned instead",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Change generated password",
+                "parameters": [
+                    {
+                        "description": "User request",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PasswordChangeLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid challenge",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/logout": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Unauthorized users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Unauthorized users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "User request",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RecreateJWTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/lookup": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve users by id list, unknown ids are skipped. At most 100 ids can be requested at once. A key of a company service account gets members of its company only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Retrieve user information by id list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "User data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UsersByIdListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetUsersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/password/forgot": {
+            "post": {
+                "description": "Send a one-time password reset link to the email. The response is the same whether the email is registered or not. A link is sent at most once per cooldown",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Request password reset",
+                "parameters": [
+                    {
+                        "description": "User request",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ForgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/password/reset": {
+            "post": {
+                "description": "Set a new password using the token from the reset email. All sessions of the user are signed out",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Confirm password reset",
+                "parameters": [
+                    {
+                        "description": "User request",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/refresh": {
+            "post": {
+                "description": "Re-create refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Re-create refresh token",
+                "parameters": [
+                    {
+                        "description": "User request",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RecreateJWTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/register": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "User registration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User registration",
+                "parameters": [
+                    {
+                        "description": "User request",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RegisterRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOKWithID"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/retrieve": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve data of an authorised user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Retrieve data of an authorised user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get active sessions of the current user, the one the request was made with is marked as current",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get active sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetSessionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revoke every session of the current user except the one the request was made with",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Revoke all sessions except current",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/sessions/{session-id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revoke session of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Revoke session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "session-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user-id}/avatar/default": {
+            "get": {
+                "description": "Get the PNG picture generated for the user, it is shown until the user uploads one",
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get generated avatar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user-id}/delete": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete the user with companies the user owns alone and sign the user out. The user can be restored within the restore window",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "User owns a company with members",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user-id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a deleted user together with companies deleted along with the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Restore User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "No access",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "410": {
+                        "description": "Restore window has passed",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user-id}/roles": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get roles granted to the user, globally and within companies",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Get roles of the user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetRoleGrantsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "No access",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Grant a role to the user. Company roles require company_id, platform_admin must not have it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Grant role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.GrantRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOKWithID"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "No access",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "User or company not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "Already granted",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user-id}/roles/{role-grant-id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revoke a role of the user. The last platform administrator can't be revoked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Revoke role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role grant ID",
+                        "name": "role-grant-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "No access",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "Last platform administrator",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/vacancy": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get Vacancy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vacancy"
+                ],
+                "summary": "Get Vacancy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetVacancyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/vacancy/company/{company-id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create Vacancy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vacancy"
+                ],
+                "summary": "Create Vacancy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company id",
+                        "name": "company-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateNewVacancyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a member of the company",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        },
+        "/vacancy/{vacancy-id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve Vacancy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vacancy"
+                ],
+                "summary": "Retrieve Vacancy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Vacancy id",
+                        "name": "vacancy-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RetrieveVacancyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Change the details of the open vacancy, omitted fields are kept. A new name gives the vacancy a new slug",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vacancy"
+                ],
+                "summary": "Update Vacancy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Vacancy id",
+                        "name": "vacancy-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Vacancy data",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateVacancyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "409": {
+                        "description": "Vacancy is closed",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error (server fault)",
+                        "schema": {
+                            "$ref": "#/definitions/base.ResponseFailure"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auth.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.JWKSet": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.JWK"
+                    }
+                }
+            }
+        },
+        "base.Blame": {
+            "type": "string",
+            "enum": [
                 "User",
                 "Postgres",
                 "S3",
@@ -502,42 +4574,1403 @@
                 "BlameMail"
             ]
         },
-        "base.ResponseFailure": {
+        "base.ResponseFailure": {
+            "type": "object",
+            "properties": {
+                "blame": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/base.Blame"
+                        }
+                    ],
+                    "example": "Guilty System"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "error occurred"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "Error"
+                }
+            }
+        },
+        "base.ResponseOK": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "base.ResponseOKWithID": {
+            "type": "object",
+            "properties": {
+                "ID": {
+                    "type": "string",
+                    "example": "12345678-1234-1234-1234-000000000000"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "enum.APIScope": {
+            "type": "string",
+            "enum": [
+                "users:read",
+                "companies:read",
+                "companies:write",
+                "vacancies:read",
+                "vacancies:write",
+                "candidates:read",
+                "candidates:write"
+            ],
+            "x-enum-varnames": [
+                "ScopeUsersRead",
+                "ScopeCompaniesRead",
+                "ScopeCompaniesWrite",
+                "ScopeVacanciesRead",
+                "ScopeVacanciesWrite",
+                "ScopeCandidatesRead",
+                "ScopeCandidatesWrite"
+            ]
+        },
+        "enum.AuditAction": {
+            "type": "string",
+            "enum": [
+                "user.register",
+                "user.update",
+                "user.delete",
+                "user.restore",
+                "user.purge",
+                "user.import",
+                "user.password_reset",
+                "user.password_change",
+                "user.email_verify",
+                "user.avatar_upload",
+                "user.avatar_delete",
+                "user.provision",
+                "identity.link",
+                "session.revoke",
+                "two_factor.enable",
+                "two_factor.disable",
+                "two_factor.backup_codes",
+                "company.create",
+                "company.update",
+                "company.archive",
+                "company.purge",
+                "company.logo_upload",
+                "document.upload",
+                "document.delete",
+                "company.member_add",
+                "company.member_remove",
+                "company.member_leave",
+                "company.ownership_transfer",
+                "vacancy.create",
+                "vacancy.update",
+                "candidate.create",
+                "invitation.create",
+                "invitation.revoke",
+                "invitation.accept",
+                "role.grant",
+                "role.revoke",
+                "service_account.create",
+                "service_account.delete",
+                "api_key.create",
+                "api_key.revoke"
+            ],
+            "x-enum-varnames": [
+                "AuditUserRegister",
+                "AuditUserUpdate",
+                "AuditUserDelete",
+                "AuditUserRestore",
+                "AuditUserPurge",
+                "AuditUserImport",
+                "AuditUserPasswordReset",
+                "AuditUserPasswordChange",
+                "AuditUserEmailVerify",
+                "AuditUserAvatarUpload",
+                "AuditUserAvatarDelete",
+                "AuditUserProvision",
+                "AuditIdentityLink",
+                "AuditSessionRevoke",
+                "AuditTwoFactorEnable",
+                "AuditTwoFactorDisable",
+                "AuditTwoFactorBackupCodes",
+                "AuditCompanyCreate",
+                "AuditCompanyUpdate",
+                "AuditCompanyArchive",
+                "AuditCompanyPurge",
+                "AuditCompanyLogoUpload",
+                "AuditDocumentUpload",
+                "AuditDocumentDelete",
+                "AuditMemberAdd",
+                "AuditMemberRemove",
+                "AuditMemberLeave",
+                "AuditOwnershipTransfer",
+                "AuditVacancyCreate",
+                "AuditVacancyUpdate",
+                "AuditCandidateCreate",
+                "AuditInvitationCreate",
+                "AuditInvitationRevoke",
+                "AuditInvitationAccept",
+                "AuditRoleGrant",
+                "AuditRoleRevoke",
+                "AuditServiceAccountCreate",
+                "AuditServiceAccountDelete",
+                "AuditAPIKeyCreate",
+                "AuditAPIKeyRevoke"
+            ]
+        },
+        "enum.AuditActor": {
+            "type": "string",
+            "enum": [
+                "user",
+                "service_account",
+                "anonymous"
+            ],
+            "x-enum-varnames": [
+                "AuditActorUser",
+                "AuditActorServiceAccount",
+                "AuditActorAnonymous"
+            ]
+        },
+        "enum.AuditTarget": {
+            "type": "string",
+            "enum": [
+                "user",
+                "session",
+                "company",
+                "document",
+                "vacancy",
+                "candidate",
+                "invitation",
+                "role_grant",
+                "service_account",
+                "api_key"
+            ],
+            "x-enum-varnames": [
+                "AuditTargetUser",
+                "AuditTargetSession",
+                "AuditTargetCompany",
+                "AuditTargetDocument",
+                "AuditTargetVacancy",
+                "AuditTargetCandidate",
+                "AuditTargetInvitation",
+                "AuditTargetRoleGrant",
+                "AuditTargetServiceAccount",
+                "AuditTargetAPIKey"
+            ]
+        },
+        "enum.DocumentType": {
+            "type": "string",
+            "enum": [
+                "contract",
+                "nda",
+                "requisites",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "DocumentContract",
+                "DocumentNDA",
+                "DocumentRequisites",
+                "DocumentOther"
+            ]
+        },
+        "enum.Permission": {
+            "type": "string",
+            "enum": [
+                "users:manage",
+                "roles:manage",
+                "service_accounts:manage",
+                "lockouts:read",
+                "audit:read",
+                "company:manage",
+                "members:manage",
+                "vacancies:write",
+                "candidates:read",
+                "candidates:write",
+                "documents:read",
+                "documents:write"
+            ],
+            "x-enum-varnames": [
+                "PermissionManageUsers",
+                "PermissionManageRoles",
+                "PermissionManageServiceAccounts",
+                "PermissionReadLockouts",
+                "PermissionReadAuditLog",
+                "PermissionManageCompany",
+                "PermissionManageMembers",
+                "PermissionWriteVacancies",
+                "PermissionReadCandidates",
+                "PermissionWriteCandidates",
+                "PermissionReadDocuments",
+                "PermissionWriteDocuments"
+            ]
+        },
+        "enum.Role": {
+            "type": "string",
+            "enum": [
+                "platform_admin",
+                "company_owner",
+                "recruiter",
+                "hiring_manager",
+                "viewer"
+            ],
+            "x-enum-varnames": [
+                "RolePlatformAdmin",
+                "RoleCompanyOwner",
+                "RoleRecruiter",
+                "RoleHiringManager",
+                "RoleViewer"
+            ]
+        },
+        "enum.ThrottleScope": {
+            "type": "string",
+            "enum": [
+                "account",
+                "ip"
+            ],
+            "x-enum-varnames": [
+                "ThrottleAccount",
+                "ThrottleIP"
+            ]
+        },
+        "enum.UserImportStatus": {
+            "type": "string",
+            "enum": [
+                "valid",
+                "invalid",
+                "created",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "UserImportValid",
+                "UserImportInvalid",
+                "UserImportCreated",
+                "UserImportFailed"
+            ]
+        },
+        "model.APIKeyObject": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/enum.APIScope"
+                    }
+                }
+            }
+        },
+        "model.AcceptInvitationRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AddMemberRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "enum": [
+                        "recruiter",
+                        "hiring_manager",
+                        "viewer"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enum.Role"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AddNewCandidateRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AuditChangeObject": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {}
+            }
+        },
+        "model.AuditEventObject": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/enum.AuditAction"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_type": {
+                    "$ref": "#/definitions/enum.AuditActor"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.AuditChangeObject"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "$ref": "#/definitions/enum.AuditTarget"
+                }
+            }
+        },
+        "model.BackupCodesResponse": {
+            "type": "object",
+            "properties": {
+                "backup_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.CandidateObject": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "personal_data_hidden": {
+                    "description": "PersonalDataHidden is set when name and email are hidden from the viewer",
+                    "type": "boolean"
+                },
+                "system_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CompanyDocumentObject": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "download_url": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "file_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/enum.DocumentType"
+                },
+                "uploaded_by": {
+                    "type": "string"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CompanyObject": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "logoRenditions": {
+                    "description": "LogoRenditions are URLs of the logo sizes and LogoSrcSet lists them for the img srcset attribute.\nLogos uploaded before the sizes were introduced have the LogoURL only.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "logoSrcSet": {
+                    "type": "string"
+                },
+                "logoUrl": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.UserObject"
+                    }
+                },
+                "vacancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.VacancyObject"
+                    }
+                }
+            }
+        },
+        "model.CompanyStatsObject": {
+            "type": "object",
+            "properties": {
+                "average_days_since_application": {
+                    "type": "number"
+                },
+                "candidates": {
+                    "type": "integer"
+                },
+                "closed_vacancies": {
+                    "type": "integer"
+                },
+                "new_candidates_30_days": {
+                    "type": "integer"
+                },
+                "new_candidates_7_days": {
+                    "type": "integer"
+                },
+                "open_vacancies": {
+                    "type": "integer"
+                },
+                "salary": {
+                    "$ref": "#/definitions/model.SalaryRangeObject"
+                },
+                "vacancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.VacancyStatsObject"
+                    }
+                }
+            }
+        },
+        "model.CreateAPIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "enum": [
+                            "users:read",
+                            "companies:read",
+                            "companies:write",
+                            "vacancies:read",
+                            "vacancies:write",
+                            "candidates:read",
+                            "candidates:write"
+                        ],
+                        "$ref": "#/definitions/enum.APIScope"
+                    }
+                }
+            }
+        },
+        "model.CreateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "$ref": "#/definitions/model.APIKeyObject"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.CreateCompanyRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateInvitationRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "role": {
+                    "enum": [
+                        "recruiter",
+                        "hiring_manager",
+                        "viewer"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enum.Role"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.CreateNewVacancyRequest": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "salary": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CreateServiceAccountRequest": {
+            "type": "object",
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeletedUserObject": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "purge_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DisableTwoFactorRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ExternalIdentityObject": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "last_login_at": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ForgotPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GetAuditEventsResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AuditEventObject"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.GetCandidatesResponse": {
+            "type": "object",
+            "properties": {
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CandidateObject"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.GetCompanyDocumentsResponse": {
+            "type": "object",
+            "properties": {
+                "documents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CompanyDocumentObject"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.GetCompanyResponse": {
+            "type": "object",
+            "properties": {
+                "companies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CompanyObject"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.GetCompanyStatsResponse": {
+            "type": "object",
+            "properties": {
+                "stats": {
+                    "$ref": "#/definitions/model.CompanyStatsObject"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.GetDeletedUsersResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DeletedUserObject"
+                    }
+                }
+            }
+        },
+        "model.GetInvitationsResponse": {
+            "type": "object",
+            "properties": {
+                "invitations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.InvitationObject"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.GetLockoutEventsResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LockoutEventObject"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.GetMembersResponse": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.MemberObject"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.GetOIDCProvidersResponse": {
+            "type": "object",
+            "properties": {
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.GetRoleGrantsResponse": {
+            "type": "object",
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RoleGrantObject"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.GetRolesResponse": {
+            "type": "object",
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RoleObject"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.GetServiceAccountsResponse": {
+            "type": "object",
+            "properties": {
+                "service_accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ServiceAccountObject"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.GetSessionsResponse": {
+            "type": "object",
+            "properties": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SessionObject"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.GetUserResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.UserObject"
+                }
+            }
+        },
+        "model.GetUsersResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.UserObject"
+                    }
+                }
+            }
+        },
+        "model.GetVacancyResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                },
+                "vacancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.VacancyObject"
+                    }
+                }
+            }
+        },
+        "model.GrantRoleRequest": {
+            "type": "object",
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "role": {
+                    "enum": [
+                        "platform_admin",
+                        "company_owner",
+                        "recruiter",
+                        "hiring_manager",
+                        "viewer"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enum.Role"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.InvitationObject": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invited_by": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/enum.Role"
+                }
+            }
+        },
+        "model.JoinCompanyRequest": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LockoutEventObject": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "failures": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "locked_until": {
+                    "type": "string"
+                },
+                "scope": {
+                    "$ref": "#/definitions/enum.ThrottleScope"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "password_change_required": {
+                    "type": "boolean"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "two_factor_required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.MemberObject": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_owner": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/enum.Role"
+                    }
+                }
+            }
+        },
+        "model.OIDCAuthorizationResponse": {
+            "type": "object",
+            "properties": {
+                "authorization_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.OIDCCallbackRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.OwnedCompanyExportObject": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PasswordChangeLoginRequest": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RecreateJWTRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RegisterRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ResetPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RetrieveCandidateResponse": {
+            "type": "object",
+            "properties": {
+                "candidate": {
+                    "$ref": "#/definitions/model.CandidateObject"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.RetrieveCompanyDocumentResponse": {
+            "type": "object",
+            "properties": {
+                "document": {
+                    "$ref": "#/definitions/model.CompanyDocumentObject"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.RetrieveCompanyResponse": {
+            "type": "object",
+            "properties": {
+                "company": {
+                    "$ref": "#/definitions/model.CompanyObject"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.RetrieveVacancyResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                },
+                "vacancy": {
+                    "$ref": "#/definitions/model.VacancyObject"
+                }
+            }
+        },
+        "model.RoleGrantObject": {
+            "type": "object",
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "granted_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/enum.Role"
+                }
+            }
+        },
+        "model.RoleObject": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "$ref": "#/definitions/enum.Role"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/enum.Permission"
+                    }
+                }
+            }
+        },
+        "model.SalaryRangeObject": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "integer"
+                },
+                "min": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ServiceAccountObject": {
+            "type": "object",
+            "properties": {
+                "api_keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.APIKeyObject"
+                    }
+                },
+                "company_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.SessionObject": {
             "type": "object",
             "properties": {
-                "blame": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TransferOwnershipRequest": {
+            "type": "object",
+            "properties": {
+                "former_owner_role": {
+                    "description": "FormerOwnerRole is the role the former owner keeps in the company, the former owner leaves it if empty.",
+                    "enum": [
+                        "recruiter",
+                        "hiring_manager",
+                        "viewer"
+                    ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/base.Blame"
+                            "$ref": "#/definitions/enum.Role"
                         }
-                    ],
-                    "example": "Guilty System"
+                    ]
                 },
-                "message": {
-                    "type": "string",
-                    "example": "error occurred"
-                },
-                "status": {
-                    "type": "string",
-                    "example": "Error"
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "base.ResponseOK": {
+        "model.TwoFactorCodeRequest": {
             "type": "object",
             "properties": {
-                "status": {
-                    "type": "string",
-                    "example": "OK"
+                "code": {
+                    "type": "string"
                 }
             }
         },
-        "base.ResponseOKWithID": {
+        "model.TwoFactorEnrollResponse": {
             "type": "object",
             "properties": {
-                "ID": {
-                    "type": "string",
-                    "example": "12345678-1234-1234-1234-000000000000"
+                "provisioning_uri": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
                 },
                 "status": {
                     "type": "string",
@@ -545,116 +5978,241 @@
                 }
             }
         },
-        "model.GetUserResponse": {
+        "model.TwoFactorLoginRequest": {
             "type": "object",
             "properties": {
-                "status": {
-                    "type": "string",
-                    "example": "OK"
+                "challenge_token": {
+                    "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/model.UserObject"
+                "code": {
+                    "description": "Code is a TOTP code or a backup code.",
+                    "type": "string"
                 }
             }
         },
-        "model.GetUsersResponse": {
+        "model.UpdateCompanyRequest": {
             "type": "object",
             "properties": {
-                "status": {
-                    "type": "string",
-                    "example": "OK"
-                },
-                "total": {
-                    "type": "integer"
+                "description": {
+                    "type": "string"
                 },
-                "users": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.UserObject"
-                    }
+                "name": {
+                    "type": "string"
                 }
             }
         },
-        "model.LoginRequest": {
+        "model.UpdateUserAllFieldRequest": {
             "type": "object",
             "properties": {
                 "email": {
                     "type": "string"
                 },
+                "fio": {
+                    "type": "string"
+                },
                 "password": {
                     "type": "string"
                 }
             }
         },
-        "model.LoginResponse": {
+        "model.UpdateVacancyRequest": {
             "type": "object",
             "properties": {
-                "refresh_token": {
+                "city": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "string",
-                    "example": "OK"
+                "description": {
+                    "type": "string"
                 },
-                "token": {
+                "name": {
                     "type": "string"
+                },
+                "salary": {
+                    "type": "integer"
                 }
             }
         },
-        "model.RecreateJWTRequest": {
+        "model.UserExportObject": {
             "type": "object",
             "properties": {
-                "refresh_token": {
+                "audit_events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AuditEventObject"
+                    }
+                },
+                "exported_at": {
                     "type": "string"
+                },
+                "external_identities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ExternalIdentityObject"
+                    }
+                },
+                "lockout_events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LockoutEventObject"
+                    }
+                },
+                "owned_companies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OwnedCompanyExportObject"
+                    }
+                },
+                "profile": {
+                    "$ref": "#/definitions/model.UserProfileExportObject"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RoleGrantObject"
+                    }
+                },
+                "sent_invitations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.InvitationObject"
+                    }
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SessionObject"
+                    }
                 }
             }
         },
-        "model.RegisterRequest": {
+        "model.UserImportResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.UserImportRowObject"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "model.UserImportRowObject": {
             "type": "object",
             "properties": {
                 "email": {
                     "type": "string"
                 },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "name": {
                     "type": "string"
                 },
-                "password": {
+                "role": {
+                    "$ref": "#/definitions/enum.Role"
+                },
+                "row": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/enum.UserImportStatus"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "model.UpdateUserAllFieldRequest": {
+        "model.UserObject": {
             "type": "object",
             "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
-                "fio": {
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "id": {
                     "type": "string"
                 },
-                "password": {
+                "is_admin": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pending_email": {
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "Roles, EmailVerified, PendingEmail and TwoFactorEnabled are filled only for the authorized user.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RoleGrantObject"
+                    }
+                },
+                "two_factor_enabled": {
+                    "type": "boolean"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "model.UserObject": {
+        "model.UserProfileExportObject": {
             "type": "object",
             "properties": {
+                "avatar_id": {
+                    "type": "string"
+                },
+                "company_id": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
                 "email": {
                     "type": "string"
                 },
-                "id": {
+                "email_verified_at": {
                     "type": "string"
                 },
-                "is_admin": {
-                    "type": "boolean"
+                "id": {
+                    "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
+                "pending_email": {
+                    "type": "string"
+                },
+                "two_factor_enabled_at": {
+                    "type": "string"
+                },
+                "unused_backup_codes": {
+                    "type": "integer"
+                },
                 "updated_at": {
                     "type": "string"
                 }
@@ -670,6 +6228,91 @@
                     }
                 }
             }
+        },
+        "model.VacancyObject": {
+            "type": "object",
+            "properties": {
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CandidateObject"
+                    }
+                },
+                "city": {
+                    "type": "string"
+                },
+                "closed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "salary": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.VacancyStatsObject": {
+            "type": "object",
+            "properties": {
+                "average_days_since_application": {
+                    "type": "number"
+                },
+                "candidates": {
+                    "type": "integer"
+                },
+                "closed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "new_candidates_30_days": {
+                    "type": "integer"
+                },
+                "new_candidates_7_days": {
+                    "type": "integer"
+                },
+                "salary": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.VerifyEmailRequest": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -1,8 +1,12 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -14,112 +18,176 @@
 	authorizationHeader = "Authorization"
 	//UserIDKey value type is uuid.UUID
 	UserIDKey = "userID"
-	IsAdmin   = "isAdmin"
-	AdminsID  = "adminID"
+	//ClaimsKey value type is *auth.Claims
+	ClaimsKey = "claims"
+	//ServiceAccountIDKey value type is uuid.UUID
+	ServiceAccountIDKey = "serviceAccountID"
+	//ServiceAccountKey value type is *auth.ServiceAccountPrincipal
+	ServiceAccountKey = "serviceAccount"
 )
 
 // SetAuthorizationCheck adds authorization check to middleware chain.
-func SetAuthorizationCheck(JWTManager *auth.JWTManager, logger zap.Logger) gin.HandlerFunc {
+func SetAuthorizationCheck(JWTManager *auth.JWTManager, revocationStore auth.RevocationStore, logger zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-
-		header := c.GetHeader(authorizationHeader)
-		if header == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, base.ResponseFailure{
-				Status:  http.StatusText(http.StatusUnauthorized),
-				Blame:   base.BlameUser,
-				Message: "unauthorized",
-			})
+		claims, ok := authorize(c, JWTManager, revocationStore, logger)
+		if !ok {
 			return
 		}
 
-		headerParts := strings.Split(header, " ")
-		if len(headerParts) != 2 {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, base.ResponseFailure{
-				Status:  http.StatusText(http.StatusUnauthorized),
-				Blame:   base.BlameUser,
-				Message: "unauthorized",
-			})
-			return
-		}
+		c.Set(UserIDKey, claims.UserID)
+		c.Set(ClaimsKey, claims)
+		c.Next()
+	}
+}
 
-		stringUserID, err := JWTManager.Parse(headerParts[1])
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, base.ResponseFailure{
-				Status:  http.StatusText(http.StatusUnauthorized),
-				Blame:   base.BlameUser,
-				Message: "unauthorized",
-			})
+// SetOptionalAuthorizationCheck is SetAuthorizationCheck for routes open to anonymous users.
+// Requests without the Authorization header pass without UserIDKey, invalid tokens are still rejected.
+func SetOptionalAuthorizationCheck(JWTManager *auth.JWTManager, revocationStore auth.RevocationStore, logger zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(authorizationHeader) == "" {
+			c.Next()
 			return
 		}
 
-		userID, err := uuid.Parse(stringUserID)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, base.ResponseFailure{
-				Status:  http.StatusText(http.StatusUnauthorized),
-				Blame:   base.BlameUser,
-				Message: "unauthorized",
-			})
+		claims, ok := authorize(c, JWTManager, revocationStore, logger)
+		if !ok {
 			return
 		}
 
-		c.Set(UserIDKey, userID)
+		c.Set(UserIDKey, claims.UserID)
+		c.Set(ClaimsKey, claims)
 		c.Next()
 	}
 }
 
-func SetAuthorizationAdminCheck(JWTManager *auth.JWTManager, adminID uuid.UUID, logger zap.Logger) gin.HandlerFunc {
-	return func(c *gin.Context) {
+// OptionalUserID returns ID of the user set by SetOptionalAuthorizationCheck, nil for anonymous users.
+func OptionalUserID(c *gin.Context) *uuid.UUID {
+	userID, ok := c.Get(UserIDKey)
+	if !ok {
+		return nil
+	}
 
-		header := c.GetHeader(authorizationHeader)
-		if header == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, base.ResponseFailure{
-				Status:  http.StatusText(http.StatusUnauthorized),
-				Blame:   base.BlameUser,
-				Message: "unauthorized",
-			})
-			return
-		}
+	id := userID.(uuid.UUID)
+	return &id
+}
 
-		headerParts := strings.Split(header, " ")
-		if len(headerParts) != 2 {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, base.ResponseFailure{
-				Status:  http.StatusText(http.StatusUnauthorized),
-				Blame:   base.BlameUser,
-				Message: "unauthorized",
-			})
-			return
-		}
+// RestrictedCompanyID returns the company a key of a company service account set by
+// SetAuthorizationCheckWithAPIKey is limited to, nil for users and unrestricted keys.
+func RestrictedCompanyID(c *gin.Context) *uuid.UUID {
+	principal, ok := c.Get(ServiceAccountKey)
+	if !ok {
+		return nil
+	}
 
-		stringUserID, err := JWTManager.Parse(headerParts[1])
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, base.ResponseFailure{
-				Status:  http.StatusText(http.StatusUnauthorized),
-				Blame:   base.BlameUser,
-				Message: "unauthorized",
-			})
+	return principal.(*auth.ServiceAccountPrincipal).CompanyID
+}
+
+// SetAuthorizationCheckWithAPIKey is SetAuthorizationCheck which also accepts API keys of service accounts.
+// A key must have all the scopes, and a key of a company service account is limited to the
+// company-id route parameter of that company. For keys ServiceAccountIDKey is set instead of UserIDKey,
+// so the handler must not depend on the user.
+func SetAuthorizationCheckWithAPIKey(
+	JWTManager *auth.JWTManager,
+	revocationStore auth.RevocationStore,
+	apiKeyAuthenticator auth.APIKeyAuthenticator,
+	logger zap.Logger,
+	scopes ...enum.APIScope,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c)
+		if !ok || !auth.IsAPIKey(token) {
+			claims, ok := authorize(c, JWTManager, revocationStore, logger)
+			if !ok {
+				return
+			}
+
+			c.Set(UserIDKey, claims.UserID)
+			c.Set(ClaimsKey, claims)
+			c.Next()
 			return
 		}
 
-		userID, err := uuid.Parse(stringUserID)
+		principal, err := apiKeyAuthenticator.Authenticate(token, c)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, base.ResponseFailure{
-				Status:  http.StatusText(http.StatusUnauthorized),
-				Blame:   base.BlameUser,
-				Message: "unauthorized",
-			})
+			if errors.Is(err, auth.ErrInvalidAPIKey) {
+				abortUnauthorized(c)
+				return
+			}
+
+			logger.Error(fmt.Sprintf("failed to authenticate api key: %v", err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, api.GeneralUnexpectedError())
 			return
 		}
 
-		if userID != adminID {
+		if !principal.HasScopes(scopes...) {
 			c.AbortWithStatusJSON(http.StatusForbidden, base.ResponseFailure{
 				Status:  http.StatusText(http.StatusForbidden),
 				Blame:   base.BlameUser,
-				Message: "no access",
+				Message: "api key has insufficient scope",
 			})
+			return
+		}
+
+		// a key of a company service account only works on routes of that company
+		if companyID := c.Param("company-id"); principal.CompanyID != nil && companyID != "" && companyID != principal.CompanyID.String() {
+			abortForbidden(c)
+			return
 		}
 
-		c.Set(IsAdmin, true)
-		c.Set(AdminsID, userID)
+		c.Set(ServiceAccountIDKey, principal.ServiceAccountID)
+		c.Set(ServiceAccountKey, principal)
 		c.Next()
 	}
 }
+
+// authorize parses bearer token of the request and checks it is not revoked.
+// On failure the request is aborted and false is returned.
+func authorize(c *gin.Context, JWTManager *auth.JWTManager, revocationStore auth.RevocationStore, logger zap.Logger) (*auth.Claims, bool) {
+	token, ok := bearerToken(c)
+	if !ok {
+		abortUnauthorized(c)
+		return nil, false
+	}
+
+	claims, err := JWTManager.Parse(token)
+	if err != nil {
+		abortUnauthorized(c)
+		return nil, false
+	}
+
+	revoked, err := revocationStore.IsRevoked(claims, c)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to check token revocation: %v", err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, api.GeneralUnexpectedError())
+		return nil, false
+	}
+
+	if revoked {
+		abortUnauthorized(c)
+		return nil, false
+	}
+
+	return claims, true
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader(authorizationHeader)
+	if header == "" {
+		return "", false
+	}
+
+	headerParts := strings.Split(header, " ")
+	if len(headerParts) != 2 {
+		return "", false
+	}
+
+	return headerParts[1], true
+}
+
+func abortUnauthorized(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusUnauthorized, base.ResponseFailure{
+		Status:  http.StatusText(http.StatusUnauthorized),
+		Blame:   base.BlameUser,
+		Message: "unauthorized",
+	})
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// EmailVerificationChecker tells whether the user has confirmed the email.
+type EmailVerificationChecker interface {
+	IsEmailVerified(userID uuid.UUID, ctx context.Context) (bool, error)
+}
+
+// SetEmailVerifiedCheck rejects users who have not confirmed the email yet.
+// It must follow SetAuthorizationCheck in the middleware chain. Service accounts have no email and pass.
+func SetEmailVerifiedCheck(checker EmailVerificationChecker, logger zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get(ServiceAccountIDKey); ok {
+			c.Next()
+			return
+		}
+
+		userID, ok := c.Get(UserIDKey)
+		if !ok {
+			abortUnauthorized(c)
+			return
+		}
+
+		verified, err := checker.IsEmailVerified(userID.(uuid.UUID), c)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check email verification: %v", err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, api.GeneralUnexpectedError())
+			return
+		}
+
+		if !verified {
+			c.AbortWithStatusJSON(http.StatusForbidden, base.ResponseFailure{
+				Status:  http.StatusText(http.StatusForbidden),
+				Blame:   base.BlameUser,
+				Message: "email is not verified",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// PermissionChecker tells whether roles of the user grant the permission, within the company if it is not nil.
+type PermissionChecker interface {
+	HasPermission(userID uuid.UUID, companyID *uuid.UUID, permission enum.Permission, ctx context.Context) (bool, error)
+}
+
+// CompanyResolver finds the company a resource from the route belongs to.
+type CompanyResolver interface {
+	GetVacancyCompanyID(vacancyID uuid.UUID, ctx context.Context) (uuid.UUID, *base.ServiceError)
+}
+
+// companyLookup returns the company the request is about, nil if it is not about a single company.
+// On failure the request is aborted and false is returned.
+type companyLookup func(c *gin.Context) (*uuid.UUID, bool)
+
+// SetPermissionCheck rejects users whose roles don't grant the permission.
+// On routes with the company-id parameter roles granted within that company count as well.
+// It must follow SetAuthorizationCheck in the middleware chain. Service accounts are limited by scopes instead and pass.
+func SetPermissionCheck(checker PermissionChecker, logger zap.Logger, permission enum.Permission) gin.HandlerFunc {
+	return permissionCheck(checker, logger, permission, func(c *gin.Context) (*uuid.UUID, bool) {
+		param := c.Param("company-id")
+		if param == "" {
+			return nil, true
+		}
+
+		companyID, err := uuid.Parse(param)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, api.GeneralParsingError())
+			return nil, false
+		}
+
+		return &companyID, true
+	})
+}
+
+// SetVacancyPermissionCheck is SetPermissionCheck for routes with the vacancy-id parameter,
+// roles granted within the company of the vacancy count as well.
+func SetVacancyPermissionCheck(checker PermissionChecker, resolver CompanyResolver, logger zap.Logger, permission enum.Permission) gin.HandlerFunc {
+	return permissionCheck(checker, logger, permission, func(c *gin.Context) (*uuid.UUID, bool) {
+		vacancyID, err := uuid.Parse(c.Param("vacancy-id"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, api.GeneralParsingError())
+			return nil, false
+		}
+
+		companyID, serviceErr := resolver.GetVacancyCompanyID(vacancyID, c)
+		if serviceErr != nil {
+			c.AbortWithStatusJSON(serviceErr.Code, api.ResponseFromServiceError(*serviceErr))
+			return nil, false
+		}
+
+		return &companyID, true
+	})
+}
+
+func permissionCheck(checker PermissionChecker, logger zap.Logger, permission enum.Permission, lookup companyLookup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		companyID, ok := lookup(c)
+		if !ok {
+			return
+		}
+
+		if principal, ok := c.Get(ServiceAccountKey); ok {
+			// a key of a company service account only works within that company
+			restrictedTo := principal.(*auth.ServiceAccountPrincipal).CompanyID
+			if restrictedTo != nil && (companyID == nil || *companyID != *restrictedTo) {
+				abortForbidden(c)
+				return
+			}
+
+			c.Next()
+			return
+		}
+
+		userID, ok := c.Get(UserIDKey)
+		if !ok {
+			abortUnauthorized(c)
+			return
+		}
+
+		allowed, err := checker.HasPermission(userID.(uuid.UUID), companyID, permission, c)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check permission %s: %v", permission, err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, api.GeneralUnexpectedError())
+			return
+		}
+
+		if !allowed {
+			abortForbidden(c)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func abortForbidden(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusForbidden, base.ResponseFailure{
+		Status:  http.StatusText(http.StatusForbidden),
+		Blame:   base.BlameUser,
+		Message: "no access",
+	})
+}
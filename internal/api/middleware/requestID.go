@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+	//RequestIDKey value type is string
+	RequestIDKey = "requestID"
+	//ClientIPKey value type is string
+	ClientIPKey = "clientIP"
+)
+
+// SetRequestID tags the request with an ID, taken from the X-Request-ID header of a proxy or generated.
+// The ID is returned in the same header and, with the client IP, kept in the context for the audit log.
+func SetRequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(RequestIDKey, requestID)
+		c.Set(ClientIPKey, c.ClientIP())
+		c.Header(requestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// isValidRequestID accepts IDs of printable ASCII characters, so a client can't inject anything into logs.
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range requestID {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/google/uuid"
+	"strings"
+)
+
+const (
+	// apiKeyMarker starts every API key, so it can't be confused with a JWT.
+	apiKeyMarker     = "nmx_"
+	apiKeyPrefixSize = 6
+	apiKeySecretSize = 32
+)
+
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// ServiceAccountPrincipal is the service account an API key authenticated.
+type ServiceAccountPrincipal struct {
+	ServiceAccountID uuid.UUID
+	APIKeyID         uuid.UUID
+	CompanyID        *uuid.UUID
+	Scopes           []enum.APIScope
+}
+
+func (p *ServiceAccountPrincipal) HasScopes(scopes ...enum.APIScope) bool {
+	for _, required := range scopes {
+		granted := false
+		for _, scope := range p.Scopes {
+			if scope == required {
+				granted = true
+				break
+			}
+		}
+
+		if !granted {
+			return false
+		}
+	}
+
+	return true
+}
+
+// APIKeyAuthenticator resolves API keys to service accounts.
+// It returns ErrInvalidAPIKey for unknown, expired and revoked keys.
+type APIKeyAuthenticator interface {
+	Authenticate(key string, ctx context.Context) (*ServiceAccountPrincipal, error)
+}
+
+// NewAPIKey generates an API key in the form nmx_<prefix>_<secret>. The prefix is stored in clear
+// to find the key, the hash is stored to verify it.
+func NewAPIKey() (key string, prefix string, keyHash string, err error) {
+	prefixBytes := make([]byte, apiKeyPrefixSize)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", "", err
+	}
+
+	secret := make([]byte, apiKeySecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(prefixBytes)
+	key = apiKeyMarker + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+
+	return key, prefix, HashOneTimeToken(key), nil
+}
+
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyMarker)
+}
+
+// ParseAPIKeyPrefix returns the prefix of a well-formed API key.
+func ParseAPIKeyPrefix(key string) (string, bool) {
+	if !IsAPIKey(key) {
+		return "", false
+	}
+
+	prefix, secret, found := strings.Cut(strings.TrimPrefix(key, apiKeyMarker), "_")
+	if !found || len(prefix) != apiKeyPrefixSize*2 || secret == "" {
+		return "", false
+	}
+
+	return prefix, true
+}
+
+// VerifyAPIKey compares the key with the stored hash in constant time.
+func VerifyAPIKey(key string, keyHash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashOneTimeToken(key)), []byte(keyHash)) == 1
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"context"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Claims are the verified claims of an access token.
+type Claims struct {
+	TokenID uuid.UUID
+	UserID  uuid.UUID
+	// SessionID is the family of the refresh session the token was issued for.
+	SessionID uuid.UUID
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// tokenClaims is the JWT payload of an access token.
+type tokenClaims struct {
+	jwt.StandardClaims
+	SessionID string `json:"sid,omitempty"`
+	// Purpose is set only on challenge tokens, see JWTManager.NewChallengeJWT.
+	Purpose string `json:"pur,omitempty"`
+}
+
+// RevocationStore tells whether an access token was revoked before it expired.
+type RevocationStore interface {
+	IsRevoked(claims *Claims, ctx context.Context) (bool, error)
+}
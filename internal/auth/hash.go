@@ -1,26 +1,79 @@
 package auth
 
 import (
-	"crypto/sha256"
+	"errors"
 	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/common"
 )
 
-// Hasher uses SHA256 to hash passwords with provided salt.
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// ErrUnknownHashFormat is returned when stored hash was produced by none of the known schemes.
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+// PasswordHasher is a single password hashing scheme which produces self-describing hashes.
+type PasswordHasher interface {
+	// Hash returns encoded hash of the password with a fresh per-user salt.
+	Hash(password string) (string, error)
+	// Verify reports whether password matches encoded hash. Comparison is constant-time.
+	Verify(password string, encoded string) (bool, error)
+	// Matches reports whether encoded hash was produced by this scheme.
+	Matches(encoded string) bool
+	// NeedsRehash reports whether encoded hash was produced with outdated parameters.
+	NeedsRehash(encoded string) bool
+}
+
+// Hasher hashes new passwords with the configured scheme and verifies hashes
+// produced by any known scheme, including legacy salted SHA256 ones.
 type Hasher struct {
-	salt string
+	primary PasswordHasher
+	schemes []PasswordHasher
 }
 
-func NewHasher(salt string) *Hasher {
-	return &Hasher{salt: salt}
+func NewHasher(config common.PasswordHashConfig, legacySalt string) (*Hasher, error) {
+	argon2id := NewArgon2idHasher(config.Argon2Time, config.Argon2Memory, config.Argon2Threads)
+	bcrypt := NewBcryptHasher(config.BcryptCost)
+
+	var primary PasswordHasher
+	switch config.Algorithm {
+	case "", AlgorithmArgon2id:
+		primary = argon2id
+	case AlgorithmBcrypt:
+		primary = bcrypt
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm: %s", config.Algorithm)
+	}
+
+	return &Hasher{
+		primary: primary,
+		schemes: []PasswordHasher{argon2id, bcrypt, NewLegacyHasher(legacySalt)},
+	}, nil
 }
 
-// Hash creates SHA256 hash of given password.
+// Hash creates encoded hash of given password using the configured scheme.
 func (h *Hasher) Hash(password string) (string, error) {
-	hash := sha256.New()
+	return h.primary.Hash(password)
+}
+
+// Verify checks password against encoded hash. When the password matches and
+// the hash was made by another scheme or with outdated parameters, needsRehash is true
+// and the caller is expected to replace the stored hash with a fresh one.
+func (h *Hasher) Verify(password string, encoded string) (ok bool, needsRehash bool, err error) {
+	for _, scheme := range h.schemes {
+		if !scheme.Matches(encoded) {
+			continue
+		}
+
+		ok, err := scheme.Verify(password, encoded)
+		if err != nil || !ok {
+			return false, false, err
+		}
 
-	if _, err := hash.Write([]byte(password)); err != nil {
-		return "", err
+		return true, scheme != h.primary || scheme.NeedsRehash(encoded), nil
 	}
 
-	return fmt.Sprintf("%x", hash.Sum([]byte(h.salt))), nil
+	return false, false, ErrUnknownHashFormat
 }
@@ -1,52 +1,418 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"sync"
 	"time"
 )
 
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	// keyRefreshInterval is how often the key set is reloaded from storage and checked for rotation.
+	keyRefreshInterval = 10 * time.Minute
+	// JWKSMaxAge is how long clients may cache the key set.
+	JWKSMaxAge = 10 * time.Minute
+	// keyPublicationDelay is how long a new key is published before it signs tokens,
+	// so every instance and every cached key set knows it by then.
+	keyPublicationDelay = keyRefreshInterval + JWKSMaxAge
+	// unknownKeyReloadInterval limits reloads of the key set caused by tokens signed with unknown keys.
+	unknownKeyReloadInterval = 10 * time.Second
+	unknownKeyReloadTimeout  = 5 * time.Second
+)
+
+// KeyStorage persists asymmetric signing keys, so they survive restarts and are shared between instances.
+type KeyStorage interface {
+	CreateNext(key *entity.SigningKey, after time.Time, ctx context.Context) (bool, error)
+	GetValid(algorithm string, now time.Time, ctx context.Context) ([]entity.SigningKey, error)
+	DeleteExpired(now time.Time, ctx context.Context) error
+}
+
+// JWTManager issues and verifies access tokens.
+// With HS256 tokens are signed with the shared signing key. With RS256 and EdDSA
+// tokens are signed with the newest active key of a rotating key set, identified by the kid header.
 type JWTManager struct {
-	signingKey string
-	timeToLive time.Duration
+	algorithm        string
+	signingKey       string
+	issuer           string
+	timeToLive       time.Duration
+	rotationInterval time.Duration
+	storage          KeyStorage
+
+	mu     sync.RWMutex
+	keys   map[string]*keyPair
+	newest *keyPair
+
+	reloadMu   sync.Mutex
+	reloadedAt time.Time
+}
+
+func NewJWTManager(
+	algorithm string,
+	signingKey string,
+	issuer string,
+	timeToLive time.Duration,
+	rotationInterval time.Duration,
+	storage KeyStorage,
+) (*JWTManager, error) {
+	switch algorithm {
+	case "", AlgorithmHS256:
+		if signingKey == "" {
+			return nil, errors.New("empty signing key")
+		}
+		algorithm = AlgorithmHS256
+	case AlgorithmRS256, AlgorithmEdDSA:
+		if storage == nil {
+			return nil, errors.New("key storage is required for asymmetric signing")
+		}
+		if rotationInterval <= keyPublicationDelay {
+			return nil, fmt.Errorf("key rotation interval must be longer than %s", keyPublicationDelay)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+
+	return &JWTManager{
+		algorithm:        algorithm,
+		signingKey:       signingKey,
+		issuer:           issuer,
+		timeToLive:       timeToLive,
+		rotationInterval: rotationInterval,
+		storage:          storage,
+		keys:             map[string]*keyPair{},
+	}, nil
+}
+
+func (m *JWTManager) isAsymmetric() bool {
+	return m.algorithm != AlgorithmHS256
+}
+
+// Init loads the key set from storage and creates the first key if there is no usable one.
+func (m *JWTManager) Init(ctx context.Context) error {
+	if !m.isAsymmetric() {
+		return nil
+	}
+
+	return m.refresh(ctx)
+}
+
+// StartRotation periodically reloads the key set and creates the next key keyPublicationDelay
+// before the active one gets older than rotation interval. It blocks until ctx is done.
+func (m *JWTManager) StartRotation(ctx context.Context, logger *zap.Logger) {
+	if !m.isAsymmetric() {
+		return
+	}
+
+	ticker := time.NewTicker(keyRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.refresh(ctx); err != nil {
+				logger.Error(fmt.Sprintf("failed to refresh jwt signing keys: %v", err))
+			}
+		}
+	}
+}
+
+// Rotate creates the next signing key. It is published at once and signs tokens after keyPublicationDelay,
+// when every instance and cached key set knows it. The first key signs at once, there is nobody to tell.
+// Previous keys stay valid for verification. If instances rotate at once, only one key is created.
+func (m *JWTManager) Rotate(ctx context.Context) error {
+	if !m.isAsymmetric() {
+		return errors.New("key rotation is not supported for " + m.algorithm)
+	}
+
+	now := time.Now()
+
+	m.mu.RLock()
+	newest := m.newest
+	m.mu.RUnlock()
+
+	activatesAt := now
+	var after time.Time
+	if newest != nil {
+		activatesAt = now.Add(keyPublicationDelay)
+		after = newest.createdAt
+	}
+
+	key, err := generateSigningKey(m.algorithm, activatesAt, activatesAt.Add(m.rotationInterval+m.timeToLive))
+	if err != nil {
+		return err
+	}
+
+	// another instance may have created the key first, it is loaded then
+	if _, err := m.storage.CreateNext(key, after, ctx); err != nil {
+		return err
+	}
+
+	return m.load(now, ctx)
+}
+
+func (m *JWTManager) refresh(ctx context.Context) error {
+	now := time.Now()
+
+	if err := m.storage.DeleteExpired(now, ctx); err != nil {
+		return err
+	}
+
+	if err := m.load(now, ctx); err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	needsRotation := m.newest == nil || now.Sub(m.newest.activatesAt) >= m.rotationInterval-keyPublicationDelay
+	m.mu.RUnlock()
+
+	if needsRotation {
+		return m.Rotate(ctx)
+	}
+
+	return nil
+}
+
+func (m *JWTManager) load(now time.Time, ctx context.Context) error {
+	stored, err := m.storage.GetValid(m.algorithm, now, ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*keyPair, len(stored))
+	var newest *keyPair
+
+	for i := range stored {
+		key, err := parseSigningKey(&stored[i])
+		if err != nil {
+			return err
+		}
+
+		keys[key.kid] = key
+		if newest == nil || key.createdAt.After(newest.createdAt) {
+			newest = key
+		}
+	}
+
+	m.mu.Lock()
+	m.keys = keys
+	m.newest = newest
+	m.mu.Unlock()
+
+	return nil
+}
+
+// reloadForUnknownKey reloads the key set for a token signed with an unknown key, which another
+// instance may have created since the last refresh. Reloads are limited to one per
+// unknownKeyReloadInterval, so tokens with made up kids can't flood the storage.
+func (m *JWTManager) reloadForUnknownKey() {
+	m.reloadMu.Lock()
+	defer m.reloadMu.Unlock()
+
+	now := time.Now()
+	if now.Sub(m.reloadedAt) < unknownKeyReloadInterval {
+		return
+	}
+	m.reloadedAt = now
+
+	ctx, cancel := context.WithTimeout(context.Background(), unknownKeyReloadTimeout)
+	defer cancel()
+
+	_ = m.load(now, ctx)
 }
 
-func NewJWTManager(signingKey string, timeToLive time.Duration) (*JWTManager, error) {
-	if signingKey == "" {
-		return nil, errors.New("empty signing key")
+// activeKey returns the newest key which signs tokens at now.
+func (m *JWTManager) activeKey(now time.Time) *keyPair {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var active *keyPair
+	for _, key := range m.keys {
+		if key.activatesAt.After(now) {
+			continue
+		}
+
+		if active == nil || key.activatesAt.After(active.activatesAt) {
+			active = key
+		}
 	}
 
-	return &JWTManager{signingKey: signingKey, timeToLive: timeToLive}, nil
+	return active
 }
 
-func (m *JWTManager) NewJWT(userId uuid.UUID) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
-		ExpiresAt: time.Now().Add(m.timeToLive).Unix(),
-		Subject:   userId.String(),
-	})
+// NewJWT issues an access token of the user bound to the refresh session family sessionID.
+func (m *JWTManager) NewJWT(userId uuid.UUID, sessionID uuid.UUID) (string, error) {
+	now := time.Now()
+	claims := tokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewString(),
+			ExpiresAt: now.Add(m.timeToLive).Unix(),
+			IssuedAt:  now.Unix(),
+			Issuer:    m.issuer,
+			Subject:   userId.String(),
+		},
+		SessionID: sessionID.String(),
+	}
 
-	return token.SignedString([]byte(m.signingKey))
+	return m.sign(claims)
 }
 
-func (m *JWTManager) Parse(accessToken string) (string, error) {
-	token, err := jwt.Parse(accessToken, func(token *jwt.Token) (i interface{}, err error) {
+// NewChallengeJWT issues a short-lived token proving the user passed the first login step.
+// It can only be exchanged by ParseChallenge with the same purpose and is never accepted as an access token.
+func (m *JWTManager) NewChallengeJWT(userId uuid.UUID, purpose string, timeToLive time.Duration) (string, error) {
+	now := time.Now()
+	claims := tokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewString(),
+			ExpiresAt: now.Add(timeToLive).Unix(),
+			IssuedAt:  now.Unix(),
+			Issuer:    m.issuer,
+			Subject:   userId.String(),
+		},
+		Purpose: purpose,
+	}
+
+	return m.sign(claims)
+}
+
+func (m *JWTManager) sign(claims tokenClaims) (string, error) {
+	if !m.isAsymmetric() {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(m.signingKey))
+	}
+
+	active := m.activeKey(time.Now())
+	if active == nil {
+		return "", errors.New("no active signing key")
+	}
+
+	token := jwt.NewWithClaims(active.method, claims)
+	token.Header["kid"] = active.kid
+
+	return token.SignedString(active.private)
+}
+
+// Parse verifies signature and expiry of the access token and returns its claims.
+// Revocation is not checked here, see RevocationStore.
+func (m *JWTManager) Parse(accessToken string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, m.keyFunc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*tokenClaims)
+	if !ok {
+		return nil, fmt.Errorf("error get user claims from token")
+	}
+
+	if claims.Purpose != "" {
+		return nil, fmt.Errorf("%s token is not an access token", claims.Purpose)
+	}
+
+	tokenID, err := uuid.Parse(claims.Id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token id: %w", err)
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token subject: %w", err)
+	}
+
+	var sessionID uuid.UUID
+	if claims.SessionID != "" {
+		if sessionID, err = uuid.Parse(claims.SessionID); err != nil {
+			return nil, fmt.Errorf("invalid token session: %w", err)
+		}
+	}
+
+	return &Claims{
+		TokenID:   tokenID,
+		UserID:    userID,
+		SessionID: sessionID,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// ParseChallenge verifies a token issued by NewChallengeJWT for the purpose and returns the user it was issued to.
+func (m *JWTManager) ParseChallenge(challengeToken string, purpose string) (uuid.UUID, error) {
+	token, err := jwt.ParseWithClaims(challengeToken, &tokenClaims{}, m.keyFunc)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	claims, ok := token.Claims.(*tokenClaims)
+	if !ok {
+		return uuid.Nil, fmt.Errorf("error get user claims from token")
+	}
+
+	if claims.Purpose != purpose {
+		return uuid.Nil, fmt.Errorf("token is not a %s token", purpose)
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid token subject: %w", err)
+	}
+
+	return userID, nil
+}
+
+func (m *JWTManager) keyFunc(token *jwt.Token) (interface{}, error) {
+	if !m.isAsymmetric() {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
 		return []byte(m.signingKey), nil
-	})
+	}
 
-	if err != nil {
-		return "", err
+	kid, _ := token.Header["kid"].(string)
+
+	m.mu.RLock()
+	key, ok := m.keys[kid]
+	m.mu.RUnlock()
+
+	if !ok {
+		m.reloadForUnknownKey()
+
+		m.mu.RLock()
+		key, ok = m.keys[kid]
+		m.mu.RUnlock()
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", fmt.Errorf("error get user claims from token")
+		return nil, fmt.Errorf("unknown signing key: %s", kid)
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.public, nil
+}
+
+// JWKS returns public keys which may have signed currently valid tokens and the next key, which will sign them soon.
+// The set is empty for HS256 because the shared secret must never be published.
+func (m *JWTManager) JWKS() JWKSet {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set := JWKSet{Keys: make([]JWK, 0, len(m.keys))}
+	for _, key := range m.keys {
+		set.Keys = append(set.Keys, key.jwk())
 	}
 
-	return claims["sub"].(string), nil
+	return set
 }
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"time"
+)
+
+const rsaKeyBits = 2048
+
+type keyPair struct {
+	kid         string
+	method      jwt.SigningMethod
+	private     crypto.PrivateKey
+	public      crypto.PublicKey
+	createdAt   time.Time
+	activatesAt time.Time
+}
+
+func generateSigningKey(algorithm string, activatesAt time.Time, expiresAt time.Time) (*entity.SigningKey, error) {
+	var private crypto.PrivateKey
+	var public crypto.PublicKey
+
+	switch algorithm {
+	case AlgorithmRS256:
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		private, public = key, &key.PublicKey
+	case AlgorithmEdDSA:
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		private, public = privateKey, publicKey
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.SigningKey{
+		Kid:         uuid.NewString(),
+		Algorithm:   algorithm,
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		PublicKey:   string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+		ActivatesAt: activatesAt,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+func parseSigningKey(stored *entity.SigningKey) (*keyPair, error) {
+	key := &keyPair{
+		kid:         stored.Kid,
+		createdAt:   stored.CreatedAt,
+		activatesAt: stored.ActivatesAt,
+	}
+
+	var err error
+	switch stored.Algorithm {
+	case AlgorithmRS256:
+		key.method = jwt.SigningMethodRS256
+		if key.private, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(stored.PrivateKey)); err != nil {
+			return nil, err
+		}
+		if key.public, err = jwt.ParseRSAPublicKeyFromPEM([]byte(stored.PublicKey)); err != nil {
+			return nil, err
+		}
+	case AlgorithmEdDSA:
+		key.method = jwt.SigningMethodEdDSA
+		if key.private, err = jwt.ParseEdPrivateKeyFromPEM([]byte(stored.PrivateKey)); err != nil {
+			return nil, err
+		}
+		if key.public, err = jwt.ParseEdPublicKeyFromPEM([]byte(stored.PublicKey)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", stored.Algorithm)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+)
+
+// LegacyHasher verifies hashes created before per-user salts were introduced:
+// hex encoded global salt followed by unsalted SHA256 of the password.
+// It is never used to hash new passwords, such hashes are always upgraded on login.
+type LegacyHasher struct {
+	salt string
+}
+
+func NewLegacyHasher(salt string) *LegacyHasher {
+	return &LegacyHasher{salt: salt}
+}
+
+func (h *LegacyHasher) Hash(password string) (string, error) {
+	hash := sha256.New()
+
+	if _, err := hash.Write([]byte(password)); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", hash.Sum([]byte(h.salt))), nil
+}
+
+func (h *LegacyHasher) Verify(password string, encoded string) (bool, error) {
+	hash, err := h.Hash(password)
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(encoded)) == 1, nil
+}
+
+func (h *LegacyHasher) Matches(encoded string) bool {
+	if len(encoded) != hex.EncodedLen(len(h.salt)+sha256.Size) {
+		return false
+	}
+
+	_, err := hex.DecodeString(encoded)
+	return err == nil
+}
+
+func (h *LegacyHasher) NeedsRehash(string) bool {
+	return true
+}
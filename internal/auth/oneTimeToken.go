@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const oneTimeTokenSize = 32
+
+// NewOneTimeToken generates a random secret to be sent to the user and its hash to be stored.
+func NewOneTimeToken() (token string, tokenHash string, err error) {
+	secret := make([]byte, oneTimeTokenSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(secret)
+
+	return token, HashOneTimeToken(token), nil
+}
+
+// HashOneTimeToken returns the stored form of a one-time token.
+func HashOneTimeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
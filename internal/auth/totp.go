@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP parameters of RFC 6238 understood by every authenticator app.
+const (
+	totpSecretSize = 20
+	totpDigits     = 6
+	totpPeriod     = 30
+	// totpSkew is how many periods before and after the current one are accepted to tolerate clock drift.
+	totpSkew = 1
+
+	backupCodeSize     = 10
+	backupCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewTOTPSecret generates a random base32 encoded TOTP secret.
+func NewTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TOTPProvisioningURI returns the otpauth URI authenticator apps read from a QR code.
+func TOTPProvisioningURI(issuer string, account string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+
+	return fmt.Sprintf("otpauth://totp/%s:%s?%s", url.PathEscape(issuer), url.PathEscape(account), query.Encode())
+}
+
+// VerifyTOTP checks the code against the secret at now. On success it returns the time step
+// the code belongs to, callers must reject steps which were already used to prevent replay.
+func VerifyTOTP(secret string, code string, now time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+func totpCode(key []byte, step int64) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+// NewBackupCode generates a random single-use recovery code formatted as xxxxx-xxxxx.
+func NewBackupCode() (string, error) {
+	random := make([]byte, backupCodeSize)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	var code strings.Builder
+	for i, b := range random {
+		if i == backupCodeSize/2 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(backupCodeAlphabet[int(b)%len(backupCodeAlphabet)])
+	}
+
+	return code.String(), nil
+}
+
+// NormalizeBackupCode brings a code typed by the user to the form it was hashed in.
+func NormalizeBackupCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, " ", "")
+	code = strings.ReplaceAll(code, "-", "")
+	if len(code) != backupCodeSize {
+		return code
+	}
+
+	return code[:backupCodeSize/2] + "-" + code[backupCodeSize/2:]
+}
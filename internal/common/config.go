@@ -110,9 +110,126 @@ func NewHttpClientConfig(
 }
 
 type AuthConfig struct {
-	Salt       string
-	SigningKey string
+	Salt string
+	// SigningAlgorithm is one of HS256, RS256 or EdDSA. SigningKey is used only with HS256,
+	// asymmetric keys are generated, stored in the database and rotated every KeyRotationInterval.
+	SigningAlgorithm    string
+	SigningKey          string
+	KeyRotationInterval time.Duration
+	Issuer              string
+	TimeToLive          time.Duration
+	// RefreshTimeToLive is the sliding lifetime of a refresh session, it is prolonged on every refresh.
+	RefreshTimeToLive time.Duration
+	// SessionLifetime is the absolute lifetime of a login, refresh can't prolong a session beyond it.
+	SessionLifetime   time.Duration
+	PasswordHash      PasswordHashConfig
+	PasswordReset     OneTimeLinkConfig
+	EmailVerification OneTimeLinkConfig
+	Invitation        OneTimeLinkConfig
+	// MembershipInvitation is the link registered users accept invitations into companies by.
+	MembershipInvitation OneTimeLinkConfig
+	TwoFactor            TwoFactorConfig
+	LoginThrottle        LoginThrottleConfig
+}
+
+// OIDCConfig configures single sign-on through OpenID Connect providers, keyed by the provider name used in login URLs.
+type OIDCConfig struct {
+	// StateTimeToLive is how long the user has to complete login at the provider.
+	StateTimeToLive time.Duration
+	Providers       map[string]OIDCProviderConfig
+}
+
+// OIDCProviderConfig configures a single OpenID Connect provider. Endpoints and keys are
+// discovered from the issuer, so any compliant provider, including a local one, can be used.
+type OIDCProviderConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the page receiving code and state, it has to pass them to the callback endpoint.
+	RedirectURL string
+	// Scopes are requested in addition to openid, email and profile are required.
+	Scopes []string
+	// AutoProvision creates an account on the first login of an unknown user.
+	AutoProvision bool
+	// AllowedDomains limits auto provisioning to emails of the domains, empty allows any domain.
+	AllowedDomains []string
+	// TrustEmail treats emails as verified when the provider omits the email_verified claim, false by default.
+	TrustEmail bool
+}
+
+// AvatarConfig configures user avatars. DefaultURL is a format string of the generated
+// avatar shown until the user uploads one, the user ID is substituted for %s.
+type AvatarConfig struct {
+	DefaultURL string
+}
+
+// UserDeletionConfig configures deletion of users. A deleted user can be restored by an administrator
+// within RestoreWindow, after that the user is purged with everything deleted along with it.
+type UserDeletionConfig struct {
+	RestoreWindow time.Duration
+}
+
+// CompanyArchiveConfig configures archived companies. They are purged with their vacancies and candidates
+// Retention after archiving, zero keeps them forever.
+type CompanyArchiveConfig struct {
+	Retention time.Duration
+}
+
+// CompanyDocumentConfig configures documents of companies, a single file takes at most MaxFileSize bytes.
+type CompanyDocumentConfig struct {
+	MaxFileSize int64
+}
+
+// UserImportConfig configures bulk import of users. LoginURL is the page the emailed credentials point to,
+// a single import takes at most MaxRows users.
+type UserImportConfig struct {
+	LoginURL string
+	MaxRows  int
+}
+
+// AuditConfig configures the audit log. Events older than Retention are removed, zero keeps them forever.
+type AuditConfig struct {
+	Retention time.Duration
+}
+
+// LoginThrottleConfig configures protection of login against password guessing.
+// Every failed attempt within FailureWindow delays the next one twice as long, starting with BaseDelay
+// and up to MaxDelay. After MaxAccountFailures of an account or MaxIPFailures of a client IP
+// further attempts are rejected for LockoutDuration.
+type LoginThrottleConfig struct {
+	MaxAccountFailures int
+	MaxIPFailures      int
+	BaseDelay          time.Duration
+	MaxDelay           time.Duration
+	FailureWindow      time.Duration
+	LockoutDuration    time.Duration
+}
+
+// TwoFactorConfig configures TOTP two-factor authentication.
+type TwoFactorConfig struct {
+	// Issuer is the account name shown in authenticator apps.
+	Issuer string
+	// ChallengeTimeToLive is how long the user has to enter the code after the password was accepted.
+	ChallengeTimeToLive time.Duration
+}
+
+// OneTimeLinkConfig configures emailed one-time links, e.g. password reset.
+// URL is a format string, the token is substituted for %s.
+type OneTimeLinkConfig struct {
+	URL        string
 	TimeToLive time.Duration
+	// Cooldown is the least time between links sent to the same user, zero doesn't limit them.
+	Cooldown time.Duration
+}
+
+// PasswordHashConfig selects the algorithm used for new password hashes and tunes its cost.
+// Zero values fall back to the defaults of the chosen algorithm.
+type PasswordHashConfig struct {
+	Algorithm     string
+	BcryptCost    int
+	Argon2Time    uint32
+	Argon2Memory  uint32
+	Argon2Threads uint8
 }
 
 type AdminMigrationConfig struct {
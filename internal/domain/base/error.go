@@ -3,6 +3,7 @@
 import (
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // ServiceError is a general optional error that can be
@@ -143,6 +144,28 @@ func NewLoginError(err error) *ServiceError {
 	}
 }
 
+func NewTooManyLoginAttemptsError(err error, retryAfter time.Duration) *ServiceError {
+	return &ServiceError{
+		Err:     err,
+		Blame:   BlameUser,
+		Code:    http.StatusTooManyRequests,
+		Message: fmt.Sprintf("too many login attempts, retry in %d seconds", retryAfterSeconds(retryAfter)),
+	}
+}
+
+func NewAccountLockedError(err error, retryAfter time.Duration) *ServiceError {
+	return &ServiceError{
+		Err:     err,
+		Blame:   BlameUser,
+		Code:    http.StatusLocked,
+		Message: fmt.Sprintf("account is temporarily locked, retry in %d seconds", retryAfterSeconds(retryAfter)),
+	}
+}
+
+func retryAfterSeconds(retryAfter time.Duration) int {
+	return int((retryAfter + time.Second - 1) / time.Second)
+}
+
 func NewNotSessionError(err error) *ServiceError {
 	return &ServiceError{
 		Err:     err,
@@ -161,6 +184,15 @@ func NewExpiredDate(err error) *ServiceError {
 	}
 }
 
+func NewRefreshTokenReuseError(err error) *ServiceError {
+	return &ServiceError{
+		Err:     err,
+		Blame:   BlameUser,
+		Code:    http.StatusUnauthorized,
+		Message: "refresh token reuse detected, all sessions of this login were revoked",
+	}
+}
+
 func NewCreateJWTError(err error) *ServiceError {
 	return &ServiceError{
 		Err:     err,
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing/filter"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/google/uuid"
+)
+
+// AuditEvent records a change, who made it and the request it was made in.
+type AuditEvent struct {
+	base.EntityWithIdKey
+	ActorType enum.AuditActor `json:"actor_type"`
+	// ActorID is ID of the user or the service account, nil for anonymous requests.
+	ActorID    *uuid.UUID       `json:"actor_id" gorm:"type:uuid;index"`
+	Action     enum.AuditAction `json:"action" gorm:"index"`
+	TargetType enum.AuditTarget `json:"target_type" gorm:"index:idx_audit_event_target"`
+	TargetID   uuid.UUID        `json:"target_id" gorm:"type:uuid;index:idx_audit_event_target"`
+	// Changes are the changed fields of the target, keyed by the field name.
+	Changes   map[string]AuditChange `json:"changes" gorm:"serializer:json"`
+	IPAddress string                 `json:"ip_address"`
+	RequestID string                 `json:"request_id" gorm:"index"`
+}
+
+// AuditChange is a value of a field before and after the change, nil if the field didn't exist.
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+func (AuditEvent) FilteringRules() map[string]map[string]enum.ValidateType {
+	return filter.GetFilterRules(
+		base.EntityWithIdKey{},
+		"audit_events",
+		map[string]map[string]enum.ValidateType{
+			"audit_events": {
+				"actor_type":  enum.TYPE_STRING,
+				"actor_id":    enum.TYPE_UUID,
+				"action":      enum.TYPE_STRING,
+				"target_type": enum.TYPE_STRING,
+				"target_id":   enum.TYPE_UUID,
+				"ip_address":  enum.TYPE_STRING,
+				"request_id":  enum.TYPE_STRING,
+			},
+		})
+}
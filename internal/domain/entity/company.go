@@ -7,10 +7,13 @@
 	"github.com/google/uuid"
 )
 
+// Company is hidden once archived. Members keep reading its candidates until the archive is purged.
 type Company struct {
-	base.EntityWithIdKey
+	base.ArchivableEntityWithIdKey
 
-	Name        string     `json:"name"`
+	Name string `json:"name"`
+	// Slug is unique among all companies including archived ones, former slugs are kept as CompanySlugRedirect.
+	Slug        string     `json:"slug" gorm:"uniqueIndex"`
 	Description string     `json:"description"`
 	Owner       uuid.UUID  `json:"owner"`
 	FileID      *uuid.UUID `json:"avatar_id"`
@@ -21,7 +24,7 @@ type Company struct {
 
 func (Company) FilteringRules() map[string]map[string]enum.ValidateType {
 	return filter.GetFilterRules(
-		base.EntityWithIdKey{},
+		base.ArchivableEntityWithIdKey{},
 		"companies",
 		map[string]map[string]enum.ValidateType{
 			"companies": {
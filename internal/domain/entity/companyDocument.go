@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/google/uuid"
+	"time"
+)
+
+// CompanyDocument is a contract, NDA or other file of the company kept in the document bucket.
+// FileType is the extension the file is stored with.
+type CompanyDocument struct {
+	base.EntityWithIdKey
+	CompanyID  uuid.UUID         `json:"company_id" gorm:"type:uuid;index"`
+	Type       enum.DocumentType `json:"type"`
+	Title      string            `json:"title"`
+	FileID     uuid.UUID         `json:"file_id" gorm:"type:uuid"`
+	File       *File             `json:"file"`
+	FileName   string            `json:"file_name"`
+	FileType   string            `json:"file_type"`
+	Size       int64             `json:"size"`
+	ValidFrom  *time.Time        `json:"valid_from"`
+	ValidUntil *time.Time        `json:"valid_until"`
+	UploadedBy uuid.UUID         `json:"uploaded_by" gorm:"type:uuid"`
+}
@@ -5,9 +5,10 @@
 	"github.com/google/uuid"
 )
 
-// File is a general object stored in s3.
+// File is a general object stored in s3. A picture with Renditions is stored in several sizes under the same key.
 type File struct {
 	base.EntityWithIdKey
-	Key    uuid.UUID `json:"key" example:"00000000-0000-0000-0000-000000000000"`
-	Bucket string    `json:"bucket"`
+	Key        uuid.UUID `json:"key" example:"00000000-0000-0000-0000-000000000000"`
+	Bucket     string    `json:"bucket"`
+	Renditions bool      `json:"renditions" gorm:"not null;default:false"`
 }
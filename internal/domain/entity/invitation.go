@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Invitation lets a person who has no account yet join the company with the given role.
+// An invitation with UserID is sent to a registered user, who joins by accepting it when signed in.
+// Only the hash of the emailed token is stored.
+type Invitation struct {
+	base.EntityWithIdKey
+	CompanyID  uuid.UUID  `json:"company_id" gorm:"type:uuid;index"`
+	Company    *Company   `json:"company"`
+	Email      string     `json:"email" gorm:"index"`
+	UserID     *uuid.UUID `json:"user_id" gorm:"type:uuid;index"`
+	Role       enum.Role  `json:"role"`
+	InvitedBy  uuid.UUID  `json:"invited_by" gorm:"type:uuid"`
+	TokenHash  string     `json:"-" gorm:"uniqueIndex"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+}
+
+func (i *Invitation) IsValid(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiresAt)
+}
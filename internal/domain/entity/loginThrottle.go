@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing/filter"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"time"
+)
+
+// LoginThrottle counts recent failed login attempts of an account (by email) or of a client IP.
+type LoginThrottle struct {
+	base.EntityWithIdKey
+	Scope         enum.ThrottleScope `json:"scope" gorm:"uniqueIndex:idx_login_throttle_subject"`
+	Subject       string             `json:"subject" gorm:"uniqueIndex:idx_login_throttle_subject"`
+	Failures      int                `json:"failures"`
+	LastFailureAt time.Time          `json:"last_failure_at" gorm:"index"`
+	LockedUntil   *time.Time         `json:"locked_until"`
+}
+
+func (t *LoginThrottle) IsLocked(now time.Time) bool {
+	return t.LockedUntil != nil && now.Before(*t.LockedUntil)
+}
+
+// LockoutEvent records a temporary lockout for administrators.
+type LockoutEvent struct {
+	base.EntityWithIdKey
+	Scope       enum.ThrottleScope `json:"scope"`
+	Subject     string             `json:"subject"`
+	IPAddress   string             `json:"ip_address"`
+	Failures    int                `json:"failures"`
+	LockedUntil time.Time          `json:"locked_until"`
+}
+
+func (LockoutEvent) FilteringRules() map[string]map[string]enum.ValidateType {
+	return filter.GetFilterRules(
+		base.EntityWithIdKey{},
+		"lockout_events",
+		map[string]map[string]enum.ValidateType{
+			"lockout_events": {
+				"scope":      enum.TYPE_STRING,
+				"subject":    enum.TYPE_STRING,
+				"ip_address": enum.TYPE_STRING,
+			},
+		})
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/google/uuid"
+	"time"
+)
+
+// OIDCAuthRequest is a login started at an OpenID Connect provider and not yet completed.
+// It is looked up by the hash of the state parameter and consumed by the callback.
+type OIDCAuthRequest struct {
+	base.EntityWithIdKey
+	Provider  string `json:"provider"`
+	StateHash string `json:"-" gorm:"uniqueIndex"`
+	Nonce     string `json:"-"`
+	// CodeVerifier is the PKCE secret, only its challenge was sent to the provider.
+	CodeVerifier string    `json:"-"`
+	ExpiresAt    time.Time `json:"expires_at" gorm:"index"`
+}
+
+// TableName overrides the default naming, which would split the acronym into o_id_c_auth_requests.
+func (OIDCAuthRequest) TableName() string {
+	return "oidc_auth_requests"
+}
+
+// ExternalIdentity links a user to an account at an OpenID Connect provider.
+type ExternalIdentity struct {
+	base.EntityWithIdKey
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;index"`
+	Provider    string    `json:"provider" gorm:"uniqueIndex:idx_external_identity_subject"`
+	Subject     string    `json:"subject" gorm:"uniqueIndex:idx_external_identity_subject"`
+	Email       string    `json:"email"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/google/uuid"
+	"time"
+)
+
+// OneTimeToken is a secret sent to the user by email, e.g. to reset the password.
+// Only the hash of the secret is stored. The token can be used once and only before ExpiresAt.
+type OneTimeToken struct {
+	base.EntityWithIdKey
+	UserID    uuid.UUID         `json:"user_id" gorm:"type:uuid;index"`
+	Purpose   enum.TokenPurpose `json:"purpose"`
+	TokenHash string            `json:"-" gorm:"uniqueIndex"`
+	// Payload is purpose specific data bound to the token, e.g. the email being verified.
+	Payload   string     `json:"payload"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"index"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+func (t *OneTimeToken) IsValid(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/google/uuid"
+)
+
+type Role struct {
+	base.EntityWithIdKey
+	Name        enum.Role        `json:"name" gorm:"uniqueIndex"`
+	Description string           `json:"description"`
+	Permissions []RolePermission `json:"permissions" gorm:"constraint:OnDelete:CASCADE;"`
+}
+
+type RolePermission struct {
+	base.EntityWithIdKey
+	RoleID     uuid.UUID       `json:"role_id" gorm:"type:uuid;uniqueIndex:idx_role_permission"`
+	Permission enum.Permission `json:"permission" gorm:"uniqueIndex:idx_role_permission"`
+}
+
+// UserRole grants a role to a user. Company roles are granted within CompanyID, platform roles have no company.
+type UserRole struct {
+	base.EntityWithIdKey
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;index"`
+	RoleID    uuid.UUID  `json:"role_id" gorm:"type:uuid"`
+	Role      *Role      `json:"role"`
+	CompanyID *uuid.UUID `json:"company_id" gorm:"type:uuid;index"`
+	GrantedBy *uuid.UUID `json:"granted_by" gorm:"type:uuid"`
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/google/uuid"
+	"time"
+)
+
+// ServiceAccount is a non-human client, e.g. an ATS sync job, authenticating with API keys.
+type ServiceAccount struct {
+	base.EntityWithIdKey
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// CompanyID restricts the account to a single company, nil means no restriction.
+	CompanyID *uuid.UUID `json:"company_id" gorm:"type:uuid"`
+	CreatedBy uuid.UUID  `json:"created_by" gorm:"type:uuid"`
+	APIKeys   []APIKey   `json:"api_keys" gorm:"constraint:OnDelete:CASCADE;"`
+}
+
+// APIKey is a secret of a service account. Prefix identifies the key and is stored in clear,
+// only the hash of the whole key is stored.
+type APIKey struct {
+	base.EntityWithIdKey
+	ServiceAccountID uuid.UUID       `json:"service_account_id" gorm:"type:uuid;index"`
+	ServiceAccount   *ServiceAccount `json:"service_account"`
+	Name             string          `json:"name"`
+	Prefix           string          `json:"prefix" gorm:"uniqueIndex"`
+	KeyHash          string          `json:"-"`
+	Scopes           []enum.APIScope `json:"scopes" gorm:"serializer:json"`
+	ExpiresAt        *time.Time      `json:"expires_at"`
+	LastUsedAt       *time.Time      `json:"last_used_at"`
+	RevokedAt        *time.Time      `json:"revoked_at"`
+}
+
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
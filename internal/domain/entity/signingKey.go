@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"time"
+)
+
+// SigningKey is an asymmetric key pair used to sign access tokens.
+// A key is published in JWKS from its creation, signs new tokens from ActivatesAt until a newer
+// key is activated and stays published until ExpiresAt, so tokens it signed can still be verified.
+type SigningKey struct {
+	base.EntityWithIdKey
+	Kid         string    `json:"kid" gorm:"uniqueIndex"`
+	Algorithm   string    `json:"algorithm"`
+	PrivateKey  string    `json:"-"`
+	PublicKey   string    `json:"public_key"`
+	ActivatesAt time.Time `json:"activates_at" gorm:"not null;default:now()"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"index"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/google/uuid"
+)
+
+// Slugs of companies and vacancies whose names have no letters or digits.
+const (
+	CompanySlugFallback = "company"
+	VacancySlugFallback = "vacancy"
+)
+
+// CompanySlugRedirect is a former slug of the company, links with it lead to the current slug.
+type CompanySlugRedirect struct {
+	base.EntityWithIdKey
+	Slug      string    `json:"slug" gorm:"uniqueIndex"`
+	CompanyID uuid.UUID `json:"company_id" gorm:"type:uuid;index"`
+}
+
+// VacancySlugRedirect is a former slug of the vacancy, links with it lead to the current slug.
+type VacancySlugRedirect struct {
+	base.EntityWithIdKey
+	CompanyID uuid.UUID `json:"company_id" gorm:"type:uuid;uniqueIndex:idx_vacancy_slug_redirect"`
+	Slug      string    `json:"slug" gorm:"uniqueIndex:idx_vacancy_slug_redirect"`
+	VacancyID uuid.UUID `json:"vacancy_id" gorm:"type:uuid;index"`
+}
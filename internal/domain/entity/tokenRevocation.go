@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/google/uuid"
+	"time"
+)
+
+// TokenRevocation invalidates access tokens before they expire. It revokes either
+// a single token by its ID, every token issued for the session family SessionID,
+// or every token of the user issued before RevokedBefore.
+// The record is useless after ExpiresAt, because all tokens it covers have expired by then.
+type TokenRevocation struct {
+	base.EntityWithIdKey
+	TokenID       *uuid.UUID `json:"token_id" gorm:"type:uuid;uniqueIndex"`
+	SessionID     *uuid.UUID `json:"session_id" gorm:"type:uuid;index"`
+	UserID        uuid.UUID  `json:"user_id" gorm:"type:uuid;index"`
+	RevokedBefore *time.Time `json:"revoked_before"`
+	ExpiresAt     time.Time  `json:"expires_at" gorm:"index"`
+}
@@ -5,20 +5,47 @@
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
 	"github.com/google/uuid"
+	"time"
 )
 
 // User represents general system user (student or teacher).
 type User struct {
 	base.EntityWithIdKey
 
-	Name      string     `json:"name"`
-	Email     string     `json:"email" gorm:"uniqueIndex"`
-	Password  string     `json:"password"`
+	Name  string `json:"name"`
+	Email string `json:"email" gorm:"uniqueIndex"`
+	// EmailVerifiedAt is nil until the user confirms Email.
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// PendingEmail is the requested new email, it replaces Email once confirmed.
+	PendingEmail string `json:"pending_email"`
+	Password     string `json:"password"`
+	// PasswordChangeRequired is set for users with a generated password, they have to replace it on the first login.
+	PasswordChangeRequired bool `json:"password_change_required" gorm:"not null;default:false"`
+	// TOTPSecret is set on enrollment, two-factor authentication is on only once TOTPEnabledAt is set.
+	TOTPSecret    string     `json:"-"`
+	TOTPEnabledAt *time.Time `json:"totp_enabled_at"`
+	// TOTPLastStep is the time step of the last accepted code, a code can't be used twice.
+	TOTPLastStep int64        `json:"-"`
+	BackupCodes  []BackupCode `json:"-" gorm:"constraint:OnDelete:CASCADE;"`
+	// ExternalIdentities are accounts at OpenID Connect providers the user signs in with.
+	ExternalIdentities []ExternalIdentity `json:"-" gorm:"constraint:OnDelete:CASCADE;"`
+	// AvatarID is nil until the user uploads a picture, a generated one is shown meanwhile.
+	AvatarID  *uuid.UUID `json:"avatar_id"`
+	Avatar    *File      `json:"-"`
 	Company   *Company   `json:"company"`
 	CompanyID *uuid.UUID `json:"companyID"`
+	Roles     []UserRole `json:"-" gorm:"constraint:OnDelete:CASCADE;"`
 	Sessions  []Session  `json:"sessions,omitempty"`
 }
 
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
 func (User) FilteringRules() map[string]map[string]enum.ValidateType {
 	return filter.GetFilterRules(
 		base.EntityWithIdKey{},
@@ -31,9 +58,35 @@ func (User) FilteringRules() map[string]map[string]enum.ValidateType {
 		})
 }
 
+// Session is a refresh token. Every refresh rotates the session: the presented one is
+// marked as rotated and a new one from the same family is issued.
 type Session struct {
 	base.EntityWithIdKey
-	UserID   uuid.UUID `json:"user_id"`
-	User     *User     `json:"user"`
-	DeviceID string    `json:"device_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	User      *User     `json:"user"`
+	DeviceID  string    `json:"device_id"`
+	UserAgent string    `json:"user_agent"`
+	IPAddress string    `json:"ip_address"`
+	// StartedAt is the login time of the family, it is kept on rotation.
+	StartedAt  time.Time `json:"started_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	// FamilyID is shared by all sessions created from a single login through rotation.
+	FamilyID uuid.UUID `json:"family_id" gorm:"type:uuid;index"`
+	// ExpiresAt is the sliding expiry, it moves forward on every refresh.
+	ExpiresAt time.Time `json:"expires_at"`
+	// AbsoluteExpiresAt is the hard limit of the whole family, refresh never moves it.
+	AbsoluteExpiresAt time.Time  `json:"absolute_expires_at"`
+	RotatedAt         *time.Time `json:"rotated_at"`
+}
+
+func (s *Session) IsExpired(now time.Time) bool {
+	return now.After(s.ExpiresAt) || now.After(s.AbsoluteExpiresAt)
+}
+
+// BackupCode is a single-use recovery code replacing a TOTP code when the authenticator is lost.
+type BackupCode struct {
+	base.EntityWithIdKey
+	UserID   uuid.UUID  `json:"user_id" gorm:"type:uuid;index"`
+	CodeHash string     `json:"-"`
+	UsedAt   *time.Time `json:"used_at"`
 }
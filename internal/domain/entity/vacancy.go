@@ -5,17 +5,22 @@
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
 	"github.com/google/uuid"
+	"time"
 )
 
 type Vacancy struct {
 	base.EntityWithIdKey
-	Name        string      `json:"name"`
-	Salary      int         `json:"salary"`
-	City        string      `json:"city"`
-	Description string      `json:"description"`
-	Candidates  []Candidate `json:"candidates" `
-	Company     Company     `json:"company"`
-	CompanyID   uuid.UUID   `json:"company_id"`
+	Name string `json:"name"`
+	// Slug is unique within the company, former slugs are kept as VacancySlugRedirect.
+	Slug        string `json:"slug" gorm:"uniqueIndex:idx_vacancies_company_slug"`
+	Salary      int    `json:"salary"`
+	City        string `json:"city"`
+	Description string `json:"description"`
+	// ClosedAt is set when the vacancy stops taking candidates, e.g. once the company is archived.
+	ClosedAt   *time.Time  `json:"closed_at"`
+	Candidates []Candidate `json:"candidates" `
+	Company    Company     `json:"company"`
+	CompanyID  uuid.UUID   `json:"company_id" gorm:"uniqueIndex:idx_vacancies_company_slug"`
 }
 
 func (Vacancy) FilteringRules() map[string]map[string]enum.ValidateType {
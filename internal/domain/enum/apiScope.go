@@ -0,0 +1,29 @@
+package enum
+
+// APIScope limits what an API key of a service account is allowed to do.
+type APIScope string
+
+const (
+	ScopeUsersRead       APIScope = "users:read"
+	ScopeCompaniesRead   APIScope = "companies:read"
+	ScopeCompaniesWrite  APIScope = "companies:write"
+	ScopeVacanciesRead   APIScope = "vacancies:read"
+	ScopeVacanciesWrite  APIScope = "vacancies:write"
+	ScopeCandidatesRead  APIScope = "candidates:read"
+	ScopeCandidatesWrite APIScope = "candidates:write"
+)
+
+func (s APIScope) IsValid() bool {
+	switch s {
+	case ScopeUsersRead,
+		ScopeCompaniesRead,
+		ScopeCompaniesWrite,
+		ScopeVacanciesRead,
+		ScopeVacanciesWrite,
+		ScopeCandidatesRead,
+		ScopeCandidatesWrite:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,73 @@
+package enum
+
+// AuditAction names a change recorded in the audit log.
+type AuditAction string
+
+const (
+	AuditUserRegister         AuditAction = "user.register"
+	AuditUserUpdate           AuditAction = "user.update"
+	AuditUserDelete           AuditAction = "user.delete"
+	AuditUserRestore          AuditAction = "user.restore"
+	AuditUserPurge            AuditAction = "user.purge"
+	AuditUserImport           AuditAction = "user.import"
+	AuditUserPasswordReset    AuditAction = "user.password_reset"
+	AuditUserPasswordChange   AuditAction = "user.password_change"
+	AuditUserEmailVerify      AuditAction = "user.email_verify"
+	AuditUserAvatarUpload     AuditAction = "user.avatar_upload"
+	AuditUserAvatarDelete     AuditAction = "user.avatar_delete"
+	AuditUserProvision        AuditAction = "user.provision"
+	AuditIdentityLink         AuditAction = "identity.link"
+	AuditSessionRevoke        AuditAction = "session.revoke"
+	AuditTwoFactorEnable      AuditAction = "two_factor.enable"
+	AuditTwoFactorDisable     AuditAction = "two_factor.disable"
+	AuditTwoFactorBackupCodes AuditAction = "two_factor.backup_codes"
+	AuditCompanyCreate        AuditAction = "company.create"
+	AuditCompanyUpdate        AuditAction = "company.update"
+	AuditCompanyArchive       AuditAction = "company.archive"
+	AuditCompanyPurge         AuditAction = "company.purge"
+	AuditCompanyLogoUpload    AuditAction = "company.logo_upload"
+	AuditDocumentUpload       AuditAction = "document.upload"
+	AuditDocumentDelete       AuditAction = "document.delete"
+	AuditMemberAdd            AuditAction = "company.member_add"
+	AuditMemberRemove         AuditAction = "company.member_remove"
+	AuditMemberLeave          AuditAction = "company.member_leave"
+	AuditOwnershipTransfer    AuditAction = "company.ownership_transfer"
+	AuditVacancyCreate        AuditAction = "vacancy.create"
+	AuditVacancyUpdate        AuditAction = "vacancy.update"
+	AuditCandidateCreate      AuditAction = "candidate.create"
+	AuditInvitationCreate     AuditAction = "invitation.create"
+	AuditInvitationRevoke     AuditAction = "invitation.revoke"
+	AuditInvitationAccept     AuditAction = "invitation.accept"
+	AuditRoleGrant            AuditAction = "role.grant"
+	AuditRoleRevoke           AuditAction = "role.revoke"
+	AuditServiceAccountCreate AuditAction = "service_account.create"
+	AuditServiceAccountDelete AuditAction = "service_account.delete"
+	AuditAPIKeyCreate         AuditAction = "api_key.create"
+	AuditAPIKeyRevoke         AuditAction = "api_key.revoke"
+)
+
+// AuditTarget is the kind of entity an audited change was made to.
+type AuditTarget string
+
+const (
+	AuditTargetUser           AuditTarget = "user"
+	AuditTargetSession        AuditTarget = "session"
+	AuditTargetCompany        AuditTarget = "company"
+	AuditTargetDocument       AuditTarget = "document"
+	AuditTargetVacancy        AuditTarget = "vacancy"
+	AuditTargetCandidate      AuditTarget = "candidate"
+	AuditTargetInvitation     AuditTarget = "invitation"
+	AuditTargetRoleGrant      AuditTarget = "role_grant"
+	AuditTargetServiceAccount AuditTarget = "service_account"
+	AuditTargetAPIKey         AuditTarget = "api_key"
+)
+
+// AuditActor tells who made an audited change.
+type AuditActor string
+
+const (
+	AuditActorUser           AuditActor = "user"
+	AuditActorServiceAccount AuditActor = "service_account"
+	// AuditActorAnonymous is a request without credentials, e.g. password reset by an emailed link.
+	AuditActorAnonymous AuditActor = "anonymous"
+)
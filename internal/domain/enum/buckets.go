@@ -4,4 +4,6 @@
 
 const (
 	CompanyLogo Bucket = "company-logo"
+	UserAvatar  Bucket = "user-avatar"
+	Document    Bucket = "document"
 )
@@ -0,0 +1,20 @@
+package enum
+
+// CompanyInclude is a kind of children loaded along with companies.
+type CompanyInclude string
+
+const (
+	CompanyIncludeUsers     CompanyInclude = "users"
+	CompanyIncludeVacancies CompanyInclude = "vacancies"
+	// CompanyIncludeCandidates are shown within vacancies, so they include vacancies too.
+	CompanyIncludeCandidates CompanyInclude = "candidates"
+)
+
+func (i CompanyInclude) IsValid() bool {
+	switch i {
+	case CompanyIncludeUsers, CompanyIncludeVacancies, CompanyIncludeCandidates:
+		return true
+	}
+
+	return false
+}
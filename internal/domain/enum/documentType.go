@@ -0,0 +1,21 @@
+package enum
+
+// DocumentType is the kind of a company document.
+type DocumentType string
+
+const (
+	DocumentContract DocumentType = "contract"
+	DocumentNDA      DocumentType = "nda"
+	// DocumentRequisites are bank and legal details of the company.
+	DocumentRequisites DocumentType = "requisites"
+	DocumentOther      DocumentType = "other"
+)
+
+func (t DocumentType) IsValid() bool {
+	switch t {
+	case DocumentContract, DocumentNDA, DocumentRequisites, DocumentOther:
+		return true
+	}
+
+	return false
+}
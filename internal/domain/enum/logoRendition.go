@@ -0,0 +1,27 @@
+package enum
+
+// LogoRendition is a size company logos are stored in.
+type LogoRendition string
+
+const (
+	LogoThumbnail LogoRendition = "thumbnail"
+	LogoCard      LogoRendition = "card"
+	LogoFull      LogoRendition = "full"
+)
+
+// LogoRenditions are all the sizes, from the smallest to the largest.
+var LogoRenditions = []LogoRendition{LogoThumbnail, LogoCard, LogoFull}
+
+// Width is the width of the rendition in pixels, the height keeps the proportions of the logo.
+func (r LogoRendition) Width() int {
+	switch r {
+	case LogoThumbnail:
+		return 64
+	case LogoCard:
+		return 256
+	case LogoFull:
+		return 1024
+	}
+
+	return 0
+}
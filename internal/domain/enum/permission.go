@@ -0,0 +1,60 @@
+package enum
+
+// Permission allows a single kind of operation. Permissions are granted to users through roles.
+type Permission string
+
+const (
+	PermissionManageUsers           Permission = "users:manage"
+	PermissionManageRoles           Permission = "roles:manage"
+	PermissionManageServiceAccounts Permission = "service_accounts:manage"
+	PermissionReadLockouts          Permission = "lockouts:read"
+	PermissionReadAuditLog          Permission = "audit:read"
+	PermissionManageCompany         Permission = "company:manage"
+	PermissionManageMembers         Permission = "members:manage"
+	PermissionWriteVacancies        Permission = "vacancies:write"
+	PermissionReadCandidates        Permission = "candidates:read"
+	PermissionWriteCandidates       Permission = "candidates:write"
+	PermissionReadDocuments         Permission = "documents:read"
+	PermissionWriteDocuments        Permission = "documents:write"
+)
+
+// DefaultRolePermissions are the permissions roles are created with. Later changes of the stored roles are kept.
+var DefaultRolePermissions = map[Role][]Permission{
+	RolePlatformAdmin: {
+		PermissionManageUsers,
+		PermissionManageRoles,
+		PermissionManageServiceAccounts,
+		PermissionReadLockouts,
+		PermissionReadAuditLog,
+		PermissionManageCompany,
+		PermissionManageMembers,
+		PermissionWriteVacancies,
+		PermissionReadCandidates,
+		PermissionWriteCandidates,
+		PermissionReadDocuments,
+		PermissionWriteDocuments,
+	},
+	RoleCompanyOwner: {
+		PermissionManageCompany,
+		PermissionManageMembers,
+		PermissionWriteVacancies,
+		PermissionReadCandidates,
+		PermissionWriteCandidates,
+		PermissionReadDocuments,
+		PermissionWriteDocuments,
+	},
+	RoleRecruiter: {
+		PermissionWriteVacancies,
+		PermissionReadCandidates,
+		PermissionWriteCandidates,
+		PermissionReadDocuments,
+		PermissionWriteDocuments,
+	},
+	RoleHiringManager: {
+		PermissionReadCandidates,
+		PermissionWriteCandidates,
+	},
+	RoleViewer: {
+		PermissionReadCandidates,
+	},
+}
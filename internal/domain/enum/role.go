@@ -0,0 +1,37 @@
+package enum
+
+// Role is a named set of permissions. Platform roles are granted globally,
+// company roles are granted within a single company.
+type Role string
+
+const (
+	RolePlatformAdmin Role = "platform_admin"
+	RoleCompanyOwner  Role = "company_owner"
+	RoleRecruiter     Role = "recruiter"
+	RoleHiringManager Role = "hiring_manager"
+	RoleViewer        Role = "viewer"
+)
+
+func (r Role) IsValid() bool {
+	switch r {
+	case RolePlatformAdmin, RoleCompanyOwner, RoleRecruiter, RoleHiringManager, RoleViewer:
+		return true
+	}
+
+	return false
+}
+
+// IsCompanyRole tells whether the role is granted within a company.
+func (r Role) IsCompanyRole() bool {
+	return r.IsValid() && r != RolePlatformAdmin
+}
+
+// IsInvitable tells whether new members can be invited into a company with the role.
+func (r Role) IsInvitable() bool {
+	switch r {
+	case RoleRecruiter, RoleHiringManager, RoleViewer:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,9 @@
+package enum
+
+// ThrottleScope tells what login attempts are counted against.
+type ThrottleScope string
+
+const (
+	ThrottleAccount ThrottleScope = "account"
+	ThrottleIP      ThrottleScope = "ip"
+)
@@ -0,0 +1,9 @@
+package enum
+
+// TokenPurpose tells what a one-time token sent to the user can be used for.
+type TokenPurpose string
+
+const (
+	PasswordReset     TokenPurpose = "password-reset"
+	EmailVerification TokenPurpose = "email-verification"
+)
@@ -0,0 +1,13 @@
+package enum
+
+// UserImportStatus is the outcome of a single row of a user import.
+type UserImportStatus string
+
+const (
+	// UserImportValid is a row which passed validation of a dry run.
+	UserImportValid   UserImportStatus = "valid"
+	UserImportInvalid UserImportStatus = "invalid"
+	UserImportCreated UserImportStatus = "created"
+	// UserImportFailed is a valid row the user could not be created for.
+	UserImportFailed UserImportStatus = "failed"
+)
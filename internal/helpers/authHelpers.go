@@ -13,3 +13,18 @@ func GetDeviceID(c *gin.Context) string {
 	hasher.Write([]byte(userAgent))
 	return hex.EncodeToString(hasher.Sum(nil))
 }
+
+// ClientInfo describes the client a session was opened from.
+type ClientInfo struct {
+	DeviceID  string
+	UserAgent string
+	IPAddress string
+}
+
+func GetClientInfo(c *gin.Context) ClientInfo {
+	return ClientInfo{
+		DeviceID:  GetDeviceID(c),
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
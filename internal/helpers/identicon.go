@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"image"
+	"image/color"
+)
+
+const identiconGrid = 5
+
+var identiconBackground = color.RGBA{R: 240, G: 240, B: 240, A: 255}
+
+// GenerateIdenticon draws a symmetric 5x5 pattern derived from the seed, so the same seed always
+// gives the same picture. The size is rounded down to a multiple of the grid with a margin of half a cell.
+func GenerateIdenticon(seed []byte, size int) image.Image {
+	hash := sha256.Sum256(seed)
+
+	cell := size / (identiconGrid + 1)
+	if cell < 1 {
+		cell = 1
+	}
+	margin := cell / 2
+	side := cell*identiconGrid + margin*2
+
+	foreground := color.RGBA{R: hash[0], G: hash[1], B: hash[2], A: 255}
+	// too light colors are hardly visible on the background
+	if int(foreground.R)+int(foreground.G)+int(foreground.B) > 600 {
+		foreground.R /= 2
+		foreground.G /= 2
+		foreground.B /= 2
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, side, side))
+	fillRect(img, img.Bounds(), identiconBackground)
+
+	for row := 0; row < identiconGrid; row++ {
+		// only the left half and the middle column are derived from the hash, the right half mirrors them
+		for column := 0; column <= identiconGrid/2; column++ {
+			if hash[3+row*3+column]%2 == 0 {
+				continue
+			}
+
+			for _, x := range []int{column, identiconGrid - 1 - column} {
+				rect := image.Rect(margin+x*cell, margin+row*cell, margin+(x+1)*cell, margin+(row+1)*cell)
+				fillRect(img, rect, foreground)
+			}
+		}
+	}
+
+	return img
+}
+
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
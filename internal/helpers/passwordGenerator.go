@@ -1,44 +1,63 @@
 package helpers
 
 import (
-	"math/rand"
+	"crypto/rand"
+	"math/big"
 	"strings"
 )
 
 var (
-	lowerCharSet   = "abcdedfghijklmnopqrst"
+	lowerCharSet   = "abcdefghijklmnopqrstuvwxyz"
 	upperCharSet   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 	specialCharSet = "!@#$%&*_-+=.,"
 	numberSet      = "0123456789"
 	allCharSet     = lowerCharSet + upperCharSet + specialCharSet + numberSet
 )
 
-func GeneratePassword(passwordLength, minSpecialChar, minNum, minUpperCase int) string {
+// GeneratePassword returns a random password with at least the requested number of special characters,
+// digits and upper case letters. The randomness comes from crypto/rand, so the password can be handed out as is.
+func GeneratePassword(passwordLength, minSpecialChar, minNum, minUpperCase int) (string, error) {
 	var password strings.Builder
 
-	for i := 0; i < minSpecialChar; i++ {
-		random := rand.Intn(len(specialCharSet))
-		password.WriteString(string(specialCharSet[random]))
+	sets := []struct {
+		charSet string
+		count   int
+	}{
+		{specialCharSet, minSpecialChar},
+		{numberSet, minNum},
+		{upperCharSet, minUpperCase},
+		{allCharSet, passwordLength - minSpecialChar - minNum - minUpperCase},
 	}
 
-	for i := 0; i < minNum; i++ {
-		random := rand.Intn(len(numberSet))
-		password.WriteString(string(numberSet[random]))
+	for _, set := range sets {
+		for i := 0; i < set.count; i++ {
+			random, err := randomIndex(len(set.charSet))
+			if err != nil {
+				return "", err
+			}
+			password.WriteByte(set.charSet[random])
+		}
 	}
 
-	for i := 0; i < minUpperCase; i++ {
-		random := rand.Intn(len(upperCharSet))
-		password.WriteString(string(upperCharSet[random]))
+	inRune := []rune(password.String())
+	// Fisher-Yates shuffle, the required characters must not stay at the start
+	for i := len(inRune) - 1; i > 0; i-- {
+		j, err := randomIndex(i + 1)
+		if err != nil {
+			return "", err
+		}
+		inRune[i], inRune[j] = inRune[j], inRune[i]
 	}
 
-	remainingLength := passwordLength - minSpecialChar - minNum - minUpperCase
-	for i := 0; i < remainingLength; i++ {
-		random := rand.Intn(len(allCharSet))
-		password.WriteString(string(allCharSet[random]))
+	return string(inRune), nil
+}
+
+// randomIndex returns a uniformly distributed random number in [0, n).
+func randomIndex(n int) (int, error) {
+	random, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
 	}
-	inRune := []rune(password.String())
-	rand.Shuffle(len(inRune), func(i, j int) {
-		inRune[i], inRune[j] = inRune[j], inRune[i]
-	})
-	return string(inRune)
+
+	return int(random.Int64()), nil
 }
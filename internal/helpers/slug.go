@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxSlugLength leaves room for the numeric suffix added by UniqueSlug.
+const maxSlugLength = 64
+
+// Slugify makes a URL part of lowercase latin letters, digits and dashes from the text. Cyrillic is transliterated
+// and diacritics are dropped. The fallback is returned if nothing is left of the text.
+func Slugify(text string, fallback string) string {
+	var slug strings.Builder
+	dash := false
+
+	// the decomposition separates diacritics from the letters
+	for _, char := range norm.NFD.String(Transliterate(text)) {
+		if unicode.Is(unicode.Mn, char) {
+			continue
+		}
+
+		if (char >= 'a' && char <= 'z') || (char >= '0' && char <= '9') {
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			dash = false
+			slug.WriteRune(char)
+		} else {
+			dash = true
+		}
+
+		if slug.Len() >= maxSlugLength {
+			break
+		}
+	}
+
+	if slug.Len() == 0 {
+		return fallback
+	}
+
+	return slug.String()
+}
+
+// UniqueSlug returns the slug, or the slug with the smallest numeric suffix, which is not taken.
+func UniqueSlug(slug string, taken map[string]bool) string {
+	if !taken[slug] {
+		return slug
+	}
+
+	for i := 2; ; i++ {
+		candidate := slug + "-" + strconv.Itoa(i)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
@@ -5,10 +5,15 @@
 	"fmt"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/common"
 	"go.uber.org/zap"
+	"mime"
 	"net/mail"
 	"net/smtp"
+	"strings"
 )
 
+// headerLineBreaks are removed from header values, otherwise user input could add headers.
+var headerLineBreaks = strings.NewReplacer("\r", "", "\n", " ")
+
 type MailService struct {
 	smtpConfig common.SmtpConfig
 	logger     *zap.Logger
@@ -32,7 +37,7 @@ func (m *MailService) SendMessage(recipientAdder string, subject string, message
 	header := make(map[string]string)
 	header["From"] = from.String()
 	header["To"] = recipient.String()
-	header["Subject"] = subject
+	header["Subject"] = encodeHeader(subject)
 	header["MIME-Version"] = "1.0"
 	header["Content-Type"] = "text/html; charset=\"utf-8\""
 
@@ -49,3 +54,9 @@ func (m *MailService) SendMessage(recipientAdder string, subject string, message
 
 	return nil
 }
+
+// encodeHeader makes the header value safe to write as is. Line breaks are dropped and
+// non-ASCII text is Q-encoded.
+func encodeHeader(value string) string {
+	return mime.QEncoding.Encode("utf-8", headerLineBreaks.Replace(value))
+}
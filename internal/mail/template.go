@@ -1,6 +1,8 @@
 package mail
 
 import (
+	"fmt"
+	"html"
 	"io"
 	"os"
 	"strings"
@@ -9,7 +11,12 @@
 type TypeTemplate string
 
 const (
-	FreeRequest TypeTemplate = "freeRequest.html"
+	FreeRequest       TypeTemplate = "freeRequest.html"
+	PasswordReset     TypeTemplate = "passwordReset.html"
+	EmailVerification TypeTemplate = "emailVerification.html"
+	Invitation        TypeTemplate = "invitation.html"
+	Credentials       TypeTemplate = "credentials.html"
+	Membership        TypeTemplate = "membership.html"
 )
 
 func LoadTemplate(template TypeTemplate) (*string, error) {
@@ -29,3 +36,14 @@ func LoadTemplate(template TypeTemplate) (*string, error) {
 
 	return &strContent, nil
 }
+
+// FormatTemplate substitutes the arguments into the template. They are HTML escaped,
+// so user input can't add markup to the message.
+func FormatTemplate(template string, args ...any) string {
+	escaped := make([]any, 0, len(args))
+	for _, arg := range args {
+		escaped = append(escaped, html.EscapeString(fmt.Sprint(arg)))
+	}
+
+	return fmt.Sprintf(template, escaped...)
+}
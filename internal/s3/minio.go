@@ -9,11 +9,13 @@
 	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
 	"github.com/nickalie/go-webpbin"
+	"golang.org/x/image/draw"
 	"image"
 	_ "image/gif"  // Добавляем для поддержки формата GIF
 	_ "image/jpeg" // Добавляем для поддержки формата JPEG
 	_ "image/png"  // Добавляем для поддержки формата PNG
 	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"strings"
@@ -62,40 +64,58 @@ func (s *MinioService) UploadAsWebP(ctx context.Context, bucket enum.Bucket, fil
 	}
 
 	// Создаем буфер для сохранения изображения в формате WebP
-	var webpBuffer bytes.Buffer
-
-	err = webpbin.NewCWebP(
-		webpbin.SetSkipDownload(true),
-		webpbin.SetVendorPath(""),
-	).
-		Quality(80).
-		InputImage(img).
-		Output(&webpBuffer).
-		Run()
-
+	webpBuffer, err := encodeWebP(img)
 	if err != nil {
 		return nil, unexpectedServiceError(err)
 	}
 
-	// Определяем новый контент-тайп для WebP
-	contentType := "image/webp"
-
 	// Генерируем новый ключ для WebP файла
 	key := uuid.New()
 	keyStr := key.String() + ".webp"
 
-	// Определяем параметры загрузки для WebP
-	opts := minio.PutObjectOptions{
-		ContentType:  contentType,
-		UserMetadata: map[string]string{"x-amz-acl": "public-read"},
+	// Загружаем WebP изображение в MinIO
+	if err := s.putWebP(ctx, bucket, keyStr, webpBuffer); err != nil {
+		return nil, unexpectedServiceError(err)
 	}
 
-	// Загружаем WebP изображение в MinIO
-	_, err = s.client.PutObject(ctx, string(bucket), keyStr, &webpBuffer, int64(webpBuffer.Len()), opts)
+	return &key, nil
+}
+
+// UploadAsWebPRenditions stores the picture scaled to each of the widths with the proportions kept.
+// The renditions share the returned key and are told apart by the width. Nothing is left stored on failure.
+// A picture is never enlarged, renditions wider than the picture hold it in its own size.
+func (s *MinioService) UploadAsWebPRenditions(ctx context.Context, bucket enum.Bucket, file io.Reader, widths []int) (*uuid.UUID, *base.ServiceError) {
+	img, _, err := image.Decode(file)
 	if err != nil {
 		return nil, unexpectedServiceError(err)
 	}
 
+	// the picture in its own size is encoded once for all the renditions not narrower than it
+	var original []byte
+
+	key := uuid.New()
+	for i, width := range widths {
+		var webpBuffer *bytes.Buffer
+		if width >= img.Bounds().Dx() && original != nil {
+			webpBuffer = bytes.NewBuffer(original)
+		} else {
+			webpBuffer, err = encodeWebP(scaleToWidth(img, width))
+			if err == nil && width >= img.Bounds().Dx() {
+				original = webpBuffer.Bytes()
+			}
+		}
+
+		if err == nil {
+			err = s.putWebP(ctx, bucket, renditionKey(key, width), webpBuffer)
+		}
+
+		if err != nil {
+			// the renditions stored so far are useless without the rest
+			_ = s.DeleteWebPRenditions(ctx, bucket, key, widths[:i])
+			return nil, unexpectedServiceError(err)
+		}
+	}
+
 	return &key, nil
 }
 
@@ -131,6 +151,30 @@ func (s *MinioService) DeleteWebPFile(ctx context.Context, bucket enum.Bucket, f
 	return nil
 }
 
+// GetWebPRenditionURL returns a presigned URL of the rendition of the given width.
+func (s *MinioService) GetWebPRenditionURL(ctx context.Context, bucket enum.Bucket, fileID uuid.UUID, width int) (string, *base.ServiceError) {
+	reqParams := make(url.Values)
+	reqParams.Set("response-content-type", "image/webp")
+
+	resignedURL, err := s.client.PresignedGetObject(ctx, string(bucket), renditionKey(fileID, width), time.Hour, reqParams)
+	if err != nil {
+		return "", unexpectedServiceError(err)
+	}
+
+	return resignedURL.String(), nil
+}
+
+// DeleteWebPRenditions removes the renditions of the given widths.
+func (s *MinioService) DeleteWebPRenditions(ctx context.Context, bucket enum.Bucket, fileID uuid.UUID, widths []int) *base.ServiceError {
+	for _, width := range widths {
+		if err := s.client.RemoveObject(ctx, string(bucket), renditionKey(fileID, width), minio.RemoveObjectOptions{}); err != nil {
+			return unexpectedServiceError(err)
+		}
+	}
+
+	return nil
+}
+
 func (s *MinioService) GetImageFileURL(ctx context.Context, bucket string, fileKey string) (*string, *base.ServiceError) {
 
 	_, err := s.client.StatObject(context.Background(), bucket, fileKey+".png", minio.StatObjectOptions{})
@@ -178,12 +222,37 @@ func (s *MinioService) GetDocumentFileURL(ctx context.Context, bucket string, fi
 	return &urlString, nil
 }
 
+// UploadDocument stores the file in the document bucket with the file type as the extension.
+// Unlike pictures documents are not public, they are downloaded by presigned URLs.
+func (s *MinioService) UploadDocument(ctx context.Context, input UploadInput, fileType string) (*uuid.UUID, *base.ServiceError) {
+	opts := minio.PutObjectOptions{
+		ContentType: input.ContentType,
+	}
+
+	key := uuid.New()
+	objectName := fmt.Sprintf("%s.%s", key.String(), fileType)
+	info, err := s.client.PutObject(ctx, string(enum.Document), objectName, input.File, input.Size, opts)
+	if err != nil {
+		return nil, unexpectedServiceError(err)
+	}
+
+	// the stored object must be exactly the size the document is recorded with
+	if info.Size != input.Size {
+		if err := s.client.RemoveObject(ctx, string(enum.Document), objectName, minio.RemoveObjectOptions{GovernanceBypass: true}); err != nil {
+			return nil, unexpectedServiceError(err)
+		}
+		return nil, unexpectedServiceError(fmt.Errorf("stored %d bytes of %d", info.Size, input.Size))
+	}
+
+	return &key, nil
+}
+
 func (s *MinioService) RemoveDocument(ctx context.Context, fileID uuid.UUID, fileType string) *base.ServiceError {
 	opts := minio.RemoveObjectOptions{
 		GovernanceBypass: true,
 	}
 
-	if err := s.client.RemoveObject(ctx, "document", fmt.Sprintf("%s.%s", fileID.String(), fileType), opts); err != nil {
+	if err := s.client.RemoveObject(ctx, string(enum.Document), fmt.Sprintf("%s.%s", fileID.String(), fileType), opts); err != nil {
 		return unexpectedServiceError(err)
 	}
 
@@ -206,6 +275,56 @@ func (s *MinioService) RemoveImage(ctx context.Context, bucket string, fileID uu
 	return nil
 }
 
+func (s *MinioService) putWebP(ctx context.Context, bucket enum.Bucket, keyStr string, webpBuffer *bytes.Buffer) error {
+	// Определяем параметры загрузки для WebP
+	opts := minio.PutObjectOptions{
+		ContentType:  "image/webp",
+		UserMetadata: map[string]string{"x-amz-acl": "public-read"},
+	}
+
+	_, err := s.client.PutObject(ctx, string(bucket), keyStr, webpBuffer, int64(webpBuffer.Len()), opts)
+	return err
+}
+
+func encodeWebP(img image.Image) (*bytes.Buffer, error) {
+	var webpBuffer bytes.Buffer
+
+	err := webpbin.NewCWebP(
+		webpbin.SetSkipDownload(true),
+		webpbin.SetVendorPath(""),
+	).
+		Quality(80).
+		InputImage(img).
+		Output(&webpBuffer).
+		Run()
+
+	return &webpBuffer, err
+}
+
+// scaleToWidth resizes the picture to the width, the height keeps the proportions.
+// A picture not wider than the width is returned as is, it is never enlarged.
+func scaleToWidth(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if width >= bounds.Dx() {
+		return img
+	}
+
+	height := int(math.Round(float64(bounds.Dy()) * float64(width) / float64(bounds.Dx())))
+	if height < 1 {
+		height = 1
+	}
+
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, bounds, draw.Src, nil)
+
+	return scaled
+}
+
+// renditionKey is the object key of the rendition of the given width.
+func renditionKey(fileID uuid.UUID, width int) string {
+	return fmt.Sprintf("%s_%d.webp", fileID.String(), width)
+}
+
 // unexpectedServiceError returns any unclassified service error.
 func unexpectedServiceError(err error) *base.ServiceError {
 	return &base.ServiceError{
@@ -14,8 +14,12 @@ type ObjectStoreService interface {
 	GetImageFileURL(ctx context.Context, bucket string, fileName string) (*string, *base.ServiceError)
 	GetDocumentFileURL(ctx context.Context, bucket string, fileName string, fileType string) (*string, *base.ServiceError)
 	RemoveImage(ctx context.Context, bucket string, fileID uuid.UUID) *base.ServiceError
+	UploadDocument(ctx context.Context, input UploadInput, fileType string) (*uuid.UUID, *base.ServiceError)
 	RemoveDocument(ctx context.Context, fileID uuid.UUID, fileType string) *base.ServiceError
 	UploadAsWebP(ctx context.Context, bucket enum.Bucket, file io.Reader) (*uuid.UUID, *base.ServiceError)
 	GetWebPFileURL(ctx context.Context, bucket enum.Bucket, fileID uuid.UUID) (string, *base.ServiceError)
 	DeleteWebPFile(ctx context.Context, bucket enum.Bucket, fileID uuid.UUID) *base.ServiceError
+	UploadAsWebPRenditions(ctx context.Context, bucket enum.Bucket, file io.Reader, widths []int) (*uuid.UUID, *base.ServiceError)
+	GetWebPRenditionURL(ctx context.Context, bucket enum.Bucket, fileID uuid.UUID, width int) (string, *base.ServiceError)
+	DeleteWebPRenditions(ctx context.Context, bucket enum.Bucket, fileID uuid.UUID, widths []int) *base.ServiceError
 }
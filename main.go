@@ -10,6 +10,7 @@
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/common"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/helpers"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/mail"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/s3"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/telemetry/log"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/router"
@@ -47,6 +48,18 @@ func main() {
 		logger.Fatal(fmt.Sprintf("error reading config file: %v", err))
 	}
 
+	// CONFIG_OVERRIDE names a file merged over the config for local development only,
+	// such as config/local.yml with the stand-in OIDC provider. It is not copied into the image.
+	if err := viper.BindEnv("configOverride", "CONFIG_OVERRIDE"); err != nil {
+		logger.Fatal(err.Error())
+	}
+	if override := viper.GetString("configOverride"); override != "" {
+		viper.SetConfigFile(override)
+		if err := viper.MergeInConfig(); err != nil {
+			logger.Fatal(fmt.Sprintf("error reading config override %s: %v", override, err))
+		}
+	}
+
 	err := viper.Unmarshal(&cfg)
 	if err != nil {
 		logger.Fatal(fmt.Sprintf("unable to decode into struct: %v", err))
@@ -86,7 +99,10 @@ func main() {
 	logger.Info(fmt.Sprintf("successfully connected to database %s on %s:%d as %s",
 		cfg.DB.Name, cfg.DB.Host, cfg.DB.Port, cfg.DB.User))
 
-	hasher := auth.NewHasher(cfg.Auth.Salt)
+	hasher, err := auth.NewHasher(cfg.Auth.PasswordHash, cfg.Auth.Salt)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("failed to create password hasher: %v", err))
+	}
 
 	adminID, err := uuid.Parse(cfg.AdminMigration.AdminID)
 	if err != nil {
@@ -109,11 +125,6 @@ func main() {
 
 	logger.Info("database migrated successfully")
 
-	jwtManager, err := auth.NewJWTManager(cfg.Auth.SigningKey, cfg.Auth.TimeToLive)
-	if err != nil {
-		logger.Fatal(fmt.Sprintf("failed to create JWTManager: %v", err))
-	}
-
 	//init minio
 	// init minio connection
 	var minioService s3.ObjectStoreService
@@ -133,7 +144,7 @@ func main() {
 	}
 
 	// init mail service
-	//mailService := mail.NewMailService(cfg.SmtpConfig, logger)
+	mailService := mail.NewMailService(cfg.SmtpConfig, logger)
 
 	//init http client
 	cameoMetricsHttpClient, err := helpers.NewHttpClient(common.NewHttpClientConfig(cfg.CameoMetricsHttpClient.URL, cfg.CameoMetricsHttpClient.RateLimiting))
@@ -148,38 +159,185 @@ func main() {
 	companyStorage := dao.NewCompanyStorage(db)
 	vacancyStorage := dao.NewVacancyStorage(db)
 	candidateStorage := dao.NewCandidateStorage(db)
+	signingKeyStorage := dao.NewSigningKeyStorage(db)
+	tokenRevocationStorage := dao.NewTokenRevocationStorage(db)
+	oneTimeTokenStorage := dao.NewOneTimeTokenStorage(db)
+	invitationStorage := dao.NewInvitationStorage(db)
+	backupCodeStorage := dao.NewBackupCodeStorage(db)
+	loginThrottleStorage := dao.NewLoginThrottleStorage(db)
+	serviceAccountStorage := dao.NewServiceAccountStorage(db)
+	oidcStorage := dao.NewOIDCStorage(db)
+	roleStorage := dao.NewRoleStorage(db)
+	auditStorage := dao.NewAuditStorage(db)
+	companyDocumentStorage := dao.NewCompanyDocumentStorage(db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jwtManager, err := auth.NewJWTManager(
+		cfg.Auth.SigningAlgorithm,
+		cfg.Auth.SigningKey,
+		cfg.Auth.Issuer,
+		cfg.Auth.TimeToLive,
+		cfg.Auth.KeyRotationInterval,
+		signingKeyStorage)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("failed to create JWTManager: %v", err))
+	}
+
+	if err := jwtManager.Init(ctx); err != nil {
+		logger.Fatal(fmt.Sprintf("failed to init jwt signing keys: %v", err))
+	}
+
+	go jwtManager.StartRotation(ctx, logger)
 
 	// init service
+	auditService := service.NewAuditService(auditStorage, logger, cfg.Audit)
+
+	go auditService.StartRetention(ctx)
+
+	tokenRevocationService := service.NewTokenRevocationService(tokenRevocationStorage, cfg.Auth.TimeToLive)
+
+	emailVerificationService := service.NewEmailVerificationService(
+		userStorage,
+		oneTimeTokenStorage,
+		mailService,
+		logger,
+		auditService,
+		cfg.Auth.EmailVerification)
+
+	twoFactorService := service.NewTwoFactorService(
+		userStorage,
+		backupCodeStorage,
+		hasher,
+		logger,
+		auditService,
+		cfg.Auth.TwoFactor.Issuer)
+
+	roleService := service.NewRoleService(roleStorage, userStorage, companyStorage, logger, auditService)
+
+	loginThrottleService := service.NewLoginThrottleService(loginThrottleStorage, logger, cfg.Auth.LoginThrottle)
+
 	authService := service.NewAuthService(
 		userStorage,
 		sessionStorage,
 		hasher,
 		jwtManager,
-		logger)
+		tokenRevocationService,
+		emailVerificationService,
+		twoFactorService,
+		loginThrottleService,
+		logger,
+		auditService,
+		cfg.Auth.RefreshTimeToLive,
+		cfg.Auth.SessionLifetime,
+		cfg.Auth.TwoFactor.ChallengeTimeToLive)
+
+	passwordResetService := service.NewPasswordResetService(
+		userStorage,
+		oneTimeTokenStorage,
+		mailService,
+		hasher,
+		authService,
+		logger,
+		auditService,
+		cfg.Auth.PasswordReset)
 
 	userService := service.NewUserService(
 		userStorage,
+		companyStorage,
+		authService,
+		emailVerificationService,
+		roleService,
+		auditService,
+		hasher,
+		fileStorage,
+		minioService,
+		cfg.Avatar,
+		cfg.UserDeletion,
+		logger)
+
+	userExportService := service.NewUserExportService(
+		userStorage,
+		companyStorage,
+		oidcStorage,
+		invitationStorage,
+		loginThrottleStorage,
+		backupCodeStorage,
 		authService,
+		roleService,
+		auditService)
+
+	userImportService := service.NewUserImportService(
+		userStorage,
+		companyStorage,
+		roleService,
 		hasher,
-		uuid.MustParse(cfg.AdminMigration.AdminID))
+		mailService,
+		logger,
+		auditService,
+		cfg.UserImport)
+
+	go userService.StartPurge(ctx)
 
 	candidateService := service.NewCandidateService(
 		logger,
+		auditService,
 		vacancyStorage,
 		candidateStorage,
+		companyStorage,
+		roleService,
 		cameoMetricsHttpClient)
 
-	vacancyService := service.NewVacancyService(logger, vacancyStorage, companyStorage, candidateService)
+	vacancyService := service.NewVacancyService(logger, auditService, vacancyStorage, companyStorage, roleService, candidateService)
+
+	companyService := service.NewCompanyService(logger, auditService, companyStorage, userService, roleService, vacancyService, fileStorage, minioService, cfg.CompanyArchive)
+	go companyService.StartPurge(ctx)
+
+	companyDocumentService := service.NewCompanyDocumentService(
+		companyDocumentStorage,
+		companyStorage,
+		fileStorage,
+		minioService,
+		logger,
+		auditService,
+		cfg.CompanyDocument)
+
+	invitationService := service.NewInvitationService(
+		invitationStorage,
+		companyStorage,
+		userStorage,
+		roleService,
+		hasher,
+		mailService,
+		logger,
+		auditService,
+		cfg.Auth.Invitation,
+		cfg.Auth.MembershipInvitation)
+	membershipService := service.NewMembershipService(companyStorage, userStorage, roleService, logger, auditService)
+	serviceAccountService := service.NewServiceAccountService(serviceAccountStorage, companyStorage, logger, auditService)
+	oidcService := service.NewOIDCService(oidcStorage, userStorage, authService, logger, auditService, cfg.OIDC)
 
-	companyService := service.NewCompanyService(logger, companyStorage, userService, vacancyService, fileStorage, minioService)
 	// init controller
 	controllers := controller.NewControllerContainer(
 		logger,
 		authService,
+		passwordResetService,
+		emailVerificationService,
 		userService,
+		userExportService,
+		userImportService,
 		companyService,
+		companyDocumentService,
 		vacancyService,
 		candidateService,
+		invitationService,
+		membershipService,
+		twoFactorService,
+		serviceAccountService,
+		oidcService,
+		roleService,
+		auditService,
 	)
 
 	newDataProcessing := dataProcessing.NewDataProcessing("created_at", "ASC", 10)
@@ -194,7 +352,11 @@ func main() {
 			controllers,
 			newDataProcessing,
 			jwtManager,
-			uuid.MustParse(cfg.AdminMigration.AdminID))); err != nil {
+			tokenRevocationService,
+			emailVerificationService,
+			serviceAccountService,
+			roleService,
+			companyService)); err != nil {
 			logger.Error(fmt.Sprintf("error accured while running http server: %s", err.Error()))
 		}
 	}()
@@ -207,6 +369,7 @@ func main() {
 	<-quit
 
 	logger.Info("shutting down gracefully...")
+	cancel()
 	defer func() { logger.Info("shutdown complete") }()
 
 	// perform shutdown
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/google/uuid"
+	"time"
+)
+
+type AuditEventObject struct {
+	ID         uuid.UUID                    `json:"id"`
+	CreatedAt  time.Time                    `json:"created_at"`
+	ActorType  enum.AuditActor              `json:"actor_type"`
+	ActorID    *uuid.UUID                   `json:"actor_id"`
+	Action     enum.AuditAction             `json:"action"`
+	TargetType enum.AuditTarget             `json:"target_type"`
+	TargetID   uuid.UUID                    `json:"target_id"`
+	Changes    map[string]AuditChangeObject `json:"changes"`
+	IPAddress  string                       `json:"ip_address"`
+	RequestID  string                       `json:"request_id"`
+}
+
+type AuditChangeObject struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type GetAuditEventsResponse struct {
+	base.ResponseOK
+	Total  int64              `json:"total"`
+	Events []AuditEventObject `json:"events"`
+}
@@ -13,6 +13,8 @@ type CandidateObject struct {
 	Name      string    `json:"name"`
 	Email     string    `json:"email" gorm:"uniqueIndex"`
 	SystemID  string    `json:"system_id"`
+	// PersonalDataHidden is set when name and email are hidden from the viewer
+	PersonalDataHidden bool `json:"personal_data_hidden,omitempty"`
 }
 
 type (
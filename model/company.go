@@ -2,19 +2,65 @@
 
 import (
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
 	"github.com/google/uuid"
 	"time"
 )
 
 type CompanyObject struct {
-	ID          uuid.UUID       `json:"id"`
-	CreatedAt   time.Time       `json:"created_at"`
-	UpdatedAt   time.Time       `json:"updated_at"`
-	Name        string          `json:"name"`
-	Description string          `json:"description"`
-	LogoURL     string          `json:"logoUrl"`
-	Users       []UserObject    `json:"users" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-	Vacancies   []VacancyObject `json:"vacancies" gorm:"constraint:OnUpdate:CASCADE;"`
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	LogoURL     string    `json:"logoUrl"`
+	// LogoRenditions are URLs of the logo sizes and LogoSrcSet lists them for the img srcset attribute.
+	// Logos uploaded before the sizes were introduced have the LogoURL only.
+	LogoRenditions map[enum.LogoRendition]string `json:"logoRenditions"`
+	LogoSrcSet     string                        `json:"logoSrcSet"`
+	Users          []UserObject                  `json:"users" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Vacancies      []VacancyObject               `json:"vacancies" gorm:"constraint:OnUpdate:CASCADE;"`
+}
+
+// CompanyIncludes tells which children are loaded along with companies.
+type CompanyIncludes struct {
+	Users      bool
+	Vacancies  bool
+	Candidates bool
+}
+
+// CompanyStatsObject are hiring numbers of the company. New candidates applied within the last 7 and 30 days,
+// the time since application is averaged over all candidates of the company.
+type CompanyStatsObject struct {
+	OpenVacancies               int64                `json:"open_vacancies"`
+	ClosedVacancies             int64                `json:"closed_vacancies"`
+	Candidates                  int64                `json:"candidates"`
+	NewCandidates7Days          int64                `json:"new_candidates_7_days"`
+	NewCandidates30Days         int64                `json:"new_candidates_30_days"`
+	AverageDaysSinceApplication *float64             `json:"average_days_since_application"`
+	Salary                      SalaryRangeObject    `json:"salary"`
+	Vacancies                   []VacancyStatsObject `json:"vacancies"`
+}
+
+// SalaryRangeObject is the range of salaries of open vacancies with the salary given, empty if there are none.
+type SalaryRangeObject struct {
+	Min     *int `json:"min"`
+	Max     *int `json:"max"`
+	Average *int `json:"average"`
+}
+
+// VacancyStatsObject are candidate numbers of a single vacancy.
+type VacancyStatsObject struct {
+	ID                          uuid.UUID  `json:"id"`
+	Name                        string     `json:"name"`
+	Slug                        string     `json:"slug"`
+	Salary                      int        `json:"salary"`
+	ClosedAt                    *time.Time `json:"closed_at"`
+	Candidates                  int64      `json:"candidates"`
+	NewCandidates7Days          int64      `json:"new_candidates_7_days"`
+	NewCandidates30Days         int64      `json:"new_candidates_30_days"`
+	AverageDaysSinceApplication *float64   `json:"average_days_since_application"`
 }
 
 type (
@@ -23,6 +69,12 @@ type CompanyObject struct {
 		Description string `json:"description"`
 	}
 
+	// UpdateCompanyRequest changes the given fields only.
+	UpdateCompanyRequest struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+	}
+
 	RetrieveCompanyResponse struct {
 		base.ResponseOK
 		Company CompanyObject `json:"company"`
@@ -32,4 +84,102 @@ type CompanyObject struct {
 		base.ResponseOK
 		Companies []CompanyObject `json:"companies"`
 	}
+
+	GetCompanyStatsResponse struct {
+		base.ResponseOK
+		Stats CompanyStatsObject `json:"stats"`
+	}
+)
+
+type InvitationObject struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Email     string    `json:"email"`
+	Role      enum.Role `json:"role"`
+	InvitedBy uuid.UUID `json:"invited_by"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type (
+	CreateInvitationRequest struct {
+		Email string    `json:"email"`
+		Role  enum.Role `json:"role" enums:"recruiter,hiring_manager,viewer"`
+	}
+
+	JoinCompanyRequest struct {
+		Token string `json:"token"`
+	}
+
+	AcceptInvitationRequest struct {
+		Token    string `json:"token"`
+		Name     string `json:"name"`
+		Password string `json:"password"`
+	}
+
+	GetInvitationsResponse struct {
+		base.ResponseOK
+		Invitations []InvitationObject `json:"invitations"`
+	}
+)
+
+// MemberObject is a member of the company with the roles granted within it.
+type MemberObject struct {
+	ID      uuid.UUID   `json:"id"`
+	Name    string      `json:"name"`
+	Email   string      `json:"email"`
+	IsOwner bool        `json:"is_owner"`
+	Roles   []enum.Role `json:"roles"`
+}
+
+type (
+	AddMemberRequest struct {
+		UserID uuid.UUID `json:"user_id"`
+		Role   enum.Role `json:"role" enums:"recruiter,hiring_manager,viewer"`
+	}
+
+	TransferOwnershipRequest struct {
+		UserID uuid.UUID `json:"user_id"`
+		// FormerOwnerRole is the role the former owner keeps in the company, the former owner leaves it if empty.
+		FormerOwnerRole enum.Role `json:"former_owner_role" enums:"recruiter,hiring_manager,viewer"`
+	}
+
+	GetMembersResponse struct {
+		base.ResponseOK
+		Members []MemberObject `json:"members"`
+	}
+)
+
+// CompanyDocumentObject is a document of the company. DownloadURL is given only when a single document is retrieved.
+type CompanyDocumentObject struct {
+	ID          uuid.UUID         `json:"id"`
+	CreatedAt   time.Time         `json:"created_at"`
+	Type        enum.DocumentType `json:"type"`
+	Title       string            `json:"title"`
+	FileName    string            `json:"file_name"`
+	FileType    string            `json:"file_type"`
+	Size        int64             `json:"size"`
+	ValidFrom   *time.Time        `json:"valid_from"`
+	ValidUntil  *time.Time        `json:"valid_until"`
+	UploadedBy  uuid.UUID         `json:"uploaded_by"`
+	DownloadURL string            `json:"download_url,omitempty"`
+}
+
+type (
+	// UploadCompanyDocumentRequest comes as form fields along with the file. Dates are formatted as YYYY-MM-DD.
+	UploadCompanyDocumentRequest struct {
+		Type       enum.DocumentType `form:"type"`
+		Title      string            `form:"title"`
+		ValidFrom  string            `form:"valid_from"`
+		ValidUntil string            `form:"valid_until"`
+	}
+
+	GetCompanyDocumentsResponse struct {
+		base.ResponseOK
+		Documents []CompanyDocumentObject `json:"documents"`
+	}
+
+	RetrieveCompanyDocumentResponse struct {
+		base.ResponseOK
+		Document CompanyDocumentObject `json:"document"`
+	}
 )
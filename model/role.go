@@ -0,0 +1,40 @@
+package model
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/google/uuid"
+	"time"
+)
+
+type RoleObject struct {
+	Name        enum.Role         `json:"name"`
+	Description string            `json:"description"`
+	Permissions []enum.Permission `json:"permissions"`
+}
+
+// RoleGrantObject is a role granted to a user, globally or within the company.
+type RoleGrantObject struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	Role      enum.Role  `json:"role"`
+	CompanyID *uuid.UUID `json:"company_id,omitempty"`
+	GrantedBy *uuid.UUID `json:"granted_by,omitempty"`
+}
+
+type (
+	GrantRoleRequest struct {
+		Role      enum.Role  `json:"role" enums:"platform_admin,company_owner,recruiter,hiring_manager,viewer"`
+		CompanyID *uuid.UUID `json:"company_id"`
+	}
+
+	GetRolesResponse struct {
+		base.ResponseOK
+		Roles []RoleObject `json:"roles"`
+	}
+
+	GetRoleGrantsResponse struct {
+		base.ResponseOK
+		Roles []RoleGrantObject `json:"roles"`
+	}
+)
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/google/uuid"
+	"time"
+)
+
+type ServiceAccountObject struct {
+	ID          uuid.UUID      `json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	CompanyID   *uuid.UUID     `json:"company_id"`
+	CreatedBy   uuid.UUID      `json:"created_by"`
+	APIKeys     []APIKeyObject `json:"api_keys"`
+}
+
+type APIKeyObject struct {
+	ID         uuid.UUID       `json:"id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Name       string          `json:"name"`
+	Prefix     string          `json:"prefix"`
+	Scopes     []enum.APIScope `json:"scopes"`
+	ExpiresAt  *time.Time      `json:"expires_at"`
+	LastUsedAt *time.Time      `json:"last_used_at"`
+	RevokedAt  *time.Time      `json:"revoked_at"`
+}
+
+type (
+	CreateServiceAccountRequest struct {
+		Name        string     `json:"name"`
+		Description string     `json:"description"`
+		CompanyID   *uuid.UUID `json:"company_id"`
+	}
+
+	CreateAPIKeyRequest struct {
+		Name      string          `json:"name"`
+		Scopes    []enum.APIScope `json:"scopes" enums:"users:read,companies:read,companies:write,vacancies:read,vacancies:write,candidates:read,candidates:write"`
+		ExpiresAt *time.Time      `json:"expires_at"`
+	}
+
+	// CreateAPIKeyResponse carries the plain key, it is shown only once.
+	CreateAPIKeyResponse struct {
+		base.ResponseOK
+		Key    string       `json:"key"`
+		APIKey APIKeyObject `json:"api_key"`
+	}
+
+	GetServiceAccountsResponse struct {
+		base.ResponseOK
+		ServiceAccounts []ServiceAccountObject `json:"service_accounts"`
+	}
+)
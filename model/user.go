@@ -2,6 +2,7 @@
 
 import (
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
 	"github.com/google/uuid"
 	"time"
 )
@@ -13,7 +14,54 @@
 		UpdatedAt time.Time `json:"updated_at"`
 		Name      string    `json:"name"`
 		Email     string    `json:"email"`
+		AvatarURL string    `json:"avatar_url"`
 		IsAdmin   bool      `json:"is_admin"`
+		// Roles, EmailVerified, PendingEmail and TwoFactorEnabled are filled only for the authorized user.
+		Roles            []RoleGrantObject `json:"roles,omitempty"`
+		EmailVerified    bool              `json:"email_verified"`
+		PendingEmail     string            `json:"pending_email,omitempty"`
+		TwoFactorEnabled bool              `json:"two_factor_enabled"`
+	}
+
+	// UserImportRowObject is a row of an imported CSV file. Row is the line number in the file.
+	UserImportRowObject struct {
+		Row    int                   `json:"row"`
+		Name   string                `json:"name"`
+		Email  string                `json:"email"`
+		Role   enum.Role             `json:"role,omitempty"`
+		Status enum.UserImportStatus `json:"status"`
+		UserID *uuid.UUID            `json:"user_id,omitempty"`
+		Errors []string              `json:"errors,omitempty"`
+	}
+
+	// DeletedUserObject is a deleted user which can be restored until PurgeAt.
+	DeletedUserObject struct {
+		ID        uuid.UUID `json:"id"`
+		Name      string    `json:"name"`
+		Email     string    `json:"email"`
+		DeletedAt time.Time `json:"deleted_at"`
+		PurgeAt   time.Time `json:"purge_at"`
+	}
+
+	LockoutEventObject struct {
+		ID          uuid.UUID          `json:"id"`
+		CreatedAt   time.Time          `json:"created_at"`
+		Scope       enum.ThrottleScope `json:"scope"`
+		Subject     string             `json:"subject"`
+		IPAddress   string             `json:"ip_address"`
+		Failures    int                `json:"failures"`
+		LockedUntil time.Time          `json:"locked_until"`
+	}
+
+	SessionObject struct {
+		ID         uuid.UUID `json:"id"`
+		DeviceID   string    `json:"device_id"`
+		UserAgent  string    `json:"user_agent"`
+		IPAddress  string    `json:"ip_address"`
+		CreatedAt  time.Time `json:"created_at"`
+		LastUsedAt time.Time `json:"last_used_at"`
+		ExpiresAt  time.Time `json:"expires_at"`
+		Current    bool      `json:"current"`
 	}
 )
 
@@ -48,6 +96,56 @@
 	RecreateJWTRequest struct {
 		RefreshToken uuid.UUID `json:"refresh_token"`
 	}
+
+	TwoFactorLoginRequest struct {
+		ChallengeToken string `json:"challenge_token"`
+		// Code is a TOTP code or a backup code.
+		Code string `json:"code"`
+	}
+
+	// PasswordChangeLoginRequest replaces the generated password of the user on the first login.
+	PasswordChangeLoginRequest struct {
+		ChallengeToken string `json:"challenge_token"`
+		Password       string `json:"password"`
+	}
+
+	TwoFactorCodeRequest struct {
+		Code string `json:"code"`
+	}
+
+	DisableTwoFactorRequest struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
+	}
+
+	ForgotPasswordRequest struct {
+		Email string `json:"email"`
+	}
+
+	VerifyEmailRequest struct {
+		Token string `json:"token"`
+	}
+
+	ResetPasswordRequest struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	GetLockoutEventsResponse struct {
+		base.ResponseOK
+		Total  int64                `json:"total"`
+		Events []LockoutEventObject `json:"events"`
+	}
+
+	GetDeletedUsersResponse struct {
+		base.ResponseOK
+		Users []DeletedUserObject `json:"users"`
+	}
+
+	GetSessionsResponse struct {
+		base.ResponseOK
+		Sessions []SessionObject `json:"sessions"`
+	}
 )
 
 type (
@@ -62,9 +160,96 @@
 		Users []UserObject `json:"users"`
 	}
 
+	// LoginResponse carries either the tokens or a challenge token. The challenge token is exchanged for the tokens
+	// at /user/login/2fa when two-factor authentication is on, or at /user/login/password when the password
+	// is a generated one and has to be changed.
 	LoginResponse struct {
 		base.ResponseOK
-		JWT          string    `json:"token"`
-		RefreshToken uuid.UUID `json:"refresh_token"`
+		JWT                    string     `json:"token,omitempty"`
+		RefreshToken           *uuid.UUID `json:"refresh_token,omitempty"`
+		TwoFactorRequired      bool       `json:"two_factor_required,omitempty"`
+		PasswordChangeRequired bool       `json:"password_change_required,omitempty"`
+		ChallengeToken         string     `json:"challenge_token,omitempty"`
+	}
+
+	// UserImportResponse reports the outcome of every row of an imported CSV file.
+	UserImportResponse struct {
+		base.ResponseOK
+		DryRun  bool                  `json:"dry_run"`
+		Created int                   `json:"created"`
+		Failed  int                   `json:"failed"`
+		Rows    []UserImportRowObject `json:"rows"`
+	}
+
+	TwoFactorEnrollResponse struct {
+		base.ResponseOK
+		Secret          string `json:"secret"`
+		ProvisioningURI string `json:"provisioning_uri"`
+	}
+
+	BackupCodesResponse struct {
+		base.ResponseOK
+		BackupCodes []string `json:"backup_codes"`
+	}
+)
+
+type (
+	OIDCCallbackRequest struct {
+		Code  string `json:"code"`
+		State string `json:"state"`
+	}
+
+	OIDCAuthorizationResponse struct {
+		base.ResponseOK
+		AuthorizationURL string `json:"authorization_url"`
+	}
+
+	GetOIDCProvidersResponse struct {
+		base.ResponseOK
+		Providers []string `json:"providers"`
+	}
+)
+
+// UserExportObject is everything stored about the user, returned by the data export.
+type UserExportObject struct {
+	ExportedAt         time.Time                  `json:"exported_at"`
+	Profile            UserProfileExportObject    `json:"profile"`
+	Roles              []RoleGrantObject          `json:"roles"`
+	Sessions           []SessionObject            `json:"sessions"`
+	ExternalIdentities []ExternalIdentityObject   `json:"external_identities"`
+	OwnedCompanies     []OwnedCompanyExportObject `json:"owned_companies"`
+	SentInvitations    []InvitationObject         `json:"sent_invitations"`
+	LockoutEvents      []LockoutEventObject       `json:"lockout_events"`
+	AuditEvents        []AuditEventObject         `json:"audit_events"`
+}
+
+type (
+	UserProfileExportObject struct {
+		ID                 uuid.UUID  `json:"id"`
+		CreatedAt          time.Time  `json:"created_at"`
+		UpdatedAt          time.Time  `json:"updated_at"`
+		Name               string     `json:"name"`
+		Email              string     `json:"email"`
+		EmailVerifiedAt    *time.Time `json:"email_verified_at"`
+		PendingEmail       string     `json:"pending_email"`
+		TwoFactorEnabledAt *time.Time `json:"two_factor_enabled_at"`
+		UnusedBackupCodes  int64      `json:"unused_backup_codes"`
+		CompanyID          *uuid.UUID `json:"company_id"`
+		AvatarID           *uuid.UUID `json:"avatar_id"`
+	}
+
+	ExternalIdentityObject struct {
+		CreatedAt   time.Time `json:"created_at"`
+		Provider    string    `json:"provider"`
+		Subject     string    `json:"subject"`
+		Email       string    `json:"email"`
+		LastLoginAt time.Time `json:"last_login_at"`
+	}
+
+	OwnedCompanyExportObject struct {
+		ID          uuid.UUID `json:"id"`
+		CreatedAt   time.Time `json:"created_at"`
+		Name        string    `json:"name"`
+		Description string    `json:"description"`
 	}
 )
@@ -11,9 +11,11 @@ type VacancyObject struct {
 	CreatedAt   time.Time         `json:"created_at"`
 	UpdatedAt   time.Time         `json:"updated_at"`
 	Name        string            `json:"name"`
+	Slug        string            `json:"slug"`
 	Salary      int               `json:"salary"`
 	City        string            `json:"city"`
 	Description string            `json:"description"`
+	ClosedAt    *time.Time        `json:"closed_at"`
 	Candidates  []CandidateObject `json:"candidates" `
 }
 
@@ -25,6 +27,14 @@ type VacancyObject struct {
 		Description string `json:"description"`
 	}
 
+	// UpdateVacancyRequest changes the given fields only.
+	UpdateVacancyRequest struct {
+		Name        *string `json:"name"`
+		Salary      *int    `json:"salary"`
+		City        *string `json:"city"`
+		Description *string `json:"description"`
+	}
+
 	RetrieveVacancyResponse struct {
 		base.ResponseOK
 		Vacancy VacancyObject `json:"vacancy"`
@@ -8,9 +8,9 @@
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/common"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.uber.org/zap"
@@ -31,60 +31,320 @@ func (h *Router) InitRoutes(
 	controllerContainer *controller.Container,
 	dataProcessing *dataProcessing.DataProcessing,
 	JWTManager *auth.JWTManager,
-	adminID uuid.UUID,
+	revocationStore auth.RevocationStore,
+	emailVerificationChecker middleware.EmailVerificationChecker,
+	apiKeyAuthenticator auth.APIKeyAuthenticator,
+	permissionChecker middleware.PermissionChecker,
+	companyResolver middleware.CompanyResolver,
 ) *gin.Engine {
 	gin.SetMode(h.config.Server.GinMode)
 
 	router := gin.Default()
 
 	router.Use(middleware.SetRecoveryHandler(*logger))
+	router.Use(middleware.SetRequestID())
 	router.Use(cors.New(common.DefaultCorsConfig()))
 
 	router.GET("api/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/.well-known/jwks.json", controllerContainer.AuthController.JWKS)
 
 	baseRouter := router.Group("/api")
 
 	candidate := baseRouter.Group("/candidate")
 	{
-		candidate.POST("vacancy/:vacancy-id", controllerContainer.CandidateController.CreateCandidate)
-		candidate.GET(":candidate-id", controllerContainer.CandidateController.RetrieveCandidate)
-		candidate.GET("", dataProcessing.ApplyMiddleware(*logger, entity.Candidate{}.FilteringRules(), nil), controllerContainer.CandidateController.GetCandidates)
+		candidate.POST(
+			"vacancy/:vacancy-id",
+			middleware.SetAuthorizationCheckWithAPIKey(JWTManager, revocationStore, apiKeyAuthenticator, *logger, enum.ScopeCandidatesWrite),
+			middleware.SetVacancyPermissionCheck(permissionChecker, companyResolver, *logger, enum.PermissionWriteCandidates),
+			controllerContainer.CandidateController.CreateCandidate)
+		candidate.GET(
+			":candidate-id",
+			middleware.SetOptionalAuthorizationCheck(JWTManager, revocationStore, *logger),
+			controllerContainer.CandidateController.RetrieveCandidate)
+		candidate.GET(
+			"",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			dataProcessing.ApplyMiddleware(*logger, entity.Candidate{}.FilteringRules(), nil),
+			controllerContainer.CandidateController.GetCandidates)
 	}
 
 	vacancy := baseRouter.Group("/vacancy")
 	{
-		vacancy.POST("company/:company-id", controllerContainer.VacancyController.CreateVacancy)
-		vacancy.GET(":vacancy-id", controllerContainer.VacancyController.RetrieveVacancy)
+		vacancy.POST(
+			"company/:company-id",
+			middleware.SetAuthorizationCheckWithAPIKey(JWTManager, revocationStore, apiKeyAuthenticator, *logger, enum.ScopeVacanciesWrite),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionWriteVacancies),
+			controllerContainer.VacancyController.CreateVacancy)
+		vacancy.GET(
+			":vacancy-id",
+			middleware.SetOptionalAuthorizationCheck(JWTManager, revocationStore, *logger),
+			controllerContainer.VacancyController.RetrieveVacancy)
+		vacancy.PATCH(
+			":vacancy-id",
+			middleware.SetAuthorizationCheckWithAPIKey(JWTManager, revocationStore, apiKeyAuthenticator, *logger, enum.ScopeVacanciesWrite),
+			middleware.SetVacancyPermissionCheck(permissionChecker, companyResolver, *logger, enum.PermissionWriteVacancies),
+			controllerContainer.VacancyController.UpdateVacancy)
 		vacancy.GET("", dataProcessing.ApplyMiddleware(*logger, entity.Vacancy{}.FilteringRules(), nil), controllerContainer.VacancyController.GetVacancy)
 	}
 
 	company := baseRouter.Group("/company")
 	{
-		company.POST("", middleware.SetAuthorizationCheck(JWTManager, *logger), controllerContainer.CompanyController.CreateCompany)
-		company.POST(":company-id/logo", middleware.SetAuthorizationCheck(JWTManager, *logger), controllerContainer.CompanyController.UploadLogo)
-		company.GET(":company-id", controllerContainer.CompanyController.RetrieveCompany)
-		company.GET("", dataProcessing.ApplyMiddleware(*logger, entity.Company{}.FilteringRules(), nil), controllerContainer.CompanyController.GetCompany)
+		company.POST(
+			"",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			controllerContainer.CompanyController.CreateCompany)
+		company.POST(
+			":company-id/logo",
+			middleware.SetAuthorizationCheckWithAPIKey(JWTManager, revocationStore, apiKeyAuthenticator, *logger, enum.ScopeCompaniesWrite),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageCompany),
+			controllerContainer.CompanyController.UploadLogo)
+		company.POST(
+			":company-id/invitations",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageMembers),
+			controllerContainer.InvitationController.CreateInvitation)
+		company.GET(
+			":company-id/invitations",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageMembers),
+			controllerContainer.InvitationController.GetInvitations)
+		company.DELETE(
+			":company-id/invitations/:invitation-id",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageMembers),
+			controllerContainer.InvitationController.RevokeInvitation)
+		company.GET(
+			":company-id/stats",
+			middleware.SetAuthorizationCheckWithAPIKey(JWTManager, revocationStore, apiKeyAuthenticator, *logger, enum.ScopeCompaniesRead),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionReadCandidates),
+			controllerContainer.CompanyController.GetStats)
+		company.POST(
+			":company-id/documents",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionWriteDocuments),
+			controllerContainer.CompanyDocumentController.UploadDocument)
+		company.GET(
+			":company-id/documents",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionReadDocuments),
+			controllerContainer.CompanyDocumentController.GetDocuments)
+		company.GET(
+			":company-id/documents/:document-id",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionReadDocuments),
+			controllerContainer.CompanyDocumentController.RetrieveDocument)
+		company.DELETE(
+			":company-id/documents/:document-id",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionWriteDocuments),
+			controllerContainer.CompanyDocumentController.DeleteDocument)
+		company.GET(
+			":company-id/members",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			controllerContainer.MembershipController.GetMembers)
+		company.POST(
+			":company-id/members",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageMembers),
+			controllerContainer.MembershipController.AddMember)
+		company.DELETE(
+			":company-id/members/:user-id",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageMembers),
+			controllerContainer.MembershipController.RemoveMember)
+		company.POST(
+			":company-id/leave",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			controllerContainer.MembershipController.Leave)
+		company.POST(
+			":company-id/owner",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageCompany),
+			controllerContainer.MembershipController.TransferOwnership)
+		company.GET(
+			"by-slug/:company-slug",
+			middleware.SetOptionalAuthorizationCheck(JWTManager, revocationStore, *logger),
+			controllerContainer.CompanyController.RetrieveCompanyBySlug)
+		company.GET(
+			"by-slug/:company-slug/vacancy/:vacancy-slug",
+			middleware.SetOptionalAuthorizationCheck(JWTManager, revocationStore, *logger),
+			controllerContainer.VacancyController.RetrieveVacancyBySlug)
+		company.GET(
+			":company-id",
+			middleware.SetOptionalAuthorizationCheck(JWTManager, revocationStore, *logger),
+			controllerContainer.CompanyController.RetrieveCompany)
+		company.PATCH(
+			":company-id",
+			middleware.SetAuthorizationCheckWithAPIKey(JWTManager, revocationStore, apiKeyAuthenticator, *logger, enum.ScopeCompaniesWrite),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageCompany),
+			controllerContainer.CompanyController.UpdateCompany)
+		company.DELETE(
+			":company-id",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageCompany),
+			controllerContainer.CompanyController.ArchiveCompany)
+		company.GET("",
+			middleware.SetOptionalAuthorizationCheck(JWTManager, revocationStore, *logger),
+			dataProcessing.ApplyMiddleware(*logger, entity.Company{}.FilteringRules(), nil),
+			controllerContainer.CompanyController.GetCompany)
+	}
+
+	invitation := baseRouter.Group("/invitation")
+	{
+		invitation.POST("accept", controllerContainer.InvitationController.AcceptInvitation)
+		invitation.POST("join",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetEmailVerifiedCheck(emailVerificationChecker, *logger),
+			controllerContainer.InvitationController.JoinCompany)
 	}
 
 	user := baseRouter.Group("user")
 	{
 		user.POST("register",
-			middleware.SetAuthorizationAdminCheck(JWTManager, adminID, *logger),
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageUsers),
 			controllerContainer.AuthController.Register)
 		user.POST("login", controllerContainer.AuthController.Login)
+		user.POST("login/2fa", controllerContainer.AuthController.LoginTwoFactor)
+		user.POST("login/password", controllerContainer.AuthController.LoginPasswordChange)
+		user.GET("login/oidc", controllerContainer.OIDCController.GetProviders)
+		user.GET("login/oidc/:provider", controllerContainer.OIDCController.Begin)
+		user.POST("login/oidc/:provider/callback", controllerContainer.OIDCController.Callback)
 		user.POST("refresh", controllerContainer.AuthController.RecreateJWT)
+		user.POST("password/forgot", controllerContainer.AuthController.ForgotPassword)
+		user.POST("password/reset", controllerContainer.AuthController.ResetPassword)
+		user.POST("email/verify", controllerContainer.AuthController.VerifyEmail)
+		user.POST("email/verify/resend", middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger), controllerContainer.AuthController.ResendEmailVerification)
 		user.POST(
 			"logout",
-			middleware.SetAuthorizationCheck(JWTManager, *logger),
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
 			controllerContainer.AuthController.Logout)
-		user.DELETE(":user-id/delete", middleware.SetAuthorizationAdminCheck(JWTManager, adminID, *logger), controllerContainer.UserController.DeleteUser)
-		user.POST("field/update", middleware.SetAuthorizationCheck(JWTManager, *logger), controllerContainer.UserController.Update)
+		user.DELETE(
+			":user-id/delete",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageUsers),
+			controllerContainer.UserController.DeleteUser)
+		user.GET(
+			":user-id/roles",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageRoles),
+			controllerContainer.RoleController.GetUserRoles)
+		user.POST(
+			":user-id/roles",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageRoles),
+			controllerContainer.RoleController.GrantRole)
+		user.DELETE(
+			":user-id/roles/:role-grant-id",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageRoles),
+			controllerContainer.RoleController.RevokeRole)
+		user.POST("field/update", middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger), controllerContainer.UserController.Update)
 		user.GET(
 			"get",
-			middleware.SetAuthorizationCheck(JWTManager, *logger),
+			middleware.SetAuthorizationCheckWithAPIKey(JWTManager, revocationStore, apiKeyAuthenticator, *logger, enum.ScopeUsersRead),
 			dataProcessing.ApplyMiddleware(*logger, entity.User{}.FilteringRules(), nil),
 			controllerContainer.UserController.Get)
-		user.GET("retrieve", middleware.SetAuthorizationCheck(JWTManager, *logger), controllerContainer.UserController.RetrieveUser)
+		user.POST(
+			"lookup",
+			middleware.SetAuthorizationCheckWithAPIKey(JWTManager, revocationStore, apiKeyAuthenticator, *logger, enum.ScopeUsersRead),
+			controllerContainer.UserController.GetUserByIdList)
+		user.POST(
+			"import",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageUsers),
+			controllerContainer.UserController.ImportUsers)
+		user.GET("sessions", middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger), controllerContainer.AuthController.GetSessions)
+		user.DELETE("sessions", middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger), controllerContainer.AuthController.RevokeOtherSessions)
+		user.DELETE("sessions/:session-id", middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger), controllerContainer.AuthController.RevokeSession)
+		user.POST("2fa/enroll", middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger), controllerContainer.TwoFactorController.Enroll)
+		user.POST("2fa/confirm", middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger), controllerContainer.TwoFactorController.Confirm)
+		user.POST("2fa/backup-codes", middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger), controllerContainer.TwoFactorController.RegenerateBackupCodes)
+		user.POST("2fa/disable", middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger), controllerContainer.TwoFactorController.Disable)
+		user.GET(
+			"lockouts",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionReadLockouts),
+			dataProcessing.ApplyMiddleware(*logger, entity.LockoutEvent{}.FilteringRules(), nil),
+			controllerContainer.AuthController.GetLockoutEvents)
+		user.GET("retrieve", middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger), controllerContainer.UserController.RetrieveUser)
+		user.POST("avatar", middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger), controllerContainer.UserController.UploadAvatar)
+		user.DELETE("avatar", middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger), controllerContainer.UserController.DeleteAvatar)
+		user.GET(":user-id/avatar/default", controllerContainer.UserController.GetDefaultAvatar)
+		user.GET("export", middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger), controllerContainer.UserController.ExportData)
+		user.GET(
+			"deleted",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageUsers),
+			controllerContainer.UserController.GetDeletedUsers)
+		user.POST(
+			":user-id/restore",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageUsers),
+			controllerContainer.UserController.RestoreUser)
+	}
+
+	role := baseRouter.Group("/role")
+	{
+		role.GET(
+			"",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageRoles),
+			controllerContainer.RoleController.GetRoles)
+	}
+
+	audit := baseRouter.Group("/audit")
+	{
+		audit.GET(
+			"",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionReadAuditLog),
+			dataProcessing.ApplyMiddleware(*logger, entity.AuditEvent{}.FilteringRules(), nil),
+			controllerContainer.AuditController.GetEvents)
+	}
+
+	serviceAccount := baseRouter.Group("/service-account")
+	{
+		serviceAccount.POST(
+			"",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageServiceAccounts),
+			controllerContainer.ServiceAccountController.CreateServiceAccount)
+		serviceAccount.GET(
+			"",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageServiceAccounts),
+			controllerContainer.ServiceAccountController.GetServiceAccounts)
+		serviceAccount.DELETE(
+			":service-account-id",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageServiceAccounts),
+			controllerContainer.ServiceAccountController.DeleteServiceAccount)
+		serviceAccount.POST(
+			":service-account-id/keys",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageServiceAccounts),
+			controllerContainer.ServiceAccountController.CreateAPIKey)
+		serviceAccount.DELETE(
+			":service-account-id/keys/:key-id",
+			middleware.SetAuthorizationCheck(JWTManager, revocationStore, *logger),
+			middleware.SetPermissionCheck(permissionChecker, *logger, enum.PermissionManageServiceAccounts),
+			controllerContainer.ServiceAccountController.RevokeAPIKey)
 	}
 
 	return router
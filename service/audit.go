@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/middleware"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/common"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	auditRetentionInterval = time.Hour
+	auditRedactedValue     = "[redacted]"
+)
+
+// auditSensitiveFields are parts of field names whose values are never written to the audit log.
+var auditSensitiveFields = []string{"password", "secret", "hash", "token"}
+
+// AuditService keeps the audit log of changes made through services. The actor, client IP
+// and request ID are taken from the request context set up by the auth and request ID middleware.
+type AuditService struct {
+	storage *dao.AuditStorage
+	logger  *zap.Logger
+	config  common.AuditConfig
+}
+
+func NewAuditService(storage *dao.AuditStorage, logger *zap.Logger, config common.AuditConfig) *AuditService {
+	return &AuditService{
+		storage: storage,
+		logger:  logger,
+		config:  config,
+	}
+}
+
+// Record writes the change of the target to the audit log. Before is nil for created targets and
+// after is nil for deleted ones. A failure is logged but doesn't fail the change, it is made already.
+func (s *AuditService) Record(action enum.AuditAction, targetType enum.AuditTarget, targetID uuid.UUID, before any, after any, ctx context.Context) {
+	changes, err := auditChanges(before, after)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to compute audit changes of %s %s: %v", targetType, targetID, err))
+	}
+
+	event := &entity.AuditEvent{
+		ActorType:  enum.AuditActorAnonymous,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Changes:    changes,
+	}
+
+	if id, ok := ctx.Value(middleware.ServiceAccountIDKey).(uuid.UUID); ok {
+		event.ActorType = enum.AuditActorServiceAccount
+		event.ActorID = &id
+	} else if id, ok := ctx.Value(middleware.UserIDKey).(uuid.UUID); ok {
+		event.ActorType = enum.AuditActorUser
+		event.ActorID = &id
+	}
+
+	event.IPAddress, _ = ctx.Value(middleware.ClientIPKey).(string)
+	event.RequestID, _ = ctx.Value(middleware.RequestIDKey).(string)
+
+	// the change is made even if the client has gone meanwhile, so it must be recorded too
+	if err := s.storage.Create(event, context.WithoutCancel(ctx)); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to record %s of %s %s: %v", action, targetType, targetID, err))
+	}
+}
+
+func (s *AuditService) GetEvents(options *dataProcessing.Options, ctx context.Context) ([]model.AuditEventObject, int64, *base.ServiceError) {
+	events, total, err := s.storage.Get(options, ctx)
+	if err != nil {
+		return nil, total, base.NewPostgresReadError(err)
+	}
+
+	result := make([]model.AuditEventObject, 0, len(events))
+	for _, event := range events {
+		result = append(result, auditEventObject(&event))
+	}
+
+	return result, total, nil
+}
+
+// GetUserEvents returns events made by the user or to the user for the user's data export.
+// Data of other people is redacted: who made an event to the user and from where is not given,
+// and changes are given only for the user's own record, since changes of other targets may hold
+// personal data of third parties, e.g. emails of invitees.
+func (s *AuditService) GetUserEvents(userID uuid.UUID, ctx context.Context) ([]model.AuditEventObject, *base.ServiceError) {
+	events, err := s.storage.GetByUser(userID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	result := make([]model.AuditEventObject, 0, len(events))
+	for _, event := range events {
+		object := auditEventObject(&event)
+
+		if event.ActorID == nil || *event.ActorID != userID {
+			object.ActorID = nil
+			object.IPAddress = ""
+			object.RequestID = ""
+		}
+
+		if event.TargetType != enum.AuditTargetUser || event.TargetID != userID {
+			object.Changes = map[string]model.AuditChangeObject{}
+		}
+
+		result = append(result, object)
+	}
+
+	return result, nil
+}
+
+// StartRetention periodically removes events older than the retention period.
+// Events are kept forever if the period is not set. It blocks until ctx is done.
+func (s *AuditService) StartRetention(ctx context.Context) {
+	if s.config.Retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(auditRetentionInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.storage.DeleteOlderThan(time.Now().Add(-s.config.Retention), ctx)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("failed to remove expired audit events: %v", err))
+		} else if deleted > 0 {
+			s.logger.Info(fmt.Sprintf("removed %d expired audit events", deleted))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func auditEventObject(event *entity.AuditEvent) model.AuditEventObject {
+	changes := make(map[string]model.AuditChangeObject, len(event.Changes))
+	for name, change := range event.Changes {
+		changes[name] = model.AuditChangeObject{Before: change.Before, After: change.After}
+	}
+
+	return model.AuditEventObject{
+		ID:         event.ID,
+		CreatedAt:  event.CreatedAt,
+		ActorType:  event.ActorType,
+		ActorID:    event.ActorID,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		Changes:    changes,
+		IPAddress:  event.IPAddress,
+		RequestID:  event.RequestID,
+	}
+}
+
+// auditChanges returns fields which differ between JSON representations of before and after.
+func auditChanges(before any, after any) (map[string]entity.AuditChange, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]entity.AuditChange{}
+	for name, value := range beforeFields {
+		if afterValue, ok := afterFields[name]; !ok || !reflect.DeepEqual(value, afterValue) {
+			changes[name] = entity.AuditChange{Before: value, After: afterValue}
+		}
+	}
+
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = entity.AuditChange{After: value}
+		}
+	}
+
+	for name, change := range changes {
+		if isAuditSensitiveField(name) {
+			changes[name] = entity.AuditChange{Before: redactAuditValue(change.Before), After: redactAuditValue(change.After)}
+		}
+	}
+
+	return changes, nil
+}
+
+func auditFields(value any) (map[string]any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+func isAuditSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range auditSensitiveFields {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactAuditValue hides the value but keeps the fact it was set or unset.
+func redactAuditValue(value any) any {
+	if value == nil || value == "" {
+		return value
+	}
+
+	return auditRedactedValue
+}
+
+// userAuditState is the part of the user written to the audit log. The password hash is redacted,
+// so only the fact of its change is recorded.
+type userAuditState struct {
+	Name             string     `json:"name"`
+	Email            string     `json:"email"`
+	PendingEmail     string     `json:"pending_email"`
+	EmailVerifiedAt  *time.Time `json:"email_verified_at"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	CompanyID        *uuid.UUID `json:"company_id"`
+	Password         string     `json:"password"`
+}
+
+func newUserAuditState(user *entity.User) *userAuditState {
+	return &userAuditState{
+		Name:             user.Name,
+		Email:            user.Email,
+		PendingEmail:     user.PendingEmail,
+		EmailVerifiedAt:  user.EmailVerifiedAt,
+		TwoFactorEnabled: user.IsTwoFactorEnabled(),
+		CompanyID:        user.CompanyID,
+		Password:         user.Password,
+	}
+}
+
+type companyAuditState struct {
+	Name        string     `json:"name"`
+	Slug        string     `json:"slug"`
+	Description string     `json:"description"`
+	Owner       uuid.UUID  `json:"owner"`
+	LogoID      *uuid.UUID `json:"logo_id"`
+}
+
+func newCompanyAuditState(company *entity.Company) *companyAuditState {
+	return &companyAuditState{
+		Name:        company.Name,
+		Slug:        company.Slug,
+		Description: company.Description,
+		Owner:       company.Owner,
+		LogoID:      company.FileID,
+	}
+}
+
+type documentAuditState struct {
+	CompanyID  uuid.UUID         `json:"company_id"`
+	Type       enum.DocumentType `json:"type"`
+	Title      string            `json:"title"`
+	FileName   string            `json:"file_name"`
+	FileID     uuid.UUID         `json:"file_id"`
+	ValidFrom  *time.Time        `json:"valid_from"`
+	ValidUntil *time.Time        `json:"valid_until"`
+}
+
+func newDocumentAuditState(document *entity.CompanyDocument) *documentAuditState {
+	return &documentAuditState{
+		CompanyID:  document.CompanyID,
+		Type:       document.Type,
+		Title:      document.Title,
+		FileName:   document.FileName,
+		FileID:     document.FileID,
+		ValidFrom:  document.ValidFrom,
+		ValidUntil: document.ValidUntil,
+	}
+}
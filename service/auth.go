@@ -3,23 +3,43 @@
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/helpers"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"net/http"
+	"time"
+)
+
+const (
+	// challengeTwoFactor is the purpose of challenge tokens issued when a TOTP code is required.
+	challengeTwoFactor = "2fa"
+	// challengePasswordChange is the purpose of challenge tokens issued when a generated password has to be changed.
+	challengePasswordChange = "password-change"
 )
 
 type AuthService struct {
-	storage        *dao.UserStorage
-	storageSession *dao.SessionStorage
-	hasher         *auth.Hasher
-	jwtManager     *auth.JWTManager
-	logger         *zap.Logger
+	storage                  *dao.UserStorage
+	storageSession           *dao.SessionStorage
+	hasher                   *auth.Hasher
+	jwtManager               *auth.JWTManager
+	revocationService        *TokenRevocationService
+	emailVerificationService *EmailVerificationService
+	twoFactorService         *TwoFactorService
+	loginThrottleService     *LoginThrottleService
+	logger                   *zap.Logger
+	auditService             *AuditService
+	refreshTimeToLive        time.Duration
+	sessionLifetime          time.Duration
+	challengeTimeToLive      time.Duration
 }
 
 func NewAuthService(
@@ -27,13 +47,29 @@ func NewAuthService(
 	storageSession *dao.SessionStorage,
 	hasher *auth.Hasher,
 	jwtManager *auth.JWTManager,
-	logger *zap.Logger) *AuthService {
+	revocationService *TokenRevocationService,
+	emailVerificationService *EmailVerificationService,
+	twoFactorService *TwoFactorService,
+	loginThrottleService *LoginThrottleService,
+	logger *zap.Logger,
+	auditService *AuditService,
+	refreshTimeToLive time.Duration,
+	sessionLifetime time.Duration,
+	challengeTimeToLive time.Duration) *AuthService {
 	return &AuthService{
-		storage:        storage,
-		storageSession: storageSession,
-		hasher:         hasher,
-		jwtManager:     jwtManager,
-		logger:         logger,
+		storage:                  storage,
+		storageSession:           storageSession,
+		hasher:                   hasher,
+		jwtManager:               jwtManager,
+		revocationService:        revocationService,
+		emailVerificationService: emailVerificationService,
+		twoFactorService:         twoFactorService,
+		loginThrottleService:     loginThrottleService,
+		logger:                   logger,
+		auditService:             auditService,
+		refreshTimeToLive:        refreshTimeToLive,
+		sessionLifetime:          sessionLifetime,
+		challengeTimeToLive:      challengeTimeToLive,
 	}
 }
 
@@ -53,63 +89,291 @@ func (s *AuthService) Register(request *model.RegisterRequest, ctx context.Conte
 		return nil, base.NewPostgresWriteError(err)
 	}
 
+	s.auditService.Record(enum.AuditUserRegister, enum.AuditTargetUser, user.ID, nil, newUserAuditState(user), ctx)
+
+	// the account is created anyway, the user can request the link again after login
+	if serviceErr := s.emailVerificationService.SendVerification(user, user.Email, ctx); serviceErr != nil {
+		s.logger.Error(user.Email + ": failed to send email verification: " + serviceErr.Error())
+	}
+
 	return &user.ID, nil
 }
 
-func (s *AuthService) Login(request *model.LoginRequest, deviceId string, ctx context.Context) (jwt *string, refToken *uuid.UUID, mainErr *base.ServiceError) {
+// Login checks the password of the user. Without two-factor authentication a session is opened right away,
+// otherwise a challenge token is returned and the session is opened by LoginTwoFactor.
+func (s *AuthService) Login(request *model.LoginRequest, client helpers.ClientInfo, ctx context.Context) (*model.LoginResponse, *base.ServiceError) {
+	if serviceErr := s.loginThrottleService.Check(request.Email, client.IPAddress, ctx); serviceErr != nil {
+		s.logger.Info(request.Email + ": login attempt throttled: " + serviceErr.Message)
+		return nil, serviceErr
+	}
+
 	user, err := s.storage.GetUser(request.Email, ctx)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			s.logger.Info(request.Email + ": user not found")
-			return nil, nil, base.NewLoginError(err)
+			return nil, s.loginFailed(request.Email, client, base.NewLoginError(err), ctx)
 		}
-		return nil, nil, base.NewPostgresReadError(err)
+		return nil, base.NewPostgresReadError(err)
 	}
-	hashPassword, err := s.hasher.Hash(request.Password)
+	ok, needsRehash, err := s.hasher.Verify(request.Password, user.Password)
 	if err != nil {
-		return nil, nil, base.NewReadByteError(err)
+		s.logger.Error(request.Email + ": failed verify password hash: " + err.Error())
+		return nil, s.loginFailed(request.Email, client, base.NewLoginError(err), ctx)
 	}
 
-	if hashPassword != user.Password {
+	if !ok {
 		s.logger.Info(request.Email + ": user invalid password")
-		return nil, nil, base.NewLoginError(err)
+		return nil, s.loginFailed(request.Email, client, base.NewLoginError(errors.New("invalid password")), ctx)
+	}
+
+	if needsRehash {
+		if serviceErr := s.rehashPassword(user, request.Password, ctx); serviceErr != nil {
+			return nil, serviceErr
+		}
+	}
+
+	if user.PasswordChangeRequired {
+		return s.passwordChangeChallenge(user)
+	}
+
+	if user.IsTwoFactorEnabled() {
+		return s.twoFactorChallenge(user)
+	}
+
+	if serviceErr := s.loginThrottleService.RegisterSuccess(user.Email, ctx); serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	return s.openSession(user, client, ctx)
+}
+
+func (s *AuthService) passwordChangeChallenge(user *entity.User) (*model.LoginResponse, *base.ServiceError) {
+	challengeToken, err := s.jwtManager.NewChallengeJWT(user.ID, challengePasswordChange, s.challengeTimeToLive)
+	if err != nil {
+		return nil, base.NewCreateJWTError(err)
+	}
+
+	s.logger.Info(user.Email + ": password change challenge issued")
+
+	return &model.LoginResponse{
+		PasswordChangeRequired: true,
+		ChallengeToken:         challengeToken,
+	}, nil
+}
+
+// LoginPasswordChange replaces the generated password of the user and continues the login.
+// Two-factor authentication is still required if the user has enabled it.
+func (s *AuthService) LoginPasswordChange(request *model.PasswordChangeLoginRequest, client helpers.ClientInfo, ctx context.Context) (*model.LoginResponse, *base.ServiceError) {
+	userID, err := s.jwtManager.ParseChallenge(request.ChallengeToken, challengePasswordChange)
+	if err != nil {
+		return nil, base.NewUnauthorizedError(err)
+	}
+
+	user, err := s.storage.Retrieve(userID, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, base.NewUnauthorizedError(err)
+		}
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	if !user.PasswordChangeRequired {
+		return nil, base.NewUnauthorizedError(errors.New("password has already been changed"))
+	}
+
+	sameAsGenerated, _, err := s.hasher.Verify(request.Password, user.Password)
+	if err != nil {
+		return nil, base.NewUnauthorizedError(err)
+	}
+
+	if request.Password == "" || sameAsGenerated {
+		return nil, &base.ServiceError{
+			Err:     errors.New("the new password is empty or the generated one"),
+			Blame:   base.BlameUser,
+			Code:    http.StatusBadRequest,
+			Message: "the new password must differ from the generated one",
+		}
+	}
+
+	hashPassword, err := s.hasher.Hash(request.Password)
+	if err != nil {
+		return nil, base.NewReadByteError(err)
+	}
+
+	now := time.Now()
+	if err := s.storage.ChangePassword(user.ID, hashPassword, now, ctx); err != nil {
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	before := newUserAuditState(user)
+	user.Password = hashPassword
+	user.PasswordChangeRequired = false
+	if user.EmailVerifiedAt == nil {
+		user.EmailVerifiedAt = &now
+	}
+	s.auditService.Record(enum.AuditUserPasswordChange, enum.AuditTargetUser, user.ID, before, newUserAuditState(user), ctx)
+
+	s.logger.Info(user.Email + ": generated password changed")
+
+	if user.IsTwoFactorEnabled() {
+		return s.twoFactorChallenge(user)
+	}
+
+	if serviceErr := s.loginThrottleService.RegisterSuccess(user.Email, ctx); serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	return s.openSession(user, client, ctx)
+}
+
+// LoginExternal finishes login of a user authenticated by an external identity provider.
+// Two-factor authentication is still required if the user has enabled it.
+func (s *AuthService) LoginExternal(user *entity.User, client helpers.ClientInfo, ctx context.Context) (*model.LoginResponse, *base.ServiceError) {
+	if user.IsTwoFactorEnabled() {
+		return s.twoFactorChallenge(user)
+	}
+
+	return s.openSession(user, client, ctx)
+}
+
+func (s *AuthService) twoFactorChallenge(user *entity.User) (*model.LoginResponse, *base.ServiceError) {
+	challengeToken, err := s.jwtManager.NewChallengeJWT(user.ID, challengeTwoFactor, s.challengeTimeToLive)
+	if err != nil {
+		return nil, base.NewCreateJWTError(err)
+	}
+
+	s.logger.Info(user.Email + ": two-factor challenge issued")
+
+	return &model.LoginResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    challengeToken,
+	}, nil
+}
+
+// LoginTwoFactor completes the login of a user with two-factor authentication.
+func (s *AuthService) LoginTwoFactor(request *model.TwoFactorLoginRequest, client helpers.ClientInfo, ctx context.Context) (*model.LoginResponse, *base.ServiceError) {
+	userID, err := s.jwtManager.ParseChallenge(request.ChallengeToken, challengeTwoFactor)
+	if err != nil {
+		return nil, base.NewUnauthorizedError(err)
+	}
+
+	user, err := s.storage.Retrieve(userID, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, base.NewUnauthorizedError(err)
+		}
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	if !user.IsTwoFactorEnabled() {
+		return nil, base.NewUnauthorizedError(errors.New("two-factor authentication has been disabled"))
+	}
+
+	if serviceErr := s.loginThrottleService.Check(user.Email, client.IPAddress, ctx); serviceErr != nil {
+		s.logger.Info(user.Email + ": two-factor attempt throttled: " + serviceErr.Message)
+		return nil, serviceErr
+	}
+
+	if serviceErr := s.twoFactorService.VerifyCode(user, request.Code, ctx); serviceErr != nil {
+		s.logger.Info(user.Email + ": invalid two-factor code")
+		if serviceErr.Code != http.StatusUnauthorized {
+			return nil, serviceErr
+		}
+		return nil, s.loginFailed(user.Email, client, serviceErr, ctx)
+	}
+
+	if serviceErr := s.loginThrottleService.RegisterSuccess(user.Email, ctx); serviceErr != nil {
+		return nil, serviceErr
 	}
 
+	return s.openSession(user, client, ctx)
+}
+
+// loginFailed counts the failed attempt against the account and the client IP and returns loginErr.
+func (s *AuthService) loginFailed(email string, client helpers.ClientInfo, loginErr *base.ServiceError, ctx context.Context) *base.ServiceError {
+	if serviceErr := s.loginThrottleService.RegisterFailure(email, client.IPAddress, ctx); serviceErr != nil {
+		return serviceErr
+	}
+
+	return loginErr
+}
+
+// openSession creates a new session family of the user and issues the first pair of tokens.
+func (s *AuthService) openSession(user *entity.User, client helpers.ClientInfo, ctx context.Context) (*model.LoginResponse, *base.ServiceError) {
+	now := time.Now()
+
+	if err := s.storageSession.DeleteExpiredByUserID(user.ID, now, ctx); err != nil {
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	absoluteExpiresAt := now.Add(s.sessionLifetime)
 	session := &entity.Session{
-		UserID:   user.ID,
-		DeviceID: deviceId,
+		UserID:            user.ID,
+		DeviceID:          client.DeviceID,
+		UserAgent:         client.UserAgent,
+		IPAddress:         client.IPAddress,
+		StartedAt:         now,
+		LastUsedAt:        now,
+		FamilyID:          uuid.New(),
+		ExpiresAt:         s.slidingExpiry(now, absoluteExpiresAt),
+		AbsoluteExpiresAt: absoluteExpiresAt,
 	}
 
 	if err := s.storageSession.Create(session, ctx); err != nil {
-		return nil, nil, base.NewPostgresWriteError(err)
+		return nil, base.NewPostgresWriteError(err)
 	}
 
 	s.logger.Info(user.Email + ": session create")
 
-	token, err := s.jwtManager.NewJWT(user.ID)
+	token, err := s.jwtManager.NewJWT(user.ID, session.FamilyID)
 	if err != nil {
-		return nil, nil, base.NewCreateJWTError(err)
+		return nil, base.NewCreateJWTError(err)
 	}
 
-	return &token, &session.ID, nil
+	return &model.LoginResponse{
+		JWT:          token,
+		RefreshToken: &session.ID,
+	}, nil
 }
 
-func (s *AuthService) SignOutAllSession(id uuid.UUID, ctx context.Context) (mainErr *base.ServiceError) {
-	sessions, err := s.storageSession.GetByUserID(id, ctx)
+// rehashPassword replaces outdated password hash of the user with one made by the current scheme.
+func (s *AuthService) rehashPassword(user *entity.User, password string, ctx context.Context) *base.ServiceError {
+	hashPassword, err := s.hasher.Hash(password)
 	if err != nil {
-		return base.NewPostgresReadError(err)
+		return base.NewReadByteError(err)
 	}
 
-	for _, session := range sessions {
-		if serviceErr := s.storageSession.Delete(session.ID, ctx); serviceErr != nil {
-			return base.NewPostgresReadError(err)
-		}
+	if err := s.storage.UpdatePassword(user.ID, hashPassword, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
 	}
 
+	user.Password = hashPassword
+	s.logger.Info(user.Email + ": password hash upgraded")
+
 	return nil
 }
 
-func (s *AuthService) Logout(refreshToken uuid.UUID, ctx context.Context) (mainErr *base.ServiceError) {
+// slidingExpiry returns expiry of a session refreshed at now, capped by the absolute expiry of its family.
+func (s *AuthService) slidingExpiry(now time.Time, absoluteExpiresAt time.Time) time.Time {
+	expiresAt := now.Add(s.refreshTimeToLive)
+	if expiresAt.After(absoluteExpiresAt) {
+		return absoluteExpiresAt
+	}
+
+	return expiresAt
+}
+
+// SignOutAllSession deletes every refresh session of the user and revokes all access tokens issued so far.
+func (s *AuthService) SignOutAllSession(id uuid.UUID, ctx context.Context) (mainErr *base.ServiceError) {
+	if err := s.storageSession.DeleteByUserID(id, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	return s.revocationService.RevokeUserTokens(id, ctx)
+}
+
+// Logout ends the refresh session and revokes the access token the request was made with.
+func (s *AuthService) Logout(refreshToken uuid.UUID, accessClaims *auth.Claims, ctx context.Context) (mainErr *base.ServiceError) {
 	session, err := s.storageSession.Retrieve(refreshToken, ctx)
 	if err != nil {
 		return &base.ServiceError{
@@ -119,14 +383,23 @@ func (s *AuthService) Logout(refreshToken uuid.UUID, ctx context.Context) (mainE
 			Message: "failed get session",
 		}
 	}
-	if err := s.storageSession.Delete(session.ID, ctx); err != nil {
-		return base.NewPostgresReadError(err)
+	if session.UserID != accessClaims.UserID {
+		return &base.ServiceError{
+			Err:     errors.New("session belongs to another user"),
+			Blame:   base.BlameUser,
+			Code:    http.StatusForbidden,
+			Message: "no access",
+		}
 	}
 
-	return nil
+	if err := s.storageSession.DeleteByFamilyID(session.FamilyID, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	return s.revocationService.RevokeToken(accessClaims, ctx)
 }
 
-func (s *AuthService) RefreshJWT(id uuid.UUID, ctx context.Context) (*string, *uuid.UUID, *uuid.UUID, *base.ServiceError) {
+func (s *AuthService) RefreshJWT(id uuid.UUID, client helpers.ClientInfo, ctx context.Context) (*string, *uuid.UUID, *uuid.UUID, *base.ServiceError) {
 	session, err := s.storageSession.Retrieve(id, ctx)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -135,22 +408,143 @@ func (s *AuthService) RefreshJWT(id uuid.UUID, ctx context.Context) (*string, *u
 		return nil, nil, nil, base.NewPostgresReadError(err)
 	}
 
-	newSession := &entity.Session{
-		UserID: session.UserID,
+	if session.RotatedAt != nil {
+		return nil, nil, nil, s.revokeReusedFamily(session, ctx)
 	}
 
-	if err := s.storageSession.Delete(session.ID, ctx); err != nil {
-		return nil, nil, nil, base.NewPostgresReadError(err)
+	now := time.Now()
+
+	if session.IsExpired(now) {
+		if err := s.storageSession.DeleteByFamilyID(session.FamilyID, ctx); err != nil {
+			return nil, nil, nil, base.NewPostgresWriteError(err)
+		}
+		return nil, nil, nil, base.NewExpiredDate(errors.New("session expired"))
+	}
+
+	rotated, err := s.storageSession.MarkRotated(session.ID, now, ctx)
+	if err != nil {
+		return nil, nil, nil, base.NewPostgresWriteError(err)
+	}
+
+	if !rotated {
+		return nil, nil, nil, s.revokeReusedFamily(session, ctx)
+	}
+
+	newSession := &entity.Session{
+		UserID:            session.UserID,
+		DeviceID:          session.DeviceID,
+		UserAgent:         client.UserAgent,
+		IPAddress:         client.IPAddress,
+		StartedAt:         session.StartedAt,
+		LastUsedAt:        now,
+		FamilyID:          session.FamilyID,
+		ExpiresAt:         s.slidingExpiry(now, session.AbsoluteExpiresAt),
+		AbsoluteExpiresAt: session.AbsoluteExpiresAt,
 	}
 
 	if err := s.storageSession.Create(newSession, ctx); err != nil {
 		return nil, nil, nil, base.NewPostgresWriteError(err)
 	}
 
-	token, err := s.jwtManager.NewJWT(session.UserID)
+	token, err := s.jwtManager.NewJWT(session.UserID, session.FamilyID)
 	if err != nil {
 		return nil, nil, nil, base.NewCreateJWTError(err)
 	}
 
 	return &token, &newSession.ID, &newSession.UserID, nil
 }
+
+// GetSessions returns active sessions of the user. The session the request was made with is flagged as current.
+func (s *AuthService) GetSessions(userID uuid.UUID, currentSessionID uuid.UUID, ctx context.Context) ([]model.SessionObject, *base.ServiceError) {
+	sessions, err := s.storageSession.GetByUserID(userID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	now := time.Now()
+	sessionObjects := make([]model.SessionObject, 0, len(sessions))
+	for _, session := range sessions {
+		if session.IsExpired(now) {
+			continue
+		}
+
+		sessionObjects = append(sessionObjects, model.SessionObject{
+			ID:         session.FamilyID,
+			DeviceID:   session.DeviceID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.StartedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.FamilyID == currentSessionID,
+		})
+	}
+
+	return sessionObjects, nil
+}
+
+// RevokeSession ends the session of the user and revokes access tokens issued for it.
+func (s *AuthService) RevokeSession(userID uuid.UUID, sessionID uuid.UUID, ctx context.Context) *base.ServiceError {
+	deleted, err := s.storageSession.DeleteByUserIDAndFamilyID(userID, sessionID, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if !deleted {
+		return &base.ServiceError{
+			Err:     fmt.Errorf("session %s not found", sessionID),
+			Blame:   base.BlameUser,
+			Code:    http.StatusNotFound,
+			Message: "session not found",
+		}
+	}
+
+	s.auditService.Record(enum.AuditSessionRevoke, enum.AuditTargetSession, sessionID, map[string]any{"user_id": userID}, nil, ctx)
+
+	return s.revocationService.RevokeSessionTokens(userID, sessionID, ctx)
+}
+
+// RevokeOtherSessions ends every session of the user except the current one.
+func (s *AuthService) RevokeOtherSessions(userID uuid.UUID, currentSessionID uuid.UUID, ctx context.Context) *base.ServiceError {
+	sessionIDs, err := s.storageSession.DeleteByUserIDExceptFamily(userID, currentSessionID, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	for _, sessionID := range sessionIDs {
+		s.auditService.Record(enum.AuditSessionRevoke, enum.AuditTargetSession, sessionID, map[string]any{"user_id": userID}, nil, ctx)
+
+		if serviceErr := s.revocationService.RevokeSessionTokens(userID, sessionID, ctx); serviceErr != nil {
+			return serviceErr
+		}
+	}
+
+	return nil
+}
+
+// GetLockoutEvents returns login lockouts for administrators.
+func (s *AuthService) GetLockoutEvents(options *dataProcessing.Options, ctx context.Context) ([]model.LockoutEventObject, int64, *base.ServiceError) {
+	return s.loginThrottleService.GetLockoutEvents(options, ctx)
+}
+
+// JWKS returns public keys other services use to verify access tokens issued by this backend.
+func (s *AuthService) JWKS() auth.JWKSet {
+	return s.jwtManager.JWKS()
+}
+
+// revokeReusedFamily handles presentation of an already rotated refresh token.
+// The token was most likely stolen, so every session of its family is revoked.
+func (s *AuthService) revokeReusedFamily(session *entity.Session, ctx context.Context) *base.ServiceError {
+	s.logger.Warn(fmt.Sprintf("refresh token reuse detected: user %s, session family %s", session.UserID, session.FamilyID))
+
+	if err := s.storageSession.DeleteByFamilyID(session.FamilyID, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	// access tokens already issued to the family may be in the hands of whoever replayed the refresh token
+	if serviceErr := s.revocationService.RevokeSessionTokens(session.UserID, session.FamilyID, ctx); serviceErr != nil {
+		return serviceErr
+	}
+
+	return base.NewRefreshTokenReuseError(fmt.Errorf("session %s has already been rotated", session.ID))
+}
@@ -4,9 +4,11 @@
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/helpers"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
@@ -15,18 +17,33 @@
 	"net/http"
 )
 
+// CandidateService manages candidates. Their names and emails are shown only to users
+// who may read candidates of the company.
 type CandidateService struct {
 	logger                 *zap.Logger
+	auditService           *AuditService
 	vacancyStorage         *dao.VacancyStorage
 	candidateStorage       *dao.CandidateStorage
+	companyStorage         *dao.CompanyStorage
+	roleService            *RoleService
 	cameoMetricsHttpClient *helpers.HttpClient
 }
 
-func NewCandidateService(logger *zap.Logger, vacancyStorage *dao.VacancyStorage, candidateStorage *dao.CandidateStorage, cameoMetricsHttpClient *helpers.HttpClient) *CandidateService {
+func NewCandidateService(
+	logger *zap.Logger,
+	auditService *AuditService,
+	vacancyStorage *dao.VacancyStorage,
+	candidateStorage *dao.CandidateStorage,
+	companyStorage *dao.CompanyStorage,
+	roleService *RoleService,
+	cameoMetricsHttpClient *helpers.HttpClient) *CandidateService {
 	return &CandidateService{
 		logger:                 logger,
+		auditService:           auditService,
 		candidateStorage:       candidateStorage,
 		vacancyStorage:         vacancyStorage,
+		companyStorage:         companyStorage,
+		roleService:            roleService,
 		cameoMetricsHttpClient: cameoMetricsHttpClient,
 	}
 }
@@ -37,6 +54,15 @@ func (s *CandidateService) AddNewCandidate(vacancyId uuid.UUID, request *model.A
 		return nil, base.NewPostgresReadError(err)
 	}
 
+	if vacancy.ClosedAt != nil {
+		return nil, &base.ServiceError{
+			Err:     fmt.Errorf("vacancy %s is closed", vacancyId),
+			Blame:   base.BlameUser,
+			Code:    http.StatusConflict,
+			Message: "vacancy is closed",
+		}
+	}
+
 	type responseModel struct {
 		SystemID string `json:"id"`
 	}
@@ -91,27 +117,49 @@ type requestModel struct {
 		return nil, base.NewPostgresWriteError(err)
 	}
 
+	s.auditService.Record(enum.AuditCandidateCreate, enum.AuditTargetCandidate, newCandidate.ID, nil, map[string]any{
+		"vacancy_id": newCandidate.VacancyID,
+		"name":       newCandidate.Name,
+		"email":      newCandidate.Email,
+		"system_id":  newCandidate.SystemID,
+	}, ctx)
+
 	return &newCandidate.ID, nil
 }
 
-func (s *CandidateService) RetrieveCandidate(candidateID uuid.UUID, ctx context.Context) (*model.CandidateObject, *base.ServiceError) {
+// RetrieveCandidate returns the candidate to the viewer, nil for anonymous users.
+func (s *CandidateService) RetrieveCandidate(candidateID uuid.UUID, viewerID *uuid.UUID, ctx context.Context) (*model.CandidateObject, *base.ServiceError) {
 	candidate, err := s.candidateStorage.Retrieve(candidateID, ctx)
 	if err != nil {
 		return nil, base.NewPostgresReadError(err)
 	}
 
-	return &model.CandidateObject{
-		ID:        candidate.ID,
-		CreatedAt: candidate.CreatedAt,
-		UpdatedAt: candidate.UpdatedAt,
-		Name:      candidate.Name,
-		Email:     candidate.Email,
-		SystemID:  candidate.SystemID,
-	}, nil
+	companyID, err := s.companyStorage.GetIDByVacancy(candidate.VacancyID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	showPersonalData, serviceErr := s.roleService.CanAccess(viewerID, companyID, enum.PermissionReadCandidates, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	result := candidateObject(candidate, showPersonalData)
+	return &result, nil
 }
 
-func (s *CandidateService) GetCandidate(option *dataProcessing.Options, ctx context.Context) ([]model.CandidateObject, *base.ServiceError) {
-	candidates, _, err := s.candidateStorage.Get(option, ctx)
+// GetCandidate returns candidates of the companies in which the user may read candidates.
+func (s *CandidateService) GetCandidate(userID uuid.UUID, option *dataProcessing.Options, ctx context.Context) ([]model.CandidateObject, *base.ServiceError) {
+	all, companyIDs, serviceErr := s.roleService.GetCompaniesWithPermission(userID, enum.PermissionReadCandidates, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	if all {
+		companyIDs = nil
+	}
+
+	candidates, _, err := s.candidateStorage.Get(option, companyIDs, ctx)
 	if err != nil {
 		return nil, base.NewPostgresReadError(err)
 	}
@@ -119,15 +167,27 @@ func (s *CandidateService) GetCandidate(option *dataProcessing.Options, ctx cont
 	result := make([]model.CandidateObject, 0, len(candidates))
 
 	for _, candidate := range candidates {
-		result = append(result, model.CandidateObject{
-			ID:        candidate.ID,
-			CreatedAt: candidate.CreatedAt,
-			UpdatedAt: candidate.UpdatedAt,
-			Name:      candidate.Name,
-			Email:     candidate.Email,
-			SystemID:  candidate.SystemID,
-		})
+		result = append(result, candidateObject(&candidate, true))
 	}
 
 	return result, nil
 }
+
+// candidateObject converts the candidate, hiding the name and email unless showPersonalData is set.
+func candidateObject(candidate *entity.Candidate, showPersonalData bool) model.CandidateObject {
+	result := model.CandidateObject{
+		ID:        candidate.ID,
+		CreatedAt: candidate.CreatedAt,
+		UpdatedAt: candidate.UpdatedAt,
+		SystemID:  candidate.SystemID,
+	}
+
+	if showPersonalData {
+		result.Name = candidate.Name
+		result.Email = candidate.Email
+	} else {
+		result.PersonalDataHidden = true
+	}
+
+	return result
+}
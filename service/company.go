@@ -2,48 +2,80 @@
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/common"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/helpers"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/s3"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 	"io"
+	"math"
+	"net/http"
 	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// companyPurgeInterval is how often archived companies are checked for purging.
+	companyPurgeInterval = time.Hour
+	// statsShortPeriod and statsLongPeriod are the periods new candidates are counted within.
+	statsShortPeriod = 7 * 24 * time.Hour
+	statsLongPeriod  = 30 * 24 * time.Hour
 )
 
 type CompanyService struct {
 	logger         *zap.Logger
+	auditService   *AuditService
 	companyStorage *dao.CompanyStorage
 	userService    *UserService
+	roleService    *RoleService
 	vacancyService *VacancyService
 	fileStorage    *dao.FileStorage
 	minioService   s3.ObjectStoreService
+	archiveConfig  common.CompanyArchiveConfig
 }
 
 func NewCompanyService(
 	logger *zap.Logger,
+	auditService *AuditService,
 	companyStorage *dao.CompanyStorage,
 	userService *UserService,
+	roleService *RoleService,
 	vacancyService *VacancyService,
 	fileStorage *dao.FileStorage,
-	minioService s3.ObjectStoreService) *CompanyService {
+	minioService s3.ObjectStoreService,
+	archiveConfig common.CompanyArchiveConfig) *CompanyService {
 	return &CompanyService{
 		logger:         logger,
+		auditService:   auditService,
 		companyStorage: companyStorage,
 		userService:    userService,
+		roleService:    roleService,
 		vacancyService: vacancyService,
 		fileStorage:    fileStorage,
 		minioService:   minioService,
+		archiveConfig:  archiveConfig,
 	}
 }
 
 func (s *CompanyService) CreateNewCompany(ownerID uuid.UUID, request *model.CreateCompanyRequest, ctx context.Context) (*uuid.UUID, *base.ServiceError) {
+	slug, serviceErr := s.newSlug(request.Name, uuid.Nil, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
 	newCompany := &entity.Company{
 		Name:        request.Name,
+		Slug:        slug,
 		Description: request.Description,
 		Owner:       ownerID,
 	}
@@ -52,6 +84,12 @@ func (s *CompanyService) CreateNewCompany(ownerID uuid.UUID, request *model.Crea
 		return nil, base.NewPostgresWriteError(err)
 	}
 
+	s.auditService.Record(enum.AuditCompanyCreate, enum.AuditTargetCompany, newCompany.ID, nil, newCompanyAuditState(newCompany), ctx)
+
+	if serviceErr := s.roleService.GrantCompanyRole(ownerID, newCompany.ID, enum.RoleCompanyOwner, ctx); serviceErr != nil {
+		return nil, serviceErr
+	}
+
 	file, err := os.Open("./static/default_avatar.jpg")
 	if err != nil {
 		return nil, base.NewPostgresReadError(err)
@@ -66,138 +104,486 @@ func (s *CompanyService) CreateNewCompany(ownerID uuid.UUID, request *model.Crea
 
 }
 
+// UploadLogo replaces the logo of the company, it is stored as WebP in every rendition size.
+// The renditions of the former logo are removed.
 func (s *CompanyService) UploadLogo(companyID uuid.UUID, file io.Reader, ctx context.Context) *base.ServiceError {
 	company, err := s.companyStorage.Retrieve(companyID, ctx)
 	if err != nil {
 		return base.NewPostgresReadError(err)
 	}
 
-	fileId, serviceErr := s.minioService.UploadAsWebP(ctx, enum.CompanyLogo, file)
+	widths := logoWidths()
+	fileId, serviceErr := s.minioService.UploadAsWebPRenditions(ctx, enum.CompanyLogo, file, widths)
 	if serviceErr != nil {
 		return serviceErr
 	}
 
 	newFile := &entity.File{
-		Key:    *fileId,
-		Bucket: string(enum.CompanyLogo),
+		Key:        *fileId,
+		Bucket:     string(enum.CompanyLogo),
+		Renditions: true,
 	}
 
 	if err := s.fileStorage.Create(newFile, ctx); err != nil {
+		_ = s.minioService.DeleteWebPRenditions(ctx, enum.CompanyLogo, *fileId, widths)
 		return base.NewPostgresWriteError(err)
 	}
 
-	if company.File != nil {
-		if serviceErr := s.minioService.DeleteWebPFile(ctx, enum.CompanyLogo, company.File.Key); serviceErr != nil {
-			return serviceErr
-		}
-	}
+	before := newCompanyAuditState(company)
+	formerFile := company.File
 
-	company.FileID = fileId
+	company.FileID = &newFile.ID
 	company.File = newFile
 
 	if err := s.companyStorage.Update(company, ctx); err != nil {
 		return base.NewPostgresWriteError(err)
 	}
 
+	s.auditService.Record(enum.AuditCompanyLogoUpload, enum.AuditTargetCompany, company.ID, before, newCompanyAuditState(company), ctx)
+
+	// the new logo is in place already, so a former one left behind is only logged
+	if formerFile != nil {
+		if serviceErr := s.removeFile(formerFile, ctx); serviceErr != nil {
+			s.logger.Error(fmt.Sprintf("failed to remove former logo %s of company %s: %v", formerFile.ID, company.ID, serviceErr.Err))
+		}
+	}
+
 	return nil
 }
 
-func (s *CompanyService) getLogoURL(company *entity.Company, ctx context.Context) (*string, *base.ServiceError) {
-	var pictureURL *string
+// UpdateCompany changes the name and description of the company. A new name gives the company
+// a new slug, the former one keeps leading to the company.
+func (s *CompanyService) UpdateCompany(companyID uuid.UUID, request *model.UpdateCompanyRequest, ctx context.Context) *base.ServiceError {
+	company, serviceErr := s.getCompany(companyID, ctx)
+	if serviceErr != nil {
+		return serviceErr
+	}
 
-	if company.File != nil {
-		url, serviceErr := s.minioService.GetWebPFileURL(ctx, enum.CompanyLogo, company.File.Key)
+	before := newCompanyAuditState(company)
+	formerName, formerSlug := company.Name, company.Slug
+
+	if request.Name != nil {
+		company.Name = strings.TrimSpace(*request.Name)
+	}
+
+	if request.Description != nil {
+		company.Description = *request.Description
+	}
+
+	if company.Name == "" {
+		return &base.ServiceError{
+			Err:     errors.New("empty company name"),
+			Blame:   base.BlameUser,
+			Code:    http.StatusBadRequest,
+			Message: "company name is required",
+		}
+	}
+
+	if helpers.Slugify(company.Name, entity.CompanySlugFallback) != helpers.Slugify(formerName, entity.CompanySlugFallback) {
+		company.Slug, serviceErr = s.newSlug(company.Name, company.ID, ctx)
 		if serviceErr != nil {
-			return nil, serviceErr
+			return serviceErr
+		}
+	}
+
+	if err := s.companyStorage.UpdateDetails(company, formerSlug, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditCompanyUpdate, enum.AuditTargetCompany, company.ID, before, newCompanyAuditState(company), ctx)
+
+	return nil
+}
+
+// ArchiveCompany archives the company: its vacancies are closed, pending invitations deleted and the logo removed.
+// Members keep reading candidates until the company is purged after the retention period.
+func (s *CompanyService) ArchiveCompany(companyID uuid.UUID, ctx context.Context) *base.ServiceError {
+	company, serviceErr := s.getCompany(companyID, ctx)
+	if serviceErr != nil {
+		return serviceErr
+	}
+
+	archived, err := s.companyStorage.Archive(company.ID, time.Now(), ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if !archived {
+		return base.NewNotFoundError(fmt.Errorf("company %s was archived meanwhile", companyID))
+	}
+
+	before := newCompanyAuditState(company)
+	company.FileID = nil
+	s.auditService.Record(enum.AuditCompanyArchive, enum.AuditTargetCompany, company.ID, before, newCompanyAuditState(company), ctx)
+
+	// the company is archived already, so a logo left behind is only logged
+	if company.File != nil {
+		if serviceErr := s.removeFile(company.File, ctx); serviceErr != nil {
+			s.logger.Error(fmt.Sprintf("failed to remove logo %s of archived company %s: %v", company.File.ID, company.ID, serviceErr.Err))
+		}
+	}
+
+	s.logger.Info(fmt.Sprintf("company %s archived", company.ID))
+
+	return nil
+}
+
+// StartPurge periodically purges companies archived longer than the retention period ago. It blocks until ctx is done.
+func (s *CompanyService) StartPurge(ctx context.Context) {
+	if s.archiveConfig.Retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(companyPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		s.purgeArchivedCompanies(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *CompanyService) purgeArchivedCompanies(ctx context.Context) {
+	companies, err := s.companyStorage.GetArchivedBefore(time.Now().Add(-s.archiveConfig.Retention), ctx)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to get companies to purge: %v", err))
+		return
+	}
+
+	for _, company := range companies {
+		documents, err := s.companyStorage.Purge(company.ID, ctx)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("failed to purge company %s: %v", company.ID, err))
+			continue
+		}
+
+		s.auditService.Record(enum.AuditCompanyPurge, enum.AuditTargetCompany, company.ID, newCompanyAuditState(&company), nil, ctx)
+
+		// the company is gone already, so leftover files are only logged
+		for _, document := range documents {
+			if serviceErr := removeDocumentFile(s.minioService, s.fileStorage, &document, ctx); serviceErr != nil {
+				s.logger.Error(fmt.Sprintf("failed to remove file %s of purged company %s: %v", document.FileID, company.ID, serviceErr.Err))
+			}
+		}
+
+		s.logger.Info(fmt.Sprintf("company %s purged", company.ID))
+	}
+}
+
+// GetStats returns hiring numbers of the company. They are aggregated by the database,
+// vacancies and candidates are not loaded.
+func (s *CompanyService) GetStats(companyID uuid.UUID, ctx context.Context) (*model.CompanyStatsObject, *base.ServiceError) {
+	exists, err := s.companyStorage.Exists(companyID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	if !exists {
+		return nil, base.NewNotFoundError(fmt.Errorf("company %s not found", companyID))
+	}
+
+	now := time.Now()
+	weekStart, monthStart := now.Add(-statsShortPeriod), now.Add(-statsLongPeriod)
+
+	stats, err := s.companyStorage.GetStats(companyID, now, weekStart, monthStart, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	vacancyStats, err := s.companyStorage.GetVacancyStats(companyID, now, weekStart, monthStart, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	vacancies := make([]model.VacancyStatsObject, 0, len(vacancyStats))
+	for _, vacancy := range vacancyStats {
+		vacancies = append(vacancies, model.VacancyStatsObject{
+			ID:                          vacancy.ID,
+			Name:                        vacancy.Name,
+			Slug:                        vacancy.Slug,
+			Salary:                      vacancy.Salary,
+			ClosedAt:                    vacancy.ClosedAt,
+			Candidates:                  vacancy.Candidates,
+			NewCandidates7Days:          vacancy.CandidatesSinceWeek,
+			NewCandidates30Days:         vacancy.CandidatesSinceMonth,
+			AverageDaysSinceApplication: secondsToDays(vacancy.AverageApplicationAge),
+		})
+	}
+
+	var averageSalary *int
+	if stats.AverageSalary != nil {
+		average := int(math.Round(*stats.AverageSalary))
+		averageSalary = &average
+	}
+
+	return &model.CompanyStatsObject{
+		OpenVacancies:               stats.OpenVacancies,
+		ClosedVacancies:             stats.ClosedVacancies,
+		Candidates:                  stats.Candidates,
+		NewCandidates7Days:          stats.CandidatesSinceWeek,
+		NewCandidates30Days:         stats.CandidatesSinceMonth,
+		AverageDaysSinceApplication: secondsToDays(stats.AverageApplicationAge),
+		Salary: model.SalaryRangeObject{
+			Min:     stats.MinSalary,
+			Max:     stats.MaxSalary,
+			Average: averageSalary,
+		},
+		Vacancies: vacancies,
+	}, nil
+}
+
+// secondsToDays converts the duration in seconds to days rounded to a tenth.
+func secondsToDays(seconds *float64) *float64 {
+	if seconds == nil {
+		return nil
+	}
+
+	days := math.Round(*seconds/(24*60*60)*10) / 10
+	return &days
+}
+
+// GetCompanyIDBySlug returns ID and the current slug of the company with the current or a former slug.
+func (s *CompanyService) GetCompanyIDBySlug(slug string, ctx context.Context) (uuid.UUID, string, *base.ServiceError) {
+	company, err := s.companyStorage.RetrieveBySlug(slug, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return uuid.Nil, "", base.NewNotFoundError(fmt.Errorf("company %q not found", slug))
 		}
+		return uuid.Nil, "", base.NewPostgresReadError(err)
+	}
+
+	return company.ID, company.Slug, nil
+}
+
+// newSlug returns a slug for the company name which is not taken by other companies.
+func (s *CompanyService) newSlug(name string, companyID uuid.UUID, ctx context.Context) (string, *base.ServiceError) {
+	slug := helpers.Slugify(name, entity.CompanySlugFallback)
+
+	taken, err := s.companyStorage.GetTakenSlugs(slug, companyID, ctx)
+	if err != nil {
+		return "", base.NewPostgresReadError(err)
+	}
+
+	return helpers.UniqueSlug(slug, newSlugSet(taken)), nil
+}
 
-		pictureURL = &url
+func newSlugSet(slugs []string) map[string]bool {
+	set := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		set[slug] = true
 	}
 
-	return pictureURL, nil
+	return set
 }
 
-func (s *CompanyService) RetrieveCompany(companyID uuid.UUID, ctx context.Context) (*model.CompanyObject, *base.ServiceError) {
+func (s *CompanyService) getCompany(companyID uuid.UUID, ctx context.Context) (*entity.Company, *base.ServiceError) {
 	company, err := s.companyStorage.Retrieve(companyID, ctx)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, base.NewNotFoundError(fmt.Errorf("company %s not found", companyID))
+		}
 		return nil, base.NewPostgresReadError(err)
 	}
 
-	logoURL, serviceErr := s.getLogoURL(company, ctx)
-	if serviceErr != nil {
-		return nil, serviceErr
+	return company, nil
+}
+
+func (s *CompanyService) removeFile(file *entity.File, ctx context.Context) *base.ServiceError {
+	if serviceErr := removePicture(s.minioService, file, ctx); serviceErr != nil {
+		return serviceErr
 	}
 
-	users := make([]model.UserObject, 0, len(company.Users))
+	if err := s.fileStorage.Delete(file.ID, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
 
-	for _, user := range company.Users {
-		um, serviceErr := s.userService.RetrieveUser(user.ID, ctx)
+	return nil
+}
+
+// setLogo fills the logo URLs of the company object. A logo without renditions has the single URL.
+func (s *CompanyService) setLogo(object *model.CompanyObject, company *entity.Company, ctx context.Context) *base.ServiceError {
+	if company.File == nil {
+		return nil
+	}
+
+	if !company.File.Renditions {
+		url, serviceErr := s.minioService.GetWebPFileURL(ctx, enum.CompanyLogo, company.File.Key)
 		if serviceErr != nil {
-			return nil, serviceErr
+			return serviceErr
 		}
-		users = append(users, *um)
+
+		object.LogoURL = url
+		return nil
 	}
 
-	vacances := make([]model.VacancyObject, 0, len(company.Vacancies))
+	object.LogoRenditions = make(map[enum.LogoRendition]string, len(enum.LogoRenditions))
+	srcSet := make([]string, 0, len(enum.LogoRenditions))
 
-	for _, vacancy := range company.Vacancies {
-		vm, serviceErr := s.vacancyService.RetrieveVacancy(vacancy.ID, ctx)
+	for _, rendition := range enum.LogoRenditions {
+		url, serviceErr := s.minioService.GetWebPRenditionURL(ctx, enum.CompanyLogo, company.File.Key, rendition.Width())
 		if serviceErr != nil {
-			return nil, serviceErr
+			return serviceErr
 		}
 
-		vacances = append(vacances, *vm)
+		object.LogoRenditions[rendition] = url
+		srcSet = append(srcSet, fmt.Sprintf("%s %dw", url, rendition.Width()))
 	}
 
-	if logoURL != nil {
-		return &model.CompanyObject{
-			ID:          company.ID,
-			CreatedAt:   company.CreatedAt,
-			UpdatedAt:   company.UpdatedAt,
-			Name:        company.Name,
-			Description: company.Description,
-			LogoURL:     *logoURL,
-			Users:       users,
-			Vacancies:   vacances,
-		}, nil
-	} else {
-		return &model.CompanyObject{
-			ID:          company.ID,
-			CreatedAt:   company.CreatedAt,
-			UpdatedAt:   company.UpdatedAt,
-			Name:        company.Name,
-			Description: company.Description,
-			LogoURL:     "",
-			Users:       users,
-			Vacancies:   vacances,
-		}, nil
+	object.LogoURL = object.LogoRenditions[enum.LogoFull]
+	object.LogoSrcSet = strings.Join(srcSet, ", ")
+
+	return nil
+}
+
+// logoWidths are the widths company logos are stored in.
+func logoWidths() []int {
+	widths := make([]int, 0, len(enum.LogoRenditions))
+	for _, rendition := range enum.LogoRenditions {
+		widths = append(widths, rendition.Width())
+	}
+
+	return widths
+}
+
+// removePicture removes the stored picture of the file, all its renditions if it has them.
+func removePicture(minioService s3.ObjectStoreService, file *entity.File, ctx context.Context) *base.ServiceError {
+	if file.Renditions {
+		return minioService.DeleteWebPRenditions(ctx, enum.Bucket(file.Bucket), file.Key, logoWidths())
+	}
+
+	return minioService.DeleteWebPFile(ctx, enum.Bucket(file.Bucket), file.Key)
+}
+
+// GetVacancyCompanyID returns ID of the company the vacancy belongs to.
+// It implements middleware.CompanyResolver.
+func (s *CompanyService) GetVacancyCompanyID(vacancyID uuid.UUID, ctx context.Context) (uuid.UUID, *base.ServiceError) {
+	companyID, err := s.companyStorage.GetIDByVacancy(vacancyID, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return uuid.Nil, base.NewNotFoundError(fmt.Errorf("vacancy %s not found", vacancyID))
+		}
+		return uuid.Nil, base.NewPostgresReadError(err)
+	}
+
+	return companyID, nil
+}
+
+// RetrieveCompany returns the company to the viewer, nil for anonymous users, with the included children.
+func (s *CompanyService) RetrieveCompany(companyID uuid.UUID, viewerID *uuid.UUID, includes model.CompanyIncludes, ctx context.Context) (*model.CompanyObject, *base.ServiceError) {
+	company, err := s.companyStorage.Retrieve(companyID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
 	}
+
+	result, serviceErr := s.companyObjects([]entity.Company{*company}, viewerID, includes, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	return &result[0], nil
 }
 
-func (s *CompanyService) GetCompany(options *dataProcessing.Options, ctx context.Context) ([]model.CompanyObject, *base.ServiceError) {
+// GetCompany returns a page of companies to the viewer, nil for anonymous users, with the included children.
+func (s *CompanyService) GetCompany(options *dataProcessing.Options, viewerID *uuid.UUID, includes model.CompanyIncludes, ctx context.Context) ([]model.CompanyObject, *base.ServiceError) {
 	companies, _, err := s.companyStorage.Get(options, ctx)
 	if err != nil {
 		return nil, base.NewPostgresReadError(err)
 	}
 
-	result := make([]model.CompanyObject, 0, len(companies))
+	return s.companyObjects(companies, viewerID, includes, ctx)
+}
+
+// ParseCompanyIncludes parses the comma separated list of children to load along with companies.
+func ParseCompanyIncludes(value string) (model.CompanyIncludes, *base.ServiceError) {
+	var includes model.CompanyIncludes
 
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		switch include := enum.CompanyInclude(item); include {
+		case enum.CompanyIncludeUsers:
+			includes.Users = true
+		case enum.CompanyIncludeVacancies:
+			includes.Vacancies = true
+		case enum.CompanyIncludeCandidates:
+			includes.Vacancies = true
+			includes.Candidates = true
+		default:
+			return model.CompanyIncludes{}, &base.ServiceError{
+				Err:     fmt.Errorf("unknown include %q", item),
+				Blame:   base.BlameUser,
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("%q can't be included, use users, vacancies or candidates", item),
+			}
+		}
+	}
+
+	return includes, nil
+}
+
+// companyObjects converts the companies. Each kind of included children is loaded with a single query
+// for all the companies, so the number of queries doesn't grow with the companies.
+func (s *CompanyService) companyObjects(companies []entity.Company, viewerID *uuid.UUID, includes model.CompanyIncludes, ctx context.Context) ([]model.CompanyObject, *base.ServiceError) {
+	companyIDs := make([]uuid.UUID, 0, len(companies))
 	for _, company := range companies {
-		logoURL, serviceErr := s.getLogoURL(&company, ctx)
-		if serviceErr != nil {
+		companyIDs = append(companyIDs, company.ID)
+	}
+
+	var users map[uuid.UUID][]model.UserObject
+	if includes.Users {
+		var serviceErr *base.ServiceError
+		if users, serviceErr = s.userService.GetCompanyUsers(companyIDs, ctx); serviceErr != nil {
 			return nil, serviceErr
 		}
+	}
+
+	var vacancies map[uuid.UUID][]model.VacancyObject
+	if includes.Vacancies {
+		var serviceErr *base.ServiceError
+		if vacancies, serviceErr = s.vacancyService.GetCompanyVacancies(companyIDs, viewerID, includes.Candidates, ctx); serviceErr != nil {
+			return nil, serviceErr
+		}
+	}
 
-		result = append(result, model.CompanyObject{
+	result := make([]model.CompanyObject, 0, len(companies))
+
+	for _, company := range companies {
+		object := model.CompanyObject{
 			ID:          company.ID,
 			CreatedAt:   company.CreatedAt,
 			UpdatedAt:   company.UpdatedAt,
 			Name:        company.Name,
+			Slug:        company.Slug,
 			Description: company.Description,
-			LogoURL:     *logoURL,
-			Users:       nil,
-			Vacancies:   nil,
-		})
+		}
+
+		if serviceErr := s.setLogo(&object, &company, ctx); serviceErr != nil {
+			return nil, serviceErr
+		}
+
+		if includes.Users {
+			object.Users = users[company.ID]
+			if object.Users == nil {
+				object.Users = []model.UserObject{}
+			}
+		}
+
+		if includes.Vacancies {
+			object.Vacancies = vacancies[company.ID]
+			if object.Vacancies == nil {
+				object.Vacancies = []model.VacancyObject{}
+			}
+		}
+
+		result = append(result, object)
 	}
 
 	return result, nil
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/common"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/s3"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// documentSniffLength is how much of a file http.DetectContentType looks at.
+const documentSniffLength = 512
+
+// documentFileType describes an accepted document format. DOCX and XLSX files are detected as ZIP archives.
+type documentFileType struct {
+	contentType string
+	detected    string
+}
+
+var documentFileTypes = map[string]documentFileType{
+	"pdf":  {contentType: "application/pdf", detected: "application/pdf"},
+	"docx": {contentType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document", detected: "application/zip"},
+	"xlsx": {contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", detected: "application/zip"},
+}
+
+// CompanyDocumentService keeps contracts, NDAs and other files of companies in the document bucket.
+// Documents are downloaded by presigned URLs.
+type CompanyDocumentService struct {
+	documentStorage *dao.CompanyDocumentStorage
+	companyStorage  *dao.CompanyStorage
+	fileStorage     *dao.FileStorage
+	minioService    s3.ObjectStoreService
+	logger          *zap.Logger
+	auditService    *AuditService
+	config          common.CompanyDocumentConfig
+}
+
+func NewCompanyDocumentService(
+	documentStorage *dao.CompanyDocumentStorage,
+	companyStorage *dao.CompanyStorage,
+	fileStorage *dao.FileStorage,
+	minioService s3.ObjectStoreService,
+	logger *zap.Logger,
+	auditService *AuditService,
+	config common.CompanyDocumentConfig) *CompanyDocumentService {
+	return &CompanyDocumentService{
+		documentStorage: documentStorage,
+		companyStorage:  companyStorage,
+		fileStorage:     fileStorage,
+		minioService:    minioService,
+		logger:          logger,
+		auditService:    auditService,
+		config:          config,
+	}
+}
+
+// MaxFileSize returns the largest document accepted in bytes.
+func (s *CompanyDocumentService) MaxFileSize() int64 {
+	return s.config.MaxFileSize
+}
+
+// UploadDocument stores a PDF, DOCX or XLSX file as a document of the company.
+func (s *CompanyDocumentService) UploadDocument(
+	companyID uuid.UUID,
+	uploaderID uuid.UUID,
+	file io.Reader,
+	fileName string,
+	size int64,
+	request *model.UploadCompanyDocumentRequest,
+	ctx context.Context) (*uuid.UUID, *base.ServiceError) {
+	if _, err := s.companyStorage.Retrieve(companyID, ctx); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, base.NewNotFoundError(fmt.Errorf("company %s not found", companyID))
+		}
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	if size > s.config.MaxFileSize {
+		return nil, &base.ServiceError{
+			Err:     fmt.Errorf("document of %d bytes", size),
+			Blame:   base.BlameUser,
+			Code:    http.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("a document can't be larger than %d bytes", s.config.MaxFileSize),
+		}
+	}
+
+	if !request.Type.IsValid() {
+		return nil, newInvalidDocumentError(fmt.Errorf("unknown document type %q", request.Type), "unknown document type")
+	}
+
+	validFrom, err := parseDocumentDate(request.ValidFrom)
+	if err != nil {
+		return nil, newInvalidDocumentError(err, "valid_from must be formatted as YYYY-MM-DD")
+	}
+
+	validUntil, err := parseDocumentDate(request.ValidUntil)
+	if err != nil {
+		return nil, newInvalidDocumentError(err, "valid_until must be formatted as YYYY-MM-DD")
+	}
+
+	if validFrom != nil && validUntil != nil && validUntil.Before(*validFrom) {
+		return nil, newInvalidDocumentError(errors.New("valid_until is before valid_from"), "the document can't expire before it is valid")
+	}
+
+	fileType := strings.ToLower(strings.TrimPrefix(filepath.Ext(fileName), "."))
+	format, ok := documentFileTypes[fileType]
+	if !ok {
+		return nil, newInvalidDocumentError(fmt.Errorf("file type %q", fileType), "only PDF, DOCX and XLSX files are accepted")
+	}
+
+	// the extension is checked against the content, so a renamed file is not stored as a document
+	head := make([]byte, documentSniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, newInvalidDocumentError(err, "failed to read the file")
+	}
+
+	if detected := http.DetectContentType(head[:n]); detected != format.detected {
+		return nil, newInvalidDocumentError(fmt.Errorf("%s file detected as %s", fileType, detected), "the file content doesn't match its extension")
+	}
+
+	title := strings.TrimSpace(request.Title)
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+	}
+
+	key, serviceErr := s.minioService.UploadDocument(ctx, s3.UploadInput{
+		File:        io.MultiReader(bytes.NewReader(head[:n]), file),
+		Size:        size,
+		ContentType: format.contentType,
+	}, fileType)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	newFile := &entity.File{
+		Key:    *key,
+		Bucket: string(enum.Document),
+	}
+
+	if err := s.fileStorage.Create(newFile, ctx); err != nil {
+		s.removeObject(*key, fileType, ctx)
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	document := &entity.CompanyDocument{
+		CompanyID:  companyID,
+		Type:       request.Type,
+		Title:      title,
+		FileID:     newFile.ID,
+		File:       newFile,
+		FileName:   filepath.Base(fileName),
+		FileType:   fileType,
+		Size:       size,
+		ValidFrom:  validFrom,
+		ValidUntil: validUntil,
+		UploadedBy: uploaderID,
+	}
+
+	if err := s.documentStorage.Create(document, ctx); err != nil {
+		if serviceErr := removeDocumentFile(s.minioService, s.fileStorage, document, ctx); serviceErr != nil {
+			s.logger.Error(fmt.Sprintf("failed to remove file %s of document not created: %v", newFile.ID, serviceErr.Err))
+		}
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditDocumentUpload, enum.AuditTargetDocument, document.ID, nil, newDocumentAuditState(document), ctx)
+
+	return &document.ID, nil
+}
+
+// GetDocuments returns documents of the company without download URLs.
+func (s *CompanyDocumentService) GetDocuments(companyID uuid.UUID, ctx context.Context) ([]model.CompanyDocumentObject, *base.ServiceError) {
+	documents, err := s.documentStorage.GetByCompanyID(companyID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	result := make([]model.CompanyDocumentObject, 0, len(documents))
+	for _, document := range documents {
+		result = append(result, companyDocumentObject(&document))
+	}
+
+	return result, nil
+}
+
+// RetrieveDocument returns the document of the company with a presigned download URL.
+func (s *CompanyDocumentService) RetrieveDocument(companyID uuid.UUID, documentID uuid.UUID, ctx context.Context) (*model.CompanyDocumentObject, *base.ServiceError) {
+	document, serviceErr := s.getDocument(companyID, documentID, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	if document.File == nil {
+		return nil, base.NewNotFoundError(fmt.Errorf("file %s of document %s not found", document.FileID, document.ID))
+	}
+
+	url, serviceErr := s.minioService.GetDocumentFileURL(ctx, string(enum.Document), document.File.Key.String(), document.FileType)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	result := companyDocumentObject(document)
+	result.DownloadURL = *url
+
+	return &result, nil
+}
+
+// DeleteDocument deletes the document of the company together with its file.
+func (s *CompanyDocumentService) DeleteDocument(companyID uuid.UUID, documentID uuid.UUID, ctx context.Context) *base.ServiceError {
+	document, serviceErr := s.getDocument(companyID, documentID, ctx)
+	if serviceErr != nil {
+		return serviceErr
+	}
+
+	deleted, err := s.documentStorage.Delete(document.ID, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if !deleted {
+		return base.NewNotFoundError(fmt.Errorf("document %s was deleted meanwhile", documentID))
+	}
+
+	s.auditService.Record(enum.AuditDocumentDelete, enum.AuditTargetDocument, document.ID, newDocumentAuditState(document), nil, ctx)
+
+	// the document is deleted already, so a file left behind is only logged
+	if serviceErr := removeDocumentFile(s.minioService, s.fileStorage, document, ctx); serviceErr != nil {
+		s.logger.Error(fmt.Sprintf("failed to remove file %s of deleted document %s: %v", document.FileID, document.ID, serviceErr.Err))
+	}
+
+	return nil
+}
+
+func (s *CompanyDocumentService) getDocument(companyID uuid.UUID, documentID uuid.UUID, ctx context.Context) (*entity.CompanyDocument, *base.ServiceError) {
+	document, err := s.documentStorage.Retrieve(documentID, companyID, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, base.NewNotFoundError(fmt.Errorf("document %s of company %s not found", documentID, companyID))
+		}
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	return document, nil
+}
+
+func (s *CompanyDocumentService) removeObject(key uuid.UUID, fileType string, ctx context.Context) {
+	if serviceErr := s.minioService.RemoveDocument(ctx, key, fileType); serviceErr != nil {
+		s.logger.Error(fmt.Sprintf("failed to remove document object %s: %v", key, serviceErr.Err))
+	}
+}
+
+// removeDocumentFile removes the stored file of the document and its record. The document must come with its file.
+func removeDocumentFile(minioService s3.ObjectStoreService, fileStorage *dao.FileStorage, document *entity.CompanyDocument, ctx context.Context) *base.ServiceError {
+	if document.File == nil {
+		return base.NewNotFoundError(fmt.Errorf("file %s of document %s not found", document.FileID, document.ID))
+	}
+
+	if serviceErr := minioService.RemoveDocument(ctx, document.File.Key, document.FileType); serviceErr != nil {
+		return serviceErr
+	}
+
+	if err := fileStorage.Delete(document.FileID, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	return nil
+}
+
+func companyDocumentObject(document *entity.CompanyDocument) model.CompanyDocumentObject {
+	return model.CompanyDocumentObject{
+		ID:         document.ID,
+		CreatedAt:  document.CreatedAt,
+		Type:       document.Type,
+		Title:      document.Title,
+		FileName:   document.FileName,
+		FileType:   document.FileType,
+		Size:       document.Size,
+		ValidFrom:  document.ValidFrom,
+		ValidUntil: document.ValidUntil,
+		UploadedBy: document.UploadedBy,
+	}
+}
+
+// parseDocumentDate parses an optional date of the document.
+func parseDocumentDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &date, nil
+}
+
+func newInvalidDocumentError(err error, message string) *base.ServiceError {
+	return &base.ServiceError{
+		Err:     err,
+		Blame:   base.BlameUser,
+		Code:    http.StatusBadRequest,
+		Message: message,
+	}
+}
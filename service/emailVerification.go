@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/common"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/mail"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+// EmailVerificationService confirms that users own their emails. A new account stays unverified
+// and an email change stays pending until the link sent to the address is opened.
+type EmailVerificationService struct {
+	userStorage  *dao.UserStorage
+	tokenStorage *dao.OneTimeTokenStorage
+	mailService  *mail.MailService
+	logger       *zap.Logger
+	auditService *AuditService
+	config       common.OneTimeLinkConfig
+}
+
+func NewEmailVerificationService(
+	userStorage *dao.UserStorage,
+	tokenStorage *dao.OneTimeTokenStorage,
+	mailService *mail.MailService,
+	logger *zap.Logger,
+	auditService *AuditService,
+	config common.OneTimeLinkConfig) *EmailVerificationService {
+	return &EmailVerificationService{
+		userStorage:  userStorage,
+		tokenStorage: tokenStorage,
+		mailService:  mailService,
+		logger:       logger,
+		auditService: auditService,
+		config:       config,
+	}
+}
+
+func (s *EmailVerificationService) IsEmailVerified(userID uuid.UUID, ctx context.Context) (bool, error) {
+	user, err := s.userStorage.Retrieve(userID, ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return user.IsEmailVerified(), nil
+}
+
+// RequestEmailChange stores the new email as pending and sends a confirmation link to it.
+// The login of the user does not change until the link is opened.
+func (s *EmailVerificationService) RequestEmailChange(user *entity.User, email string, ctx context.Context) *base.ServiceError {
+	exists, err := s.userStorage.ExistsByEmail(email, ctx)
+	if err != nil {
+		return base.NewPostgresReadError(err)
+	}
+
+	if exists {
+		return newEmailTakenError(email)
+	}
+
+	if err := s.userStorage.UpdatePendingEmail(user.ID, email, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	before := newUserAuditState(user)
+	user.PendingEmail = email
+
+	s.auditService.Record(enum.AuditUserUpdate, enum.AuditTargetUser, user.ID, before, newUserAuditState(user), ctx)
+
+	return s.SendVerification(user, email, ctx)
+}
+
+// SendVerification sends a confirmation link for the email to it. Previously sent links of the user stop working.
+func (s *EmailVerificationService) SendVerification(user *entity.User, email string, ctx context.Context) *base.ServiceError {
+	now := time.Now()
+
+	if err := s.tokenStorage.DeleteExpired(now, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if err := s.tokenStorage.DeleteByUserIDAndPurpose(user.ID, enum.EmailVerification, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	token, tokenHash, err := auth.NewOneTimeToken()
+	if err != nil {
+		return base.NewReadByteError(err)
+	}
+
+	verificationToken := &entity.OneTimeToken{
+		UserID:    user.ID,
+		Purpose:   enum.EmailVerification,
+		TokenHash: tokenHash,
+		Payload:   email,
+		ExpiresAt: now.Add(s.config.TimeToLive),
+	}
+
+	if err := s.tokenStorage.Create(verificationToken, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	template, err := mail.LoadTemplate(mail.EmailVerification)
+	if err != nil {
+		return base.NewSendMessageError(err)
+	}
+
+	message := mail.FormatTemplate(*template, email, fmt.Sprintf(s.config.URL, token), verificationToken.ExpiresAt.Format("02.01.2006 15:04 MST"))
+
+	if err := s.mailService.SendMessage(email, "Подтверждение email", message); err != nil {
+		return base.NewSendMessageError(err)
+	}
+
+	s.logger.Info(fmt.Sprintf("user %s: email verification sent", user.ID))
+
+	return nil
+}
+
+// Resend sends the confirmation link again, to the pending email if there is one.
+func (s *EmailVerificationService) Resend(userID uuid.UUID, ctx context.Context) *base.ServiceError {
+	user, err := s.userStorage.Retrieve(userID, ctx)
+	if err != nil {
+		return base.NewPostgresReadError(err)
+	}
+
+	if user.PendingEmail != "" {
+		return s.SendVerification(user, user.PendingEmail, ctx)
+	}
+
+	if user.IsEmailVerified() {
+		return &base.ServiceError{
+			Err:     errors.New("email is already verified"),
+			Blame:   base.BlameUser,
+			Code:    http.StatusConflict,
+			Message: "email is already verified",
+		}
+	}
+
+	return s.SendVerification(user, user.Email, ctx)
+}
+
+// Confirm marks the email bound to the token as verified and makes it the login of the user.
+func (s *EmailVerificationService) Confirm(token string, ctx context.Context) *base.ServiceError {
+	verificationToken, err := s.tokenStorage.GetByHash(auth.HashOneTimeToken(token), enum.EmailVerification, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return newInvalidVerificationTokenError(err)
+		}
+		return base.NewPostgresReadError(err)
+	}
+
+	now := time.Now()
+
+	if !verificationToken.IsValid(now) {
+		return newInvalidVerificationTokenError(errors.New("verification token is used or expired"))
+	}
+
+	user, err := s.userStorage.Retrieve(verificationToken.UserID, ctx)
+	if err != nil {
+		return base.NewPostgresReadError(err)
+	}
+
+	email := verificationToken.Payload
+	if email != user.Email {
+		if email != user.PendingEmail {
+			return newInvalidVerificationTokenError(errors.New("verification token is issued for another email"))
+		}
+
+		exists, err := s.userStorage.ExistsByEmail(email, ctx)
+		if err != nil {
+			return base.NewPostgresReadError(err)
+		}
+
+		if exists {
+			return newEmailTakenError(email)
+		}
+	}
+
+	used, err := s.tokenStorage.MarkUsed(verificationToken.ID, now, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if !used {
+		return newInvalidVerificationTokenError(errors.New("verification token is already used"))
+	}
+
+	if err := s.userStorage.VerifyEmail(user.ID, email, now, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	before := newUserAuditState(user)
+	user.Email = email
+	user.PendingEmail = ""
+	user.EmailVerifiedAt = &now
+
+	s.auditService.Record(enum.AuditUserEmailVerify, enum.AuditTargetUser, user.ID, before, newUserAuditState(user), ctx)
+
+	s.logger.Info(fmt.Sprintf("user %s: email verified", user.ID))
+
+	return nil
+}
+
+func newInvalidVerificationTokenError(err error) *base.ServiceError {
+	return &base.ServiceError{
+		Err:     err,
+		Blame:   base.BlameUser,
+		Code:    http.StatusBadRequest,
+		Message: "verification token is invalid or expired",
+	}
+}
+
+func newEmailTakenError(email string) *base.ServiceError {
+	return &base.ServiceError{
+		Err:     fmt.Errorf("email %s is already taken", email),
+		Blame:   base.BlameUser,
+		Code:    http.StatusConflict,
+		Message: "email is already taken",
+	}
+}
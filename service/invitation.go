@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/common"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/mail"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+// InvitationService lets member managers onboard new members. The invitee gets an emailed link,
+// sets a password and becomes a member of the company with the role chosen by the inviter.
+// Registered users are invited by member managers and join by accepting the invitation when signed in.
+type InvitationService struct {
+	invitationStorage *dao.InvitationStorage
+	companyStorage    *dao.CompanyStorage
+	userStorage       *dao.UserStorage
+	roleService       *RoleService
+	hasher            *auth.Hasher
+	mailService       *mail.MailService
+	logger            *zap.Logger
+	auditService      *AuditService
+	config            common.OneTimeLinkConfig
+	membershipConfig  common.OneTimeLinkConfig
+}
+
+func NewInvitationService(
+	invitationStorage *dao.InvitationStorage,
+	companyStorage *dao.CompanyStorage,
+	userStorage *dao.UserStorage,
+	roleService *RoleService,
+	hasher *auth.Hasher,
+	mailService *mail.MailService,
+	logger *zap.Logger,
+	auditService *AuditService,
+	config common.OneTimeLinkConfig,
+	membershipConfig common.OneTimeLinkConfig) *InvitationService {
+	return &InvitationService{
+		invitationStorage: invitationStorage,
+		companyStorage:    companyStorage,
+		userStorage:       userStorage,
+		roleService:       roleService,
+		hasher:            hasher,
+		mailService:       mailService,
+		logger:            logger,
+		auditService:      auditService,
+		config:            config,
+		membershipConfig:  membershipConfig,
+	}
+}
+
+// Invite sends an invitation into the company to the email. Earlier pending invitations of the email are replaced.
+func (s *InvitationService) Invite(inviterID uuid.UUID, companyID uuid.UUID, request *model.CreateInvitationRequest, ctx context.Context) (*uuid.UUID, *base.ServiceError) {
+	if !request.Role.IsInvitable() {
+		return nil, &base.ServiceError{
+			Err:     fmt.Errorf("role %q can't be invited", request.Role),
+			Blame:   base.BlameUser,
+			Code:    http.StatusBadRequest,
+			Message: "unknown company role",
+		}
+	}
+
+	company, serviceErr := s.getCompany(companyID, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	exists, err := s.userStorage.ExistsByEmail(request.Email, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	if exists {
+		return nil, &base.ServiceError{
+			Err:     fmt.Errorf("user %s is already registered", request.Email),
+			Blame:   base.BlameUser,
+			Code:    http.StatusConflict,
+			Message: "user with this email is already registered",
+		}
+	}
+
+	if err := s.invitationStorage.DeletePendingByEmail(companyID, request.Email, ctx); err != nil {
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	invitation := &entity.Invitation{
+		CompanyID: companyID,
+		Email:     request.Email,
+		Role:      request.Role,
+		InvitedBy: inviterID,
+	}
+
+	if serviceErr := s.send(invitation, company, mail.Invitation, s.config, ctx); serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	return &invitation.ID, nil
+}
+
+// InviteUser sends an invitation into the company to a registered user, who joins it by Join.
+// A user is never made a member without consent. Earlier pending invitations of the user are replaced.
+func (s *InvitationService) InviteUser(inviterID uuid.UUID, companyID uuid.UUID, request *model.AddMemberRequest, ctx context.Context) (*uuid.UUID, *base.ServiceError) {
+	if !request.Role.IsInvitable() {
+		return nil, &base.ServiceError{
+			Err:     fmt.Errorf("role %q can't be given to a member", request.Role),
+			Blame:   base.BlameUser,
+			Code:    http.StatusBadRequest,
+			Message: "unknown company role",
+		}
+	}
+
+	company, serviceErr := s.getCompany(companyID, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	user, err := s.userStorage.Retrieve(request.UserID, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, base.NewNotFoundError(fmt.Errorf("user %s not found", request.UserID))
+		}
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	if user.CompanyID != nil {
+		return nil, newAlreadyMemberError(user.ID)
+	}
+
+	if err := s.invitationStorage.DeletePendingByEmail(companyID, user.Email, ctx); err != nil {
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	invitation := &entity.Invitation{
+		CompanyID: companyID,
+		Email:     user.Email,
+		UserID:    &user.ID,
+		Role:      request.Role,
+		InvitedBy: inviterID,
+	}
+
+	if serviceErr := s.send(invitation, company, mail.Membership, s.membershipConfig, ctx); serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	return &invitation.ID, nil
+}
+
+// send stores the invitation with a new token and emails the link to the invitee.
+func (s *InvitationService) send(
+	invitation *entity.Invitation,
+	company *entity.Company,
+	templateType mail.TypeTemplate,
+	config common.OneTimeLinkConfig,
+	ctx context.Context) *base.ServiceError {
+	token, tokenHash, err := auth.NewOneTimeToken()
+	if err != nil {
+		return base.NewReadByteError(err)
+	}
+
+	invitation.TokenHash = tokenHash
+	invitation.ExpiresAt = time.Now().Add(config.TimeToLive)
+
+	if err := s.invitationStorage.Create(invitation, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditInvitationCreate, enum.AuditTargetInvitation, invitation.ID, nil, invitation, ctx)
+
+	template, err := mail.LoadTemplate(templateType)
+	if err != nil {
+		return base.NewSendMessageError(err)
+	}
+
+	message := mail.FormatTemplate(*template,
+		company.Name,
+		invitation.Role,
+		fmt.Sprintf(config.URL, token),
+		invitation.ExpiresAt.Format("02.01.2006 15:04 MST"))
+
+	if err := s.mailService.SendMessage(invitation.Email, "Приглашение в компанию "+company.Name, message); err != nil {
+		return base.NewSendMessageError(err)
+	}
+
+	s.logger.Info(fmt.Sprintf("company %s: %s invited as %s", company.ID, invitation.Email, invitation.Role))
+
+	return nil
+}
+
+// GetInvitations returns pending invitations of the company.
+func (s *InvitationService) GetInvitations(companyID uuid.UUID, ctx context.Context) ([]model.InvitationObject, *base.ServiceError) {
+	if _, serviceErr := s.getCompany(companyID, ctx); serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	invitations, err := s.invitationStorage.GetPendingByCompanyID(companyID, time.Now(), ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	result := make([]model.InvitationObject, 0, len(invitations))
+	for _, invitation := range invitations {
+		result = append(result, model.InvitationObject{
+			ID:        invitation.ID,
+			CreatedAt: invitation.CreatedAt,
+			Email:     invitation.Email,
+			Role:      invitation.Role,
+			InvitedBy: invitation.InvitedBy,
+			ExpiresAt: invitation.ExpiresAt,
+		})
+	}
+
+	return result, nil
+}
+
+// RevokeInvitation deletes a pending invitation, its link stops working.
+func (s *InvitationService) RevokeInvitation(companyID uuid.UUID, invitationID uuid.UUID, ctx context.Context) *base.ServiceError {
+	if _, serviceErr := s.getCompany(companyID, ctx); serviceErr != nil {
+		return serviceErr
+	}
+
+	deleted, err := s.invitationStorage.Delete(invitationID, companyID, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if !deleted {
+		return base.NewNotFoundError(fmt.Errorf("invitation %s not found", invitationID))
+	}
+
+	s.auditService.Record(enum.AuditInvitationRevoke, enum.AuditTargetInvitation, invitationID, map[string]any{"company_id": companyID}, nil, ctx)
+
+	return nil
+}
+
+// Accept creates the account of the invitee as a member of the company.
+// The email is considered verified, because the invitation link was sent to it.
+func (s *InvitationService) Accept(request *model.AcceptInvitationRequest, ctx context.Context) (*uuid.UUID, *base.ServiceError) {
+	if request.Password == "" {
+		return nil, &base.ServiceError{
+			Err:     errors.New("empty password"),
+			Blame:   base.BlameUser,
+			Code:    http.StatusBadRequest,
+			Message: "password must not be empty",
+		}
+	}
+
+	invitation, err := s.invitationStorage.GetByHash(auth.HashOneTimeToken(request.Token), ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newInvalidInvitationError(err)
+		}
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	now := time.Now()
+
+	if !invitation.IsValid(now) {
+		return nil, newInvalidInvitationError(errors.New("invitation is accepted or expired"))
+	}
+
+	// invitations of registered users are accepted by Join
+	if invitation.UserID != nil {
+		return nil, newInvalidInvitationError(fmt.Errorf("invitation %s is addressed to user %s", invitation.ID, *invitation.UserID))
+	}
+
+	exists, err := s.userStorage.ExistsByEmail(invitation.Email, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	if exists {
+		return nil, newEmailTakenError(invitation.Email)
+	}
+
+	hashPassword, err := s.hasher.Hash(request.Password)
+	if err != nil {
+		return nil, base.NewReadByteError(err)
+	}
+
+	roleID, serviceErr := s.roleService.GetRoleID(invitation.Role, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	user := &entity.User{
+		Name:            request.Name,
+		Email:           invitation.Email,
+		EmailVerifiedAt: &now,
+		Password:        hashPassword,
+		CompanyID:       &invitation.CompanyID,
+	}
+
+	userRole := &entity.UserRole{
+		RoleID:    roleID,
+		CompanyID: &invitation.CompanyID,
+		GrantedBy: &invitation.InvitedBy,
+	}
+
+	accepted, err := s.invitationStorage.Accept(invitation.ID, now, user, userRole, ctx)
+	if err != nil {
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	if !accepted {
+		return nil, newInvalidInvitationError(errors.New("invitation is already accepted"))
+	}
+
+	s.auditService.Record(enum.AuditInvitationAccept, enum.AuditTargetInvitation, invitation.ID,
+		map[string]any{"accepted_at": nil}, map[string]any{"accepted_at": now, "user_id": user.ID}, ctx)
+	s.auditService.Record(enum.AuditUserRegister, enum.AuditTargetUser, user.ID, nil, newUserAuditState(user), ctx)
+	s.auditService.Record(enum.AuditRoleGrant, enum.AuditTargetRoleGrant, userRole.ID, nil, roleGrantAuditState(userRole, invitation.Role), ctx)
+
+	s.logger.Info(fmt.Sprintf("company %s: %s joined as %s", invitation.CompanyID, user.Email, invitation.Role))
+
+	return &user.ID, nil
+}
+
+// Join makes the signed in user a member of the company the user was invited to by InviteUser.
+func (s *InvitationService) Join(userID uuid.UUID, request *model.JoinCompanyRequest, ctx context.Context) *base.ServiceError {
+	invitation, err := s.invitationStorage.GetByHash(auth.HashOneTimeToken(request.Token), ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return newInvalidInvitationError(err)
+		}
+		return base.NewPostgresReadError(err)
+	}
+
+	now := time.Now()
+
+	// the token alone is not enough, the invitation must be addressed to the user
+	if invitation.UserID == nil || *invitation.UserID != userID || !invitation.IsValid(now) {
+		return newInvalidInvitationError(fmt.Errorf("invitation %s can't be accepted by user %s", invitation.ID, userID))
+	}
+
+	roleID, serviceErr := s.roleService.GetRoleID(invitation.Role, ctx)
+	if serviceErr != nil {
+		return serviceErr
+	}
+
+	userRole := &entity.UserRole{
+		UserID:    userID,
+		RoleID:    roleID,
+		CompanyID: &invitation.CompanyID,
+		GrantedBy: &invitation.InvitedBy,
+	}
+
+	joined, err := s.invitationStorage.Join(invitation.ID, now, userRole, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if !joined {
+		return newAlreadyMemberError(userID)
+	}
+
+	s.auditService.Record(enum.AuditInvitationAccept, enum.AuditTargetInvitation, invitation.ID,
+		map[string]any{"accepted_at": nil}, map[string]any{"accepted_at": now, "user_id": userID}, ctx)
+	s.auditService.Record(enum.AuditMemberAdd, enum.AuditTargetCompany, invitation.CompanyID,
+		nil, map[string]any{"user_id": userID, "role": invitation.Role}, ctx)
+	s.auditService.Record(enum.AuditRoleGrant, enum.AuditTargetRoleGrant, userRole.ID, nil, roleGrantAuditState(userRole, invitation.Role), ctx)
+
+	s.logger.Info(fmt.Sprintf("company %s: user %s joined as %s", invitation.CompanyID, userID, invitation.Role))
+
+	return nil
+}
+
+// getCompany returns the company, the access to it is checked by the permission middleware.
+func (s *InvitationService) getCompany(companyID uuid.UUID, ctx context.Context) (*entity.Company, *base.ServiceError) {
+	company, err := s.companyStorage.Retrieve(companyID, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, base.NewNotFoundError(fmt.Errorf("company %s not found", companyID))
+		}
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	return company, nil
+}
+
+func newInvalidInvitationError(err error) *base.ServiceError {
+	return &base.ServiceError{
+		Err:     err,
+		Blame:   base.BlameUser,
+		Code:    http.StatusBadRequest,
+		Message: "invitation is invalid or expired",
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/common"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// LoginThrottleService protects login against password guessing. Failed attempts are counted
+// per account and per client IP. An account has to wait progressively longer between failed attempts,
+// and both accounts and IPs are locked for a while after too many failures.
+type LoginThrottleService struct {
+	storage *dao.LoginThrottleStorage
+	logger  *zap.Logger
+	config  common.LoginThrottleConfig
+}
+
+func NewLoginThrottleService(storage *dao.LoginThrottleStorage, logger *zap.Logger, config common.LoginThrottleConfig) *LoginThrottleService {
+	return &LoginThrottleService{
+		storage: storage,
+		logger:  logger,
+		config:  config,
+	}
+}
+
+// Check rejects the login attempt if the account or the IP is locked, or the account has to wait after a failure.
+func (s *LoginThrottleService) Check(email string, ip string, ctx context.Context) *base.ServiceError {
+	now := time.Now()
+
+	account, err := s.storage.Get(enum.ThrottleAccount, normalizeEmail(email), ctx)
+	if err != nil {
+		return base.NewPostgresReadError(err)
+	}
+
+	if account != nil {
+		if account.IsLocked(now) {
+			return base.NewAccountLockedError(fmt.Errorf("account %s is locked", email), account.LockedUntil.Sub(now))
+		}
+
+		if wait := s.delay(account, now); wait > 0 {
+			return base.NewTooManyLoginAttemptsError(fmt.Errorf("account %s has to wait", email), wait)
+		}
+	}
+
+	if ip == "" {
+		return nil
+	}
+
+	client, err := s.storage.Get(enum.ThrottleIP, ip, ctx)
+	if err != nil {
+		return base.NewPostgresReadError(err)
+	}
+
+	if client != nil && client.IsLocked(now) {
+		return base.NewTooManyLoginAttemptsError(fmt.Errorf("ip %s is locked", ip), client.LockedUntil.Sub(now))
+	}
+
+	return nil
+}
+
+// RegisterFailure counts a failed attempt and locks the account or the IP when the limit is reached.
+func (s *LoginThrottleService) RegisterFailure(email string, ip string, ctx context.Context) *base.ServiceError {
+	now := time.Now()
+	windowStart := now.Add(-s.config.FailureWindow)
+
+	if err := s.storage.DeleteStale(now, windowStart, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	account, err := s.storage.RegisterFailure(enum.ThrottleAccount, normalizeEmail(email), now, windowStart, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if account.Failures >= s.config.MaxAccountFailures && !account.IsLocked(now) {
+		if err := s.lock(account, ip, now, ctx); err != nil {
+			return base.NewPostgresWriteError(err)
+		}
+	}
+
+	if ip == "" {
+		return nil
+	}
+
+	client, err := s.storage.RegisterFailure(enum.ThrottleIP, ip, now, windowStart, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if client.Failures >= s.config.MaxIPFailures && !client.IsLocked(now) {
+		if err := s.lock(client, ip, now, ctx); err != nil {
+			return base.NewPostgresWriteError(err)
+		}
+	}
+
+	return nil
+}
+
+// RegisterSuccess forgets failed attempts of the account. Failures of the IP are kept,
+// otherwise a single known password would let an attacker keep guessing others from the same IP.
+func (s *LoginThrottleService) RegisterSuccess(email string, ctx context.Context) *base.ServiceError {
+	if err := s.storage.Delete(enum.ThrottleAccount, normalizeEmail(email), ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	return nil
+}
+
+func (s *LoginThrottleService) GetLockoutEvents(options *dataProcessing.Options, ctx context.Context) ([]model.LockoutEventObject, int64, *base.ServiceError) {
+	events, total, err := s.storage.GetLockoutEvents(options, ctx)
+	if err != nil {
+		return nil, total, base.NewPostgresReadError(err)
+	}
+
+	result := make([]model.LockoutEventObject, 0, len(events))
+	for _, event := range events {
+		result = append(result, model.LockoutEventObject{
+			ID:          event.ID,
+			CreatedAt:   event.CreatedAt,
+			Scope:       event.Scope,
+			Subject:     event.Subject,
+			IPAddress:   event.IPAddress,
+			Failures:    event.Failures,
+			LockedUntil: event.LockedUntil,
+		})
+	}
+
+	return result, total, nil
+}
+
+func (s *LoginThrottleService) lock(throttle *entity.LoginThrottle, ip string, now time.Time, ctx context.Context) error {
+	lockedUntil := now.Add(s.config.LockoutDuration)
+
+	if err := s.storage.Lock(throttle.Scope, throttle.Subject, lockedUntil, ctx); err != nil {
+		return err
+	}
+
+	s.logger.Warn(fmt.Sprintf("login lockout: %s %s locked until %s after %d failed attempts, last from %s",
+		throttle.Scope, throttle.Subject, lockedUntil.Format(time.RFC3339), throttle.Failures, ip))
+
+	return s.storage.CreateLockoutEvent(&entity.LockoutEvent{
+		Scope:       throttle.Scope,
+		Subject:     throttle.Subject,
+		IPAddress:   ip,
+		Failures:    throttle.Failures,
+		LockedUntil: lockedUntil,
+	}, ctx)
+}
+
+// delay returns how long the account still has to wait after its last failure.
+// The delay doubles with every failure within the window.
+func (s *LoginThrottleService) delay(throttle *entity.LoginThrottle, now time.Time) time.Duration {
+	if throttle.Failures == 0 || throttle.LastFailureAt.Before(now.Add(-s.config.FailureWindow)) {
+		return 0
+	}
+
+	delay := s.config.MaxDelay
+	if shift := throttle.Failures - 1; shift < 31 {
+		if backoff := s.config.BaseDelay << shift; backoff > 0 && backoff < delay {
+			delay = backoff
+		}
+	}
+
+	return throttle.LastFailureAt.Add(delay).Sub(now)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
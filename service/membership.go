@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+// MembershipService manages members of companies. A company always has an owner: the owner can't leave
+// or be removed, the ownership has to be transferred to another member first.
+type MembershipService struct {
+	companyStorage *dao.CompanyStorage
+	userStorage    *dao.UserStorage
+	roleService    *RoleService
+	logger         *zap.Logger
+	auditService   *AuditService
+}
+
+func NewMembershipService(
+	companyStorage *dao.CompanyStorage,
+	userStorage *dao.UserStorage,
+	roleService *RoleService,
+	logger *zap.Logger,
+	auditService *AuditService) *MembershipService {
+	return &MembershipService{
+		companyStorage: companyStorage,
+		userStorage:    userStorage,
+		roleService:    roleService,
+		logger:         logger,
+		auditService:   auditService,
+	}
+}
+
+// GetMembers returns the owner and members of the company. They are visible to members and to member managers.
+func (s *MembershipService) GetMembers(userID uuid.UUID, companyID uuid.UUID, ctx context.Context) ([]model.MemberObject, *base.ServiceError) {
+	company, serviceErr := s.getCompany(companyID, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	isMember, err := s.companyStorage.IsMember(companyID, userID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	if !isMember {
+		allowed, err := s.roleService.HasPermission(userID, &companyID, enum.PermissionManageMembers, ctx)
+		if err != nil {
+			return nil, base.NewPostgresReadError(err)
+		}
+
+		if !allowed {
+			return nil, &base.ServiceError{
+				Err:     fmt.Errorf("user %s is not a member of company %s", userID, companyID),
+				Blame:   base.BlameUser,
+				Code:    http.StatusForbidden,
+				Message: "no access",
+			}
+		}
+	}
+
+	users, err := s.companyStorage.GetMembers(companyID, company.Owner, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	result := make([]model.MemberObject, 0, len(users))
+	for _, user := range users {
+		roles := make([]enum.Role, 0, len(user.Roles))
+		for _, userRole := range user.Roles {
+			roles = append(roles, userRole.Role.Name)
+		}
+
+		result = append(result, model.MemberObject{
+			ID:      user.ID,
+			Name:    user.Name,
+			Email:   user.Email,
+			IsOwner: user.ID == company.Owner,
+			Roles:   roles,
+		})
+	}
+
+	return result, nil
+}
+
+// RemoveMember takes the member out of the company and revokes the roles granted within it.
+func (s *MembershipService) RemoveMember(companyID uuid.UUID, userID uuid.UUID, ctx context.Context) *base.ServiceError {
+	if serviceErr := s.removeMember(companyID, userID, ctx); serviceErr != nil {
+		return serviceErr
+	}
+
+	s.auditService.Record(enum.AuditMemberRemove, enum.AuditTargetCompany, companyID, map[string]any{"user_id": userID}, nil, ctx)
+
+	s.logger.Info(fmt.Sprintf("company %s: user %s removed", companyID, userID))
+
+	return nil
+}
+
+// Leave takes the user out of the company, as RemoveMember does.
+func (s *MembershipService) Leave(userID uuid.UUID, companyID uuid.UUID, ctx context.Context) *base.ServiceError {
+	if serviceErr := s.removeMember(companyID, userID, ctx); serviceErr != nil {
+		return serviceErr
+	}
+
+	s.auditService.Record(enum.AuditMemberLeave, enum.AuditTargetCompany, companyID, map[string]any{"user_id": userID}, nil, ctx)
+
+	s.logger.Info(fmt.Sprintf("company %s: user %s left", companyID, userID))
+
+	return nil
+}
+
+// TransferOwnership makes a member the owner of the company. The former owner stays a member
+// with the requested role or leaves the company if no role is requested.
+func (s *MembershipService) TransferOwnership(companyID uuid.UUID, request *model.TransferOwnershipRequest, ctx context.Context) *base.ServiceError {
+	if request.FormerOwnerRole != "" && !request.FormerOwnerRole.IsInvitable() {
+		return &base.ServiceError{
+			Err:     fmt.Errorf("role %q can't be given to the former owner", request.FormerOwnerRole),
+			Blame:   base.BlameUser,
+			Code:    http.StatusBadRequest,
+			Message: "unknown company role",
+		}
+	}
+
+	company, serviceErr := s.getCompany(companyID, ctx)
+	if serviceErr != nil {
+		return serviceErr
+	}
+
+	if company.Owner == request.UserID {
+		return &base.ServiceError{
+			Err:     fmt.Errorf("user %s already owns company %s", request.UserID, companyID),
+			Blame:   base.BlameUser,
+			Code:    http.StatusConflict,
+			Message: "user is the owner already",
+		}
+	}
+
+	newOwner, serviceErr := s.getUser(request.UserID, ctx)
+	if serviceErr != nil {
+		return serviceErr
+	}
+
+	if newOwner.CompanyID == nil || *newOwner.CompanyID != companyID {
+		return &base.ServiceError{
+			Err:     fmt.Errorf("user %s is not a member of company %s", request.UserID, companyID),
+			Blame:   base.BlameUser,
+			Code:    http.StatusConflict,
+			Message: "ownership can be transferred to a member of the company only",
+		}
+	}
+
+	ownerRoleID, serviceErr := s.roleService.GetRoleID(enum.RoleCompanyOwner, ctx)
+	if serviceErr != nil {
+		return serviceErr
+	}
+
+	var formerOwnerRoleID *uuid.UUID
+	if request.FormerOwnerRole != "" {
+		roleID, serviceErr := s.roleService.GetRoleID(request.FormerOwnerRole, ctx)
+		if serviceErr != nil {
+			return serviceErr
+		}
+
+		formerOwnerRoleID = &roleID
+	}
+
+	transferred, err := s.companyStorage.TransferOwnership(companyID, company.Owner, request.UserID, ownerRoleID, formerOwnerRoleID, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if !transferred {
+		return &base.ServiceError{
+			Err:     fmt.Errorf("ownership of company %s changed meanwhile or owner %s is a member of another company", companyID, company.Owner),
+			Blame:   base.BlameUser,
+			Code:    http.StatusConflict,
+			Message: "ownership can't be transferred",
+		}
+	}
+
+	s.auditService.Record(enum.AuditOwnershipTransfer, enum.AuditTargetCompany, companyID,
+		map[string]any{"owner": company.Owner},
+		map[string]any{"owner": request.UserID, "former_owner_role": request.FormerOwnerRole}, ctx)
+
+	s.logger.Info(fmt.Sprintf("company %s: ownership transferred from %s to %s", companyID, company.Owner, request.UserID))
+
+	return nil
+}
+
+// removeMember takes the member out of the company. Members may leave archived companies too.
+func (s *MembershipService) removeMember(companyID uuid.UUID, userID uuid.UUID, ctx context.Context) *base.ServiceError {
+	company, err := s.companyStorage.RetrieveWithArchived(companyID, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return base.NewNotFoundError(fmt.Errorf("company %s not found", companyID))
+		}
+		return base.NewPostgresReadError(err)
+	}
+
+	if company.Owner == userID {
+		return newOwnerRequiredError(fmt.Errorf("user %s owns company %s", userID, companyID))
+	}
+
+	removed, err := s.companyStorage.RemoveMember(companyID, userID, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if !removed {
+		return base.NewNotFoundError(fmt.Errorf("user %s is not a member of company %s", userID, companyID))
+	}
+
+	return nil
+}
+
+func (s *MembershipService) getCompany(companyID uuid.UUID, ctx context.Context) (*entity.Company, *base.ServiceError) {
+	company, err := s.companyStorage.Retrieve(companyID, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, base.NewNotFoundError(fmt.Errorf("company %s not found", companyID))
+		}
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	return company, nil
+}
+
+func (s *MembershipService) getUser(userID uuid.UUID, ctx context.Context) (*entity.User, *base.ServiceError) {
+	user, err := s.userStorage.Retrieve(userID, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, base.NewNotFoundError(fmt.Errorf("user %s not found", userID))
+		}
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	return user, nil
+}
+
+// newOwnerRequiredError is returned for changes which would leave a company without an owner.
+func newOwnerRequiredError(err error) *base.ServiceError {
+	return &base.ServiceError{
+		Err:     err,
+		Blame:   base.BlameUser,
+		Code:    http.StatusConflict,
+		Message: "the company owner must transfer the ownership first",
+	}
+}
+
+func newAlreadyMemberError(userID uuid.UUID) *base.ServiceError {
+	return &base.ServiceError{
+		Err:     fmt.Errorf("user %s is a member of a company already", userID),
+		Blame:   base.BlameUser,
+		Code:    http.StatusConflict,
+		Message: "user is a member of a company already",
+	}
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/common"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/helpers"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const oidcHttpTimeout = 10 * time.Second
+
+// OIDCService logs users in through OpenID Connect providers with the authorization code flow and PKCE.
+// Unknown users are linked to the account with the same verified email or provisioned on the first login.
+type OIDCService struct {
+	storage      *dao.OIDCStorage
+	userStorage  *dao.UserStorage
+	authService  *AuthService
+	logger       *zap.Logger
+	auditService *AuditService
+	config       common.OIDCConfig
+	httpClient   *http.Client
+
+	mu        sync.Mutex
+	providers map[string]*oidcProvider
+}
+
+type oidcProvider struct {
+	config   common.OIDCProviderConfig
+	oauth2   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// oidcClaims are the claims of an ID token used to find or provision the user.
+type oidcClaims struct {
+	Email         string `json:"email"`
+	EmailVerified *bool  `json:"email_verified"`
+	Name          string `json:"name"`
+	Nonce         string `json:"nonce"`
+}
+
+func NewOIDCService(
+	storage *dao.OIDCStorage,
+	userStorage *dao.UserStorage,
+	authService *AuthService,
+	logger *zap.Logger,
+	auditService *AuditService,
+	config common.OIDCConfig) *OIDCService {
+	return &OIDCService{
+		storage:      storage,
+		userStorage:  userStorage,
+		authService:  authService,
+		logger:       logger,
+		auditService: auditService,
+		config:       config,
+		httpClient:   &http.Client{Timeout: oidcHttpTimeout},
+		providers:    map[string]*oidcProvider{},
+	}
+}
+
+// GetProviders returns names of the configured providers.
+func (s *OIDCService) GetProviders() []string {
+	names := make([]string, 0, len(s.config.Providers))
+	for name := range s.config.Providers {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// Begin starts login at the provider and returns the URL the user has to be redirected to.
+func (s *OIDCService) Begin(providerName string, ctx context.Context) (string, *base.ServiceError) {
+	provider, serviceErr := s.getProvider(providerName)
+	if serviceErr != nil {
+		return "", serviceErr
+	}
+
+	now := time.Now()
+
+	if err := s.storage.DeleteExpiredAuthRequests(now, ctx); err != nil {
+		return "", base.NewPostgresWriteError(err)
+	}
+
+	state, stateHash, err := auth.NewOneTimeToken()
+	if err != nil {
+		return "", base.NewReadByteError(err)
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return "", base.NewReadByteError(err)
+	}
+
+	request := &entity.OIDCAuthRequest{
+		Provider:     providerName,
+		StateHash:    stateHash,
+		Nonce:        nonce,
+		CodeVerifier: oauth2.GenerateVerifier(),
+		ExpiresAt:    now.Add(s.config.StateTimeToLive),
+	}
+
+	if err := s.storage.CreateAuthRequest(request, ctx); err != nil {
+		return "", base.NewPostgresWriteError(err)
+	}
+
+	return provider.oauth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(request.CodeVerifier)), nil
+}
+
+// Callback exchanges the authorization code returned by the provider and logs the user in.
+func (s *OIDCService) Callback(providerName string, request *model.OIDCCallbackRequest, client helpers.ClientInfo, ctx context.Context) (*model.LoginResponse, *base.ServiceError) {
+	provider, serviceErr := s.getProvider(providerName)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	authRequest, err := s.storage.TakeAuthRequest(auth.HashOneTimeToken(request.State), ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newOIDCLoginError(errors.New("unknown state"))
+		}
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	if authRequest.Provider != providerName || time.Now().After(authRequest.ExpiresAt) {
+		return nil, newOIDCLoginError(errors.New("state is expired or issued for another provider"))
+	}
+
+	exchangeCtx := oidc.ClientContext(ctx, s.httpClient)
+
+	token, err := provider.oauth2.Exchange(exchangeCtx, request.Code, oauth2.VerifierOption(authRequest.CodeVerifier))
+	if err != nil {
+		return nil, newOIDCLoginError(fmt.Errorf("failed to exchange code: %w", err))
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, newOIDCLoginError(errors.New("no id_token in token response"))
+	}
+
+	idToken, err := provider.verifier.Verify(exchangeCtx, rawIDToken)
+	if err != nil {
+		return nil, newOIDCLoginError(fmt.Errorf("invalid id_token: %w", err))
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, newOIDCLoginError(fmt.Errorf("invalid id_token claims: %w", err))
+	}
+
+	if claims.Nonce != authRequest.Nonce {
+		return nil, newOIDCLoginError(errors.New("id_token nonce mismatch"))
+	}
+
+	user, serviceErr := s.resolveUser(providerName, provider.config, idToken.Subject, &claims, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	s.logger.Info(fmt.Sprintf("%s: signed in with %s", user.Email, providerName))
+
+	return s.authService.LoginExternal(user, client, ctx)
+}
+
+// resolveUser finds the user linked to the provider account. An unlinked account is linked to the user
+// with the same email, or a new user is created if the provider allows auto provisioning.
+func (s *OIDCService) resolveUser(providerName string, config common.OIDCProviderConfig, subject string, claims *oidcClaims, ctx context.Context) (*entity.User, *base.ServiceError) {
+	now := time.Now()
+
+	identity, err := s.storage.GetIdentity(providerName, subject, ctx)
+	if err == nil {
+		if err := s.storage.TouchIdentity(identity.ID, claims.Email, now, ctx); err != nil {
+			return nil, base.NewPostgresWriteError(err)
+		}
+
+		user, err := s.userStorage.Retrieve(identity.UserID, ctx)
+		if err != nil {
+			return nil, base.NewPostgresReadError(err)
+		}
+		return user, nil
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	// linking by email is only safe if the provider vouches for the address
+	emailVerified := claims.EmailVerified != nil && *claims.EmailVerified || claims.EmailVerified == nil && config.TrustEmail
+	if claims.Email == "" || !emailVerified {
+		return nil, &base.ServiceError{
+			Err:     fmt.Errorf("%s account %s has no verified email", providerName, subject),
+			Blame:   base.BlameUser,
+			Code:    http.StatusForbidden,
+			Message: "identity provider didn't confirm the email",
+		}
+	}
+
+	user, err := s.userStorage.GetUser(claims.Email, ctx)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, base.NewPostgresReadError(err)
+		}
+
+		// a deleted user keeps the email until purged, so it can't be provisioned again meanwhile
+		exists, err := s.userStorage.ExistsByEmail(claims.Email, ctx)
+		if err != nil {
+			return nil, base.NewPostgresReadError(err)
+		}
+
+		if exists {
+			return nil, newEmailTakenError(claims.Email)
+		}
+
+		var serviceErr *base.ServiceError
+		if user, serviceErr = s.provisionUser(providerName, config, claims, now, ctx); serviceErr != nil {
+			return nil, serviceErr
+		}
+	}
+
+	identity = &entity.ExternalIdentity{
+		UserID:      user.ID,
+		Provider:    providerName,
+		Subject:     subject,
+		Email:       claims.Email,
+		LastLoginAt: now,
+	}
+
+	if err := s.storage.CreateIdentity(identity, ctx); err != nil {
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditIdentityLink, enum.AuditTargetUser, user.ID,
+		nil, map[string]any{"provider": identity.Provider, "subject": identity.Subject, "email": identity.Email}, ctx)
+
+	s.logger.Info(fmt.Sprintf("%s: linked to %s account %s", user.Email, providerName, subject))
+
+	return user, nil
+}
+
+func (s *OIDCService) provisionUser(providerName string, config common.OIDCProviderConfig, claims *oidcClaims, now time.Time, ctx context.Context) (*entity.User, *base.ServiceError) {
+	if !config.AutoProvision || !isAllowedDomain(claims.Email, config.AllowedDomains) {
+		return nil, &base.ServiceError{
+			Err:     fmt.Errorf("%s is not registered and can't be provisioned by %s", claims.Email, providerName),
+			Blame:   base.BlameUser,
+			Code:    http.StatusForbidden,
+			Message: "user is not registered",
+		}
+	}
+
+	name := claims.Name
+	if name == "" {
+		name = claims.Email
+	}
+
+	// the user has no password and can sign in only through the provider until one is set by password reset
+	user := &entity.User{
+		Name:            name,
+		Email:           claims.Email,
+		EmailVerifiedAt: &now,
+	}
+
+	if err := s.userStorage.Create(user, ctx); err != nil {
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditUserProvision, enum.AuditTargetUser, user.ID, nil, newUserAuditState(user), ctx)
+
+	s.logger.Info(fmt.Sprintf("%s: provisioned by %s", user.Email, providerName))
+
+	return user, nil
+}
+
+// getProvider returns the provider, discovering its endpoints and keys on first use.
+// Discovery is lazy, so an unavailable provider doesn't prevent the service from starting.
+func (s *OIDCService) getProvider(name string) (*oidcProvider, *base.ServiceError) {
+	config, ok := s.config.Providers[name]
+	if !ok {
+		return nil, base.NewNotFoundError(fmt.Errorf("oidc provider %s is not configured", name))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if provider, ok := s.providers[name]; ok {
+		return provider, nil
+	}
+
+	// the context is kept by the provider to fetch rotated keys later, so it must outlive the request
+	discoveryCtx := oidc.ClientContext(context.Background(), s.httpClient)
+
+	discovered, err := oidc.NewProvider(discoveryCtx, config.Issuer)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to discover oidc provider %s: %v", name, err))
+		return nil, base.NewHttpServerConnectError(err)
+	}
+
+	provider := &oidcProvider{
+		config: config,
+		oauth2: oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			Endpoint:     discovered.Endpoint(),
+			RedirectURL:  config.RedirectURL,
+			Scopes:       append([]string{oidc.ScopeOpenID, "email", "profile"}, config.Scopes...),
+		},
+		verifier: discovered.Verifier(&oidc.Config{ClientID: config.ClientID}),
+	}
+
+	s.providers[name] = provider
+
+	return provider, nil
+}
+
+func isAllowedDomain(email string, allowedDomains []string) bool {
+	if len(allowedDomains) == 0 {
+		return true
+	}
+
+	_, domain, found := strings.Cut(email, "@")
+	if !found {
+		return false
+	}
+
+	for _, allowed := range allowedDomains {
+		if strings.EqualFold(domain, allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newNonce() (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(nonce), nil
+}
+
+func newOIDCLoginError(err error) *base.ServiceError {
+	return &base.ServiceError{
+		Err:     err,
+		Blame:   base.BlameUser,
+		Code:    http.StatusUnauthorized,
+		Message: "single sign-on failed",
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/common"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/mail"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+// passwordResetSendTimeout bounds issuing and mailing a reset link in the background.
+const passwordResetSendTimeout = time.Minute
+
+type PasswordResetService struct {
+	userStorage  *dao.UserStorage
+	tokenStorage *dao.OneTimeTokenStorage
+	mailService  *mail.MailService
+	hasher       *auth.Hasher
+	authService  *AuthService
+	logger       *zap.Logger
+	auditService *AuditService
+	config       common.OneTimeLinkConfig
+}
+
+func NewPasswordResetService(
+	userStorage *dao.UserStorage,
+	tokenStorage *dao.OneTimeTokenStorage,
+	mailService *mail.MailService,
+	hasher *auth.Hasher,
+	authService *AuthService,
+	logger *zap.Logger,
+	auditService *AuditService,
+	config common.OneTimeLinkConfig) *PasswordResetService {
+	return &PasswordResetService{
+		userStorage:  userStorage,
+		tokenStorage: tokenStorage,
+		mailService:  mailService,
+		hasher:       hasher,
+		authService:  authService,
+		logger:       logger,
+		auditService: auditService,
+		config:       config,
+	}
+}
+
+// RequestReset sends a password reset link to the email. Previously issued links of the user stop working.
+// Unknown email is not reported, so the endpoint can't be used to find out who is registered.
+// The link is issued and sent in the background, so the response takes the same time either way.
+func (s *PasswordResetService) RequestReset(request *model.ForgotPasswordRequest, ctx context.Context) *base.ServiceError {
+	user, err := s.userStorage.GetUser(request.Email, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Info(request.Email + ": password reset requested for unknown email")
+			return nil
+		}
+		return base.NewPostgresReadError(err)
+	}
+
+	// the request context ends with the response, the link outlives it
+	go func() {
+		sendCtx, cancel := context.WithTimeout(context.Background(), passwordResetSendTimeout)
+		defer cancel()
+
+		if serviceErr := s.sendReset(user, sendCtx); serviceErr != nil {
+			s.logger.Error(fmt.Sprintf("%s: failed to send password reset link: %v", user.Email, serviceErr.Err))
+		}
+	}()
+
+	return nil
+}
+
+// sendReset issues a reset link to the user and emails it. Requests within the cooldown after
+// the last link are ignored, so the mailbox can't be flooded.
+func (s *PasswordResetService) sendReset(user *entity.User, ctx context.Context) *base.ServiceError {
+	now := time.Now()
+
+	if err := s.tokenStorage.DeleteExpired(now, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if s.config.Cooldown > 0 {
+		recent, err := s.tokenStorage.ExistsSince(user.ID, enum.PasswordReset, now.Add(-s.config.Cooldown), ctx)
+		if err != nil {
+			return base.NewPostgresReadError(err)
+		}
+
+		if recent {
+			s.logger.Info(user.Email + ": password reset requested again within the cooldown")
+			return nil
+		}
+	}
+
+	if err := s.tokenStorage.DeleteByUserIDAndPurpose(user.ID, enum.PasswordReset, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	token, tokenHash, err := auth.NewOneTimeToken()
+	if err != nil {
+		return base.NewReadByteError(err)
+	}
+
+	resetToken := &entity.OneTimeToken{
+		UserID:    user.ID,
+		Purpose:   enum.PasswordReset,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(s.config.TimeToLive),
+	}
+
+	if err := s.tokenStorage.Create(resetToken, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	template, err := mail.LoadTemplate(mail.PasswordReset)
+	if err != nil {
+		return base.NewSendMessageError(err)
+	}
+
+	message := mail.FormatTemplate(*template, fmt.Sprintf(s.config.URL, token), resetToken.ExpiresAt.Format("02.01.2006 15:04 MST"))
+
+	if err := s.mailService.SendMessage(user.Email, "Восстановление пароля", message); err != nil {
+		return base.NewSendMessageError(err)
+	}
+
+	s.logger.Info(user.Email + ": password reset requested")
+
+	return nil
+}
+
+// ConfirmReset sets the new password of the user the token was issued to and signs out all sessions.
+func (s *PasswordResetService) ConfirmReset(request *model.ResetPasswordRequest, ctx context.Context) *base.ServiceError {
+	if request.Password == "" {
+		return &base.ServiceError{
+			Err:     errors.New("empty password"),
+			Blame:   base.BlameUser,
+			Code:    http.StatusBadRequest,
+			Message: "password must not be empty",
+		}
+	}
+
+	resetToken, err := s.tokenStorage.GetByHash(auth.HashOneTimeToken(request.Token), enum.PasswordReset, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return newInvalidResetTokenError(err)
+		}
+		return base.NewPostgresReadError(err)
+	}
+
+	now := time.Now()
+
+	if !resetToken.IsValid(now) {
+		return newInvalidResetTokenError(errors.New("reset token is used or expired"))
+	}
+
+	used, err := s.tokenStorage.MarkUsed(resetToken.ID, now, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if !used {
+		return newInvalidResetTokenError(errors.New("reset token is already used"))
+	}
+
+	hashPassword, err := s.hasher.Hash(request.Password)
+	if err != nil {
+		return base.NewReadByteError(err)
+	}
+
+	if err := s.userStorage.ChangePassword(resetToken.UserID, hashPassword, now, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditUserPasswordReset, enum.AuditTargetUser, resetToken.UserID, nil, nil, ctx)
+
+	if err := s.tokenStorage.DeleteByUserIDAndPurpose(resetToken.UserID, enum.PasswordReset, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	s.logger.Info(fmt.Sprintf("user %s: password reset", resetToken.UserID))
+
+	return s.authService.SignOutAllSession(resetToken.UserID, ctx)
+}
+
+func newInvalidResetTokenError(err error) *base.ServiceError {
+	return &base.ServiceError{
+		Err:     err,
+		Blame:   base.BlameUser,
+		Code:    http.StatusBadRequest,
+		Message: "reset token is invalid or expired",
+	}
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+// RoleService grants roles to users and checks their permissions.
+// It implements middleware.PermissionChecker consulted by the permission middleware.
+type RoleService struct {
+	roleStorage    *dao.RoleStorage
+	userStorage    *dao.UserStorage
+	companyStorage *dao.CompanyStorage
+	logger         *zap.Logger
+	auditService   *AuditService
+}
+
+func NewRoleService(
+	roleStorage *dao.RoleStorage,
+	userStorage *dao.UserStorage,
+	companyStorage *dao.CompanyStorage,
+	logger *zap.Logger,
+	auditService *AuditService) *RoleService {
+	return &RoleService{
+		roleStorage:    roleStorage,
+		userStorage:    userStorage,
+		companyStorage: companyStorage,
+		logger:         logger,
+		auditService:   auditService,
+	}
+}
+
+// HasPermission tells whether platform roles of the user grant the permission or, if companyID is not nil,
+// roles granted within the company. Company roles count only while the user owns or is a member of the company.
+func (s *RoleService) HasPermission(userID uuid.UUID, companyID *uuid.UUID, permission enum.Permission, ctx context.Context) (bool, error) {
+	allowed, err := s.roleStorage.HasPermission(userID, nil, permission, ctx)
+	if err != nil || allowed || companyID == nil {
+		return allowed, err
+	}
+
+	isMember, err := s.companyStorage.IsMember(*companyID, userID, ctx)
+	if err != nil || !isMember {
+		return false, err
+	}
+
+	return s.roleStorage.HasPermission(userID, companyID, permission, ctx)
+}
+
+// GetCompaniesWithPermission returns companies in which the user has the permission.
+// All is true if platform roles grant the permission everywhere, companies are not returned then.
+func (s *RoleService) GetCompaniesWithPermission(userID uuid.UUID, permission enum.Permission, ctx context.Context) (bool, []uuid.UUID, *base.ServiceError) {
+	all, err := s.roleStorage.HasPermission(userID, nil, permission, ctx)
+	if err != nil {
+		return false, nil, base.NewPostgresReadError(err)
+	}
+
+	if all {
+		return true, nil, nil
+	}
+
+	granted, err := s.roleStorage.GetCompaniesWithPermission(userID, permission, ctx)
+	if err != nil {
+		return false, nil, base.NewPostgresReadError(err)
+	}
+
+	companyIDs := make([]uuid.UUID, 0, len(granted))
+	for _, companyID := range granted {
+		isMember, err := s.companyStorage.IsMember(companyID, userID, ctx)
+		if err != nil {
+			return false, nil, base.NewPostgresReadError(err)
+		}
+
+		if isMember {
+			companyIDs = append(companyIDs, companyID)
+		}
+	}
+
+	return false, companyIDs, nil
+}
+
+// CanAccess is HasPermission for an optional viewer, anonymous viewers have no permissions.
+func (s *RoleService) CanAccess(viewerID *uuid.UUID, companyID uuid.UUID, permission enum.Permission, ctx context.Context) (bool, *base.ServiceError) {
+	if viewerID == nil {
+		return false, nil
+	}
+
+	allowed, err := s.HasPermission(*viewerID, &companyID, permission, ctx)
+	if err != nil {
+		return false, base.NewPostgresReadError(err)
+	}
+
+	return allowed, nil
+}
+
+func (s *RoleService) IsPlatformAdmin(userID uuid.UUID, ctx context.Context) (bool, *base.ServiceError) {
+	isAdmin, err := s.roleStorage.HasRole(userID, enum.RolePlatformAdmin, ctx)
+	if err != nil {
+		return false, base.NewPostgresReadError(err)
+	}
+
+	return isAdmin, nil
+}
+
+// GetRoleID returns ID of the stored role.
+func (s *RoleService) GetRoleID(name enum.Role, ctx context.Context) (uuid.UUID, *base.ServiceError) {
+	role, err := s.roleStorage.GetRole(name, ctx)
+	if err != nil {
+		return uuid.Nil, base.NewPostgresReadError(err)
+	}
+
+	return role.ID, nil
+}
+
+func (s *RoleService) GetRoles(ctx context.Context) ([]model.RoleObject, *base.ServiceError) {
+	roles, err := s.roleStorage.GetRoles(ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	result := make([]model.RoleObject, 0, len(roles))
+	for _, role := range roles {
+		permissions := make([]enum.Permission, 0, len(role.Permissions))
+		for _, permission := range role.Permissions {
+			permissions = append(permissions, permission.Permission)
+		}
+
+		result = append(result, model.RoleObject{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: permissions,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *RoleService) GetUserRoles(userID uuid.UUID, ctx context.Context) ([]model.RoleGrantObject, *base.ServiceError) {
+	userRoles, err := s.roleStorage.GetUserRoles(userID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	result := make([]model.RoleGrantObject, 0, len(userRoles))
+	for _, userRole := range userRoles {
+		result = append(result, model.RoleGrantObject{
+			ID:        userRole.ID,
+			CreatedAt: userRole.CreatedAt,
+			Role:      userRole.Role.Name,
+			CompanyID: userRole.CompanyID,
+			GrantedBy: userRole.GrantedBy,
+		})
+	}
+
+	return result, nil
+}
+
+// Grant grants the role to the user. Company roles need the company, platform roles must not have one.
+func (s *RoleService) Grant(granterID uuid.UUID, userID uuid.UUID, request *model.GrantRoleRequest, ctx context.Context) (*uuid.UUID, *base.ServiceError) {
+	if !request.Role.IsValid() {
+		return nil, newInvalidRoleError(fmt.Errorf("unknown role %q", request.Role), "unknown role")
+	}
+
+	if request.Role.IsCompanyRole() != (request.CompanyID != nil) {
+		return nil, newInvalidRoleError(
+			fmt.Errorf("role %s granted with company %v", request.Role, request.CompanyID),
+			"company is required for company roles and not allowed for platform roles")
+	}
+
+	if _, err := s.userStorage.Retrieve(userID, ctx); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, base.NewNotFoundError(fmt.Errorf("user %s not found", userID))
+		}
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	if request.CompanyID != nil {
+		if _, err := s.companyStorage.Retrieve(*request.CompanyID, ctx); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, base.NewNotFoundError(fmt.Errorf("company %s not found", request.CompanyID))
+			}
+			return nil, base.NewPostgresReadError(err)
+		}
+	}
+
+	return s.grant(&granterID, userID, request.Role, request.CompanyID, ctx)
+}
+
+// GrantCompanyRole grants the company role on behalf of the system, e.g. to the creator of a company.
+func (s *RoleService) GrantCompanyRole(userID uuid.UUID, companyID uuid.UUID, role enum.Role, ctx context.Context) *base.ServiceError {
+	_, serviceErr := s.grant(nil, userID, role, &companyID, ctx)
+	return serviceErr
+}
+
+// Revoke revokes a role of the user. The last platform administrator can't lose the role.
+func (s *RoleService) Revoke(userID uuid.UUID, grantID uuid.UUID, ctx context.Context) *base.ServiceError {
+	userRole, err := s.roleStorage.GetUserRole(grantID, userID, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return base.NewNotFoundError(fmt.Errorf("role grant %s not found", grantID))
+		}
+		return base.NewPostgresReadError(err)
+	}
+
+	if userRole.Role.Name == enum.RolePlatformAdmin {
+		count, err := s.roleStorage.CountUsersWithRole(enum.RolePlatformAdmin, nil, ctx)
+		if err != nil {
+			return base.NewPostgresReadError(err)
+		}
+
+		if count <= 1 {
+			return &base.ServiceError{
+				Err:     errors.New("revoking the last platform admin"),
+				Blame:   base.BlameUser,
+				Code:    http.StatusConflict,
+				Message: "the last platform administrator can't be revoked",
+			}
+		}
+	}
+
+	if userRole.Role.Name == enum.RoleCompanyOwner && userRole.CompanyID != nil {
+		company, err := s.companyStorage.Retrieve(*userRole.CompanyID, ctx)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return base.NewPostgresReadError(err)
+		}
+
+		if err == nil && company.Owner == userID {
+			return newOwnerRequiredError(fmt.Errorf("revoking owner role of company %s from its owner", company.ID))
+		}
+	}
+
+	if err := s.roleStorage.DeleteUserRole(userRole.ID, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditRoleRevoke, enum.AuditTargetRoleGrant, userRole.ID, roleGrantAuditState(userRole, userRole.Role.Name), nil, ctx)
+
+	s.logger.Info(fmt.Sprintf("user %s: role %s revoked", userID, userRole.Role.Name))
+
+	return nil
+}
+
+func (s *RoleService) grant(granterID *uuid.UUID, userID uuid.UUID, roleName enum.Role, companyID *uuid.UUID, ctx context.Context) (*uuid.UUID, *base.ServiceError) {
+	role, err := s.roleStorage.GetRole(roleName, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	exists, err := s.roleStorage.ExistsUserRole(userID, role.ID, companyID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	if exists {
+		return nil, &base.ServiceError{
+			Err:     fmt.Errorf("user %s already has role %s", userID, roleName),
+			Blame:   base.BlameUser,
+			Code:    http.StatusConflict,
+			Message: "user already has the role",
+		}
+	}
+
+	userRole := &entity.UserRole{
+		UserID:    userID,
+		RoleID:    role.ID,
+		CompanyID: companyID,
+		GrantedBy: granterID,
+	}
+
+	if err := s.roleStorage.CreateUserRole(userRole, ctx); err != nil {
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditRoleGrant, enum.AuditTargetRoleGrant, userRole.ID, nil, roleGrantAuditState(userRole, roleName), ctx)
+
+	s.logger.Info(fmt.Sprintf("user %s: role %s granted", userID, roleName))
+
+	return &userRole.ID, nil
+}
+
+func roleGrantAuditState(userRole *entity.UserRole, roleName enum.Role) map[string]any {
+	return map[string]any{
+		"user_id":    userRole.UserID,
+		"role":       roleName,
+		"company_id": userRole.CompanyID,
+		"granted_by": userRole.GrantedBy,
+	}
+}
+
+func newInvalidRoleError(err error, message string) *base.ServiceError {
+	return &base.ServiceError{
+		Err:     err,
+		Blame:   base.BlameUser,
+		Code:    http.StatusBadRequest,
+		Message: message,
+	}
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+// apiKeyTouchInterval limits how often last usage of an API key is written, a sync job may call us many times a second.
+const apiKeyTouchInterval = time.Minute
+
+// ServiceAccountService manages service accounts of machine clients and their API keys.
+// It implements auth.APIKeyAuthenticator consulted by the authorization middleware.
+type ServiceAccountService struct {
+	serviceAccountStorage *dao.ServiceAccountStorage
+	companyStorage        *dao.CompanyStorage
+	logger                *zap.Logger
+	auditService          *AuditService
+}
+
+func NewServiceAccountService(
+	serviceAccountStorage *dao.ServiceAccountStorage,
+	companyStorage *dao.CompanyStorage,
+	logger *zap.Logger,
+	auditService *AuditService) *ServiceAccountService {
+	return &ServiceAccountService{
+		serviceAccountStorage: serviceAccountStorage,
+		companyStorage:        companyStorage,
+		logger:                logger,
+		auditService:          auditService,
+	}
+}
+
+func (s *ServiceAccountService) CreateServiceAccount(adminID uuid.UUID, request *model.CreateServiceAccountRequest, ctx context.Context) (*uuid.UUID, *base.ServiceError) {
+	if request.Name == "" {
+		return nil, &base.ServiceError{
+			Err:     errors.New("empty service account name"),
+			Blame:   base.BlameUser,
+			Code:    http.StatusBadRequest,
+			Message: "name must not be empty",
+		}
+	}
+
+	if request.CompanyID != nil {
+		if _, err := s.companyStorage.Retrieve(*request.CompanyID, ctx); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, base.NewNotFoundError(fmt.Errorf("company %s not found", request.CompanyID))
+			}
+			return nil, base.NewPostgresReadError(err)
+		}
+	}
+
+	account := &entity.ServiceAccount{
+		Name:        request.Name,
+		Description: request.Description,
+		CompanyID:   request.CompanyID,
+		CreatedBy:   adminID,
+	}
+
+	if err := s.serviceAccountStorage.Create(account, ctx); err != nil {
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditServiceAccountCreate, enum.AuditTargetServiceAccount, account.ID, nil, map[string]any{
+		"name":        account.Name,
+		"description": account.Description,
+		"company_id":  account.CompanyID,
+	}, ctx)
+
+	s.logger.Info(fmt.Sprintf("service account %s (%s) created by %s", account.ID, account.Name, adminID))
+
+	return &account.ID, nil
+}
+
+func (s *ServiceAccountService) GetServiceAccounts(ctx context.Context) ([]model.ServiceAccountObject, *base.ServiceError) {
+	accounts, err := s.serviceAccountStorage.Get(ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	result := make([]model.ServiceAccountObject, 0, len(accounts))
+	for _, account := range accounts {
+		keys := make([]model.APIKeyObject, 0, len(account.APIKeys))
+		for i := range account.APIKeys {
+			keys = append(keys, apiKeyObject(&account.APIKeys[i]))
+		}
+
+		result = append(result, model.ServiceAccountObject{
+			ID:          account.ID,
+			CreatedAt:   account.CreatedAt,
+			Name:        account.Name,
+			Description: account.Description,
+			CompanyID:   account.CompanyID,
+			CreatedBy:   account.CreatedBy,
+			APIKeys:     keys,
+		})
+	}
+
+	return result, nil
+}
+
+// DeleteServiceAccount deletes the service account, its API keys stop working immediately.
+func (s *ServiceAccountService) DeleteServiceAccount(id uuid.UUID, ctx context.Context) *base.ServiceError {
+	deleted, err := s.serviceAccountStorage.Delete(id, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if !deleted {
+		return base.NewNotFoundError(fmt.Errorf("service account %s not found", id))
+	}
+
+	s.auditService.Record(enum.AuditServiceAccountDelete, enum.AuditTargetServiceAccount, id, nil, nil, ctx)
+
+	s.logger.Info(fmt.Sprintf("service account %s deleted", id))
+
+	return nil
+}
+
+// CreateAPIKey issues a new API key of the service account. The plain key is returned only here, only its hash is stored.
+func (s *ServiceAccountService) CreateAPIKey(serviceAccountID uuid.UUID, request *model.CreateAPIKeyRequest, ctx context.Context) (string, *model.APIKeyObject, *base.ServiceError) {
+	if len(request.Scopes) == 0 {
+		return "", nil, &base.ServiceError{
+			Err:     errors.New("empty api key scopes"),
+			Blame:   base.BlameUser,
+			Code:    http.StatusBadRequest,
+			Message: "at least one scope is required",
+		}
+	}
+
+	for _, scope := range request.Scopes {
+		if !scope.IsValid() {
+			return "", nil, &base.ServiceError{
+				Err:     fmt.Errorf("unknown api scope %q", scope),
+				Blame:   base.BlameUser,
+				Code:    http.StatusBadRequest,
+				Message: "unknown scope",
+			}
+		}
+	}
+
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return "", nil, &base.ServiceError{
+			Err:     errors.New("api key expiry in the past"),
+			Blame:   base.BlameUser,
+			Code:    http.StatusBadRequest,
+			Message: "expiry must be in the future",
+		}
+	}
+
+	if _, err := s.serviceAccountStorage.Retrieve(serviceAccountID, ctx); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil, base.NewNotFoundError(fmt.Errorf("service account %s not found", serviceAccountID))
+		}
+		return "", nil, base.NewPostgresReadError(err)
+	}
+
+	key, prefix, keyHash, err := auth.NewAPIKey()
+	if err != nil {
+		return "", nil, base.NewReadByteError(err)
+	}
+
+	apiKey := &entity.APIKey{
+		ServiceAccountID: serviceAccountID,
+		Name:             request.Name,
+		Prefix:           prefix,
+		KeyHash:          keyHash,
+		Scopes:           request.Scopes,
+		ExpiresAt:        request.ExpiresAt,
+	}
+
+	if err := s.serviceAccountStorage.CreateAPIKey(apiKey, ctx); err != nil {
+		return "", nil, base.NewPostgresWriteError(err)
+	}
+
+	s.logger.Info(fmt.Sprintf("api key %s issued for service account %s", apiKey.Prefix, serviceAccountID))
+
+	object := apiKeyObject(apiKey)
+	s.auditService.Record(enum.AuditAPIKeyCreate, enum.AuditTargetAPIKey, apiKey.ID, nil, object, ctx)
+
+	return key, &object, nil
+}
+
+func (s *ServiceAccountService) RevokeAPIKey(serviceAccountID uuid.UUID, keyID uuid.UUID, ctx context.Context) *base.ServiceError {
+	now := time.Now()
+
+	revoked, err := s.serviceAccountStorage.RevokeAPIKey(keyID, serviceAccountID, now, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if !revoked {
+		return base.NewNotFoundError(fmt.Errorf("active api key %s not found", keyID))
+	}
+
+	s.auditService.Record(enum.AuditAPIKeyRevoke, enum.AuditTargetAPIKey, keyID,
+		map[string]any{"revoked_at": nil}, map[string]any{"revoked_at": now}, ctx)
+
+	s.logger.Info(fmt.Sprintf("api key %s of service account %s revoked", keyID, serviceAccountID))
+
+	return nil
+}
+
+func (s *ServiceAccountService) Authenticate(key string, ctx context.Context) (*auth.ServiceAccountPrincipal, error) {
+	prefix, ok := auth.ParseAPIKeyPrefix(key)
+	if !ok {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.serviceAccountStorage.GetAPIKeyByPrefix(prefix, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if !auth.VerifyAPIKey(key, apiKey.KeyHash) || !apiKey.IsActive(now) {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.serviceAccountStorage.TouchAPIKey(apiKey.ID, now, ctx); err != nil {
+			s.logger.Error(fmt.Sprintf("failed to update last usage of api key %s: %v", apiKey.Prefix, err))
+		}
+	}
+
+	return &auth.ServiceAccountPrincipal{
+		ServiceAccountID: apiKey.ServiceAccountID,
+		APIKeyID:         apiKey.ID,
+		CompanyID:        apiKey.ServiceAccount.CompanyID,
+		Scopes:           apiKey.Scopes,
+	}, nil
+}
+
+func apiKeyObject(key *entity.APIKey) model.APIKeyObject {
+	return model.APIKeyObject{
+		ID:         key.ID,
+		CreatedAt:  key.CreatedAt,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
+	"github.com/google/uuid"
+	"time"
+)
+
+// TokenRevocationService keeps track of access tokens revoked before their expiry.
+// It implements auth.RevocationStore consulted by the authorization middleware.
+type TokenRevocationService struct {
+	storage    *dao.TokenRevocationStorage
+	timeToLive time.Duration
+}
+
+func NewTokenRevocationService(storage *dao.TokenRevocationStorage, timeToLive time.Duration) *TokenRevocationService {
+	return &TokenRevocationService{
+		storage:    storage,
+		timeToLive: timeToLive,
+	}
+}
+
+func (s *TokenRevocationService) IsRevoked(claims *auth.Claims, ctx context.Context) (bool, error) {
+	return s.storage.Exists(claims.TokenID, claims.SessionID, claims.UserID, claims.IssuedAt, time.Now(), ctx)
+}
+
+// RevokeToken revokes a single access token.
+func (s *TokenRevocationService) RevokeToken(claims *auth.Claims, ctx context.Context) *base.ServiceError {
+	return s.create(&entity.TokenRevocation{
+		TokenID:   &claims.TokenID,
+		UserID:    claims.UserID,
+		ExpiresAt: claims.ExpiresAt,
+	}, ctx)
+}
+
+// RevokeSessionTokens revokes every access token issued for the session family.
+func (s *TokenRevocationService) RevokeSessionTokens(userID uuid.UUID, sessionID uuid.UUID, ctx context.Context) *base.ServiceError {
+	return s.create(&entity.TokenRevocation{
+		SessionID: &sessionID,
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(s.timeToLive),
+	}, ctx)
+}
+
+// RevokeUserTokens revokes every access token of the user issued up to now.
+func (s *TokenRevocationService) RevokeUserTokens(userID uuid.UUID, ctx context.Context) *base.ServiceError {
+	// iat has a precision of one second, so the revocation is rounded up to the next second to cover
+	// tokens issued earlier within the current one. Tokens issued later within it are revoked too.
+	revokedBefore := time.Now().Truncate(time.Second).Add(time.Second)
+
+	return s.create(&entity.TokenRevocation{
+		UserID:        userID,
+		RevokedBefore: &revokedBefore,
+		ExpiresAt:     revokedBefore.Add(s.timeToLive),
+	}, ctx)
+}
+
+func (s *TokenRevocationService) create(revocation *entity.TokenRevocation, ctx context.Context) *base.ServiceError {
+	if err := s.storage.DeleteExpired(time.Now(), ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if err := s.storage.Create(revocation, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const backupCodeCount = 10
+
+// TwoFactorService manages optional TOTP two-factor authentication of users.
+type TwoFactorService struct {
+	userStorage       *dao.UserStorage
+	backupCodeStorage *dao.BackupCodeStorage
+	hasher            *auth.Hasher
+	logger            *zap.Logger
+	auditService      *AuditService
+	issuer            string
+}
+
+func NewTwoFactorService(
+	userStorage *dao.UserStorage,
+	backupCodeStorage *dao.BackupCodeStorage,
+	hasher *auth.Hasher,
+	logger *zap.Logger,
+	auditService *AuditService,
+	issuer string) *TwoFactorService {
+	return &TwoFactorService{
+		userStorage:       userStorage,
+		backupCodeStorage: backupCodeStorage,
+		hasher:            hasher,
+		logger:            logger,
+		auditService:      auditService,
+		issuer:            issuer,
+	}
+}
+
+// Enroll generates a new TOTP secret of the user. Two-factor authentication is not enabled
+// until a code generated from the secret is confirmed.
+func (s *TwoFactorService) Enroll(userID uuid.UUID, ctx context.Context) (*model.TwoFactorEnrollResponse, *base.ServiceError) {
+	user, err := s.userStorage.Retrieve(userID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	if user.IsTwoFactorEnabled() {
+		return nil, &base.ServiceError{
+			Err:     errors.New("two-factor authentication is already enabled"),
+			Blame:   base.BlameUser,
+			Code:    http.StatusConflict,
+			Message: "two-factor authentication is already enabled",
+		}
+	}
+
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		return nil, base.NewReadByteError(err)
+	}
+
+	if err := s.userStorage.UpdateTwoFactor(user.ID, secret, nil, ctx); err != nil {
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	return &model.TwoFactorEnrollResponse{
+		ResponseOK: base.ResponseOK{
+			Status: http.StatusText(http.StatusOK),
+		},
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm enables two-factor authentication once the user proves the authenticator is set up.
+// It returns backup codes, they are shown only once.
+func (s *TwoFactorService) Confirm(userID uuid.UUID, code string, ctx context.Context) ([]string, *base.ServiceError) {
+	user, err := s.userStorage.Retrieve(userID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	if user.TOTPSecret == "" || user.IsTwoFactorEnabled() {
+		return nil, &base.ServiceError{
+			Err:     errors.New("two-factor authentication is not being enrolled"),
+			Blame:   base.BlameUser,
+			Code:    http.StatusConflict,
+			Message: "start two-factor enrollment first",
+		}
+	}
+
+	step, ok := auth.VerifyTOTP(user.TOTPSecret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, newInvalidTwoFactorCodeError(errors.New("invalid totp code"))
+	}
+
+	now := time.Now()
+	if err := s.userStorage.UpdateTwoFactor(user.ID, user.TOTPSecret, &now, ctx); err != nil {
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	if _, err := s.userStorage.UseTOTPStep(user.ID, step, ctx); err != nil {
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditTwoFactorEnable, enum.AuditTargetUser, user.ID,
+		map[string]any{"two_factor_enabled": false}, map[string]any{"two_factor_enabled": true}, ctx)
+
+	s.logger.Info(user.Email + ": two-factor authentication enabled")
+
+	return s.replaceBackupCodes(user.ID, ctx)
+}
+
+// RegenerateBackupCodes replaces backup codes of the user with new ones.
+func (s *TwoFactorService) RegenerateBackupCodes(userID uuid.UUID, code string, ctx context.Context) ([]string, *base.ServiceError) {
+	user, serviceErr := s.getEnabledUser(userID, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	if serviceErr := s.VerifyCode(user, code, ctx); serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	codes, serviceErr := s.replaceBackupCodes(user.ID, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	s.auditService.Record(enum.AuditTwoFactorBackupCodes, enum.AuditTargetUser, user.ID, nil, nil, ctx)
+
+	return codes, nil
+}
+
+// Disable turns two-factor authentication off. Both the password and a current code are required.
+func (s *TwoFactorService) Disable(userID uuid.UUID, request *model.DisableTwoFactorRequest, ctx context.Context) *base.ServiceError {
+	user, serviceErr := s.getEnabledUser(userID, ctx)
+	if serviceErr != nil {
+		return serviceErr
+	}
+
+	ok, _, err := s.hasher.Verify(request.Password, user.Password)
+	if err != nil || !ok {
+		return base.NewLoginError(errors.New("invalid password"))
+	}
+
+	if serviceErr := s.VerifyCode(user, request.Code, ctx); serviceErr != nil {
+		return serviceErr
+	}
+
+	if err := s.userStorage.UpdateTwoFactor(user.ID, "", nil, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if err := s.backupCodeStorage.Replace(user.ID, nil, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditTwoFactorDisable, enum.AuditTargetUser, user.ID,
+		map[string]any{"two_factor_enabled": true}, map[string]any{"two_factor_enabled": false}, ctx)
+
+	s.logger.Info(user.Email + ": two-factor authentication disabled")
+
+	return nil
+}
+
+// VerifyCode accepts either a TOTP code or an unused backup code of the user.
+// Every code is accepted only once.
+func (s *TwoFactorService) VerifyCode(user *entity.User, code string, ctx context.Context) *base.ServiceError {
+	code = strings.TrimSpace(code)
+	now := time.Now()
+
+	if step, ok := auth.VerifyTOTP(user.TOTPSecret, code, now); ok {
+		fresh, err := s.userStorage.UseTOTPStep(user.ID, step, ctx)
+		if err != nil {
+			return base.NewPostgresWriteError(err)
+		}
+
+		if !fresh {
+			return newInvalidTwoFactorCodeError(errors.New("totp code has already been used"))
+		}
+
+		return nil
+	}
+
+	used, err := s.backupCodeStorage.Use(user.ID, auth.HashOneTimeToken(auth.NormalizeBackupCode(code)), now, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if !used {
+		return newInvalidTwoFactorCodeError(errors.New("invalid totp or backup code"))
+	}
+
+	s.logger.Warn(user.Email + ": backup code used")
+
+	return nil
+}
+
+func (s *TwoFactorService) getEnabledUser(userID uuid.UUID, ctx context.Context) (*entity.User, *base.ServiceError) {
+	user, err := s.userStorage.Retrieve(userID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	if !user.IsTwoFactorEnabled() {
+		return nil, &base.ServiceError{
+			Err:     errors.New("two-factor authentication is not enabled"),
+			Blame:   base.BlameUser,
+			Code:    http.StatusConflict,
+			Message: "two-factor authentication is not enabled",
+		}
+	}
+
+	return user, nil
+}
+
+func (s *TwoFactorService) replaceBackupCodes(userID uuid.UUID, ctx context.Context) ([]string, *base.ServiceError) {
+	codes := make([]string, 0, backupCodeCount)
+	backupCodes := make([]entity.BackupCode, 0, backupCodeCount)
+
+	for i := 0; i < backupCodeCount; i++ {
+		code, err := auth.NewBackupCode()
+		if err != nil {
+			return nil, base.NewReadByteError(err)
+		}
+
+		codes = append(codes, code)
+		backupCodes = append(backupCodes, entity.BackupCode{
+			UserID:   userID,
+			CodeHash: auth.HashOneTimeToken(code),
+		})
+	}
+
+	if err := s.backupCodeStorage.Replace(userID, backupCodes, ctx); err != nil {
+		return nil, base.NewPostgresWriteError(err)
+	}
+
+	return codes, nil
+}
+
+func newInvalidTwoFactorCodeError(err error) *base.ServiceError {
+	return &base.ServiceError{
+		Err:     fmt.Errorf("two-factor verification failed: %w", err),
+		Blame:   base.BlameUser,
+		Code:    http.StatusUnauthorized,
+		Message: "invalid two-factor code",
+	}
+}
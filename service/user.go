@@ -1,40 +1,85 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/common"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/helpers"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/s3"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"image/png"
+	"io"
 	"net/http"
+	"time"
+)
+
+const (
+	// defaultAvatarSize is the side in pixels of generated avatars.
+	defaultAvatarSize = 256
+	userPurgeInterval = time.Hour
+	// maxUserLookupIDs limits the bulk lookup of users by ID.
+	maxUserLookupIDs = 100
 )
 
 type UserService struct {
-	userStorage *dao.UserStorage
-	authService *AuthService
-	hasher      *auth.Hasher
-	adminID     uuid.UUID
+	userStorage              *dao.UserStorage
+	companyStorage           *dao.CompanyStorage
+	authService              *AuthService
+	emailVerificationService *EmailVerificationService
+	roleService              *RoleService
+	auditService             *AuditService
+	hasher                   *auth.Hasher
+	fileStorage              *dao.FileStorage
+	minioService             s3.ObjectStoreService
+	avatarConfig             common.AvatarConfig
+	deletionConfig           common.UserDeletionConfig
+	logger                   *zap.Logger
 }
 
 func NewUserService(
 	userStorage *dao.UserStorage,
+	companyStorage *dao.CompanyStorage,
 	authService *AuthService,
+	emailVerificationService *EmailVerificationService,
+	roleService *RoleService,
+	auditService *AuditService,
 	hasher *auth.Hasher,
-	adminID uuid.UUID) *UserService {
+	fileStorage *dao.FileStorage,
+	minioService s3.ObjectStoreService,
+	avatarConfig common.AvatarConfig,
+	deletionConfig common.UserDeletionConfig,
+	logger *zap.Logger) *UserService {
 	return &UserService{
-		userStorage: userStorage,
-		authService: authService,
-		hasher:      hasher,
-		adminID:     adminID,
+		userStorage:              userStorage,
+		companyStorage:           companyStorage,
+		authService:              authService,
+		emailVerificationService: emailVerificationService,
+		roleService:              roleService,
+		auditService:             auditService,
+		hasher:                   hasher,
+		fileStorage:              fileStorage,
+		minioService:             minioService,
+		avatarConfig:             avatarConfig,
+		deletionConfig:           deletionConfig,
+		logger:                   logger,
 	}
 }
 
-func (s *UserService) Get(option *dataProcessing.Options, ctx context.Context) ([]model.UserObject, int64, *base.ServiceError) {
+// Get returns users matching the options. A non-nil companyID limits them to members of the company.
+func (s *UserService) Get(option *dataProcessing.Options, companyID *uuid.UUID, ctx context.Context) ([]model.UserObject, int64, *base.ServiceError) {
 
-	users, total, err := s.userStorage.Get(option, ctx)
+	users, total, err := s.userStorage.Get(option, companyID, ctx)
 	if err != nil {
 		return nil, total, base.NewPostgresReadError(err)
 	}
@@ -42,13 +87,12 @@ func (s *UserService) Get(option *dataProcessing.Options, ctx context.Context) (
 	result := make([]model.UserObject, 0, len(users))
 
 	for _, user := range users {
-		result = append(result, model.UserObject{
-			ID:        user.ID,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-			Name:      user.Name,
-			Email:     user.Email,
-		})
+		object, serviceErr := s.userListObject(&user, ctx)
+		if serviceErr != nil {
+			return nil, total, serviceErr
+		}
+
+		result = append(result, object)
 	}
 
 	return result, total, nil
@@ -61,21 +105,33 @@ func (s *UserService) RetrieveUser(id uuid.UUID, ctx context.Context) (*model.Us
 		return nil, base.NewPostgresReadError(err)
 	}
 
-	var isAdmin bool
+	isAdmin, serviceErr := s.roleService.IsPlatformAdmin(id, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	roles, serviceErr := s.roleService.GetUserRoles(id, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
 
-	if id == s.adminID {
-		isAdmin = true
-	} else {
-		isAdmin = false
+	avatarURL, serviceErr := s.getAvatarURL(user, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
 	}
 
 	return &model.UserObject{
-		ID:        user.ID,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-		Name:      user.Name,
-		IsAdmin:   isAdmin,
-		Email:     user.Email,
+		ID:               user.ID,
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+		Name:             user.Name,
+		IsAdmin:          isAdmin,
+		Roles:            roles,
+		Email:            user.Email,
+		AvatarURL:        avatarURL,
+		EmailVerified:    user.IsEmailVerified(),
+		PendingEmail:     user.PendingEmail,
+		TwoFactorEnabled: user.IsTwoFactorEnabled(),
 	}, nil
 }
 
@@ -85,7 +141,8 @@ func (s *UserService) Update(id uuid.UUID, request model.UpdateUserAllFieldReque
 		return base.NewPostgresReadError(err)
 	}
 
-	user.Email = request.Email
+	before := newUserAuditState(user)
+
 	if request.Password != nil {
 		user.Password, err = s.hasher.Hash(*request.Password)
 	}
@@ -100,21 +157,32 @@ func (s *UserService) Update(id uuid.UUID, request model.UpdateUserAllFieldReque
 		return base.NewPostgresWriteError(err)
 	}
 
-	return nil
-}
+	s.auditService.Record(enum.AuditUserUpdate, enum.AuditTargetUser, user.ID, before, newUserAuditState(user), ctx)
 
-func (s *UserService) UpdateAuthorizationFields(id uuid.UUID, request model.UpdateUserAuthorizationFieldsRequest, ctx context.Context) (mainErr *base.ServiceError) {
-	hashOldPassword, err := s.hasher.Hash(*request.OldPassword)
-	if err != nil {
-		return base.NewUnauthorizedError(err)
+	if request.Password != nil {
+		if serviceErr := s.authService.SignOutAllSession(user.ID, ctx); serviceErr != nil {
+			return serviceErr
+		}
 	}
 
+	return s.changeEmail(user, request.Email, ctx)
+}
+
+func (s *UserService) UpdateAuthorizationFields(id uuid.UUID, request model.UpdateUserAuthorizationFieldsRequest, ctx context.Context) (mainErr *base.ServiceError) {
 	user, err := s.userStorage.Retrieve(id, ctx)
 	if err != nil {
 		return base.NewPostgresReadError(err)
 	}
 
-	if user.Password != hashOldPassword {
+	var ok bool
+	if request.OldPassword != nil {
+		ok, _, err = s.hasher.Verify(*request.OldPassword, user.Password)
+		if err != nil {
+			return base.NewUnauthorizedError(err)
+		}
+	}
+
+	if !ok {
 		return &base.ServiceError{
 			Err:     errors.New("authentication failed. Please provide valid credentials"),
 			Message: "authentication failed. Please provide valid credentials",
@@ -128,42 +196,332 @@ func (s *UserService) UpdateAuthorizationFields(id uuid.UUID, request model.Upda
 		return base.NewUnauthorizedError(err)
 	}
 
-	user.Email = request.Email
+	before := newUserAuditState(user)
 	user.Password = hashNewPassword
 
 	if err := s.userStorage.Update(user, ctx); err != nil {
 		return base.NewPostgresWriteError(err)
 	}
 
+	s.auditService.Record(enum.AuditUserUpdate, enum.AuditTargetUser, user.ID, before, newUserAuditState(user), ctx)
+
 	if serviceErr := s.authService.SignOutAllSession(user.ID, ctx); serviceErr != nil {
 		return serviceErr
 	}
 
+	return s.changeEmail(user, request.Email, ctx)
+}
+
+// changeEmail requests confirmation of the new email, it becomes the login of the user only after that.
+func (s *UserService) changeEmail(user *entity.User, email string, ctx context.Context) *base.ServiceError {
+	if email == "" || email == user.Email || email == user.PendingEmail {
+		return nil
+	}
+
+	return s.emailVerificationService.RequestEmailChange(user, email, ctx)
+}
+
+// GetUsersById looks up users in bulk, unknown IDs are skipped. A non-nil companyID limits them to members of the company.
+func (s *UserService) GetUsersById(ids []uuid.UUID, companyID *uuid.UUID, ctx context.Context) ([]model.UserObject, *base.ServiceError) {
+	if len(ids) > maxUserLookupIDs {
+		return nil, &base.ServiceError{
+			Err:     fmt.Errorf("%d ids requested", len(ids)),
+			Blame:   base.BlameUser,
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("at most %d users can be requested at once", maxUserLookupIDs),
+		}
+	}
+
+	result := make([]model.UserObject, 0, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	users, err := s.userStorage.GetByIDList(ids, companyID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+	for _, user := range users {
+		object, serviceErr := s.userListObject(&user, ctx)
+		if serviceErr != nil {
+			return nil, serviceErr
+		}
+
+		result = append(result, object)
+	}
+	return result, nil
+}
+
+// GetCompanyUsers returns users of the companies grouped by company ID, all of them are loaded at once.
+func (s *UserService) GetCompanyUsers(companyIDs []uuid.UUID, ctx context.Context) (map[uuid.UUID][]model.UserObject, *base.ServiceError) {
+	result := make(map[uuid.UUID][]model.UserObject, len(companyIDs))
+	if len(companyIDs) == 0 {
+		return result, nil
+	}
+
+	users, err := s.userStorage.GetByCompanyIDs(companyIDs, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	for _, user := range users {
+		object, serviceErr := s.userListObject(&user, ctx)
+		if serviceErr != nil {
+			return nil, serviceErr
+		}
+
+		result[*user.CompanyID] = append(result[*user.CompanyID], object)
+	}
+
+	return result, nil
+}
+
+func (s *UserService) DeleteUser(id uuid.UUID, ctx context.Context) *base.ServiceError {
+	user, err := s.userStorage.Retrieve(id, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return base.NewNotFoundError(fmt.Errorf("user %s not found", id))
+		}
+		return base.NewPostgresReadError(err)
+	}
+
+	// companies without other members are deleted along with the owner, others need a new owner first
+	hasMembers, err := s.companyStorage.ExistsOwnedWithMembers(id, ctx)
+	if err != nil {
+		return base.NewPostgresReadError(err)
+	}
+
+	if hasMembers {
+		return newOwnerRequiredError(fmt.Errorf("deleting user %s owning a company with members", id))
+	}
+
+	if serviceErr := s.authService.SignOutAllSession(id, ctx); serviceErr != nil {
+		return serviceErr
+	}
+
+	if err := s.userStorage.DeleteUser(id, time.Now(), ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditUserDelete, enum.AuditTargetUser, id, newUserAuditState(user), nil, ctx)
+
 	return nil
 }
 
-func (s *UserService) GetUsersById(ids []uuid.UUID, ctx context.Context) ([]model.UserObject, *base.ServiceError) {
-	users, err := s.userStorage.GetByIDList(ids, ctx)
+// GetDeletedUsers returns deleted users which are not purged yet.
+func (s *UserService) GetDeletedUsers(ctx context.Context) ([]model.DeletedUserObject, *base.ServiceError) {
+	users, err := s.userStorage.GetDeleted(ctx)
 	if err != nil {
 		return nil, base.NewPostgresReadError(err)
 	}
-	var result []model.UserObject
+
+	result := make([]model.DeletedUserObject, 0, len(users))
 	for _, user := range users {
-		result = append(result, model.UserObject{
+		result = append(result, model.DeletedUserObject{
 			ID:        user.ID,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
 			Name:      user.Name,
 			Email:     user.Email,
+			DeletedAt: user.DeletedAt.Time,
+			PurgeAt:   user.DeletedAt.Time.Add(s.deletionConfig.RestoreWindow),
 		})
 	}
+
 	return result, nil
 }
 
-func (s *UserService) DeleteUser(id uuid.UUID, ctx context.Context) *base.ServiceError {
-	if err := s.userStorage.DeleteUser(id, ctx); err != nil {
+// RestoreUser undoes deletion of the user within the restore window, companies deleted along with the user are restored too.
+// Sessions are not restored, the user has to log in again.
+func (s *UserService) RestoreUser(id uuid.UUID, ctx context.Context) *base.ServiceError {
+	user, err := s.userStorage.RetrieveDeleted(id, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return base.NewNotFoundError(fmt.Errorf("deleted user %s not found", id))
+		}
+		return base.NewPostgresReadError(err)
+	}
+
+	if time.Now().After(user.DeletedAt.Time.Add(s.deletionConfig.RestoreWindow)) {
+		return &base.ServiceError{
+			Err:     fmt.Errorf("restore window of user %s has passed", id),
+			Blame:   base.BlameUser,
+			Code:    http.StatusGone,
+			Message: "user can't be restored anymore",
+		}
+	}
+
+	restored, err := s.userStorage.Restore(id, user.DeletedAt.Time, ctx)
+	if err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	if !restored {
+		return base.NewNotFoundError(fmt.Errorf("user %s was restored or purged meanwhile", id))
+	}
+
+	s.auditService.Record(enum.AuditUserRestore, enum.AuditTargetUser, id, nil, newUserAuditState(user), ctx)
+
+	return nil
+}
+
+// StartPurge periodically purges users deleted longer than the restore window ago. It blocks until ctx is done.
+func (s *UserService) StartPurge(ctx context.Context) {
+	ticker := time.NewTicker(userPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		s.purgeDeletedUsers(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *UserService) purgeDeletedUsers(ctx context.Context) {
+	users, err := s.userStorage.GetDeletedBefore(time.Now().Add(-s.deletionConfig.RestoreWindow), ctx)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to get users to purge: %v", err))
+		return
+	}
+
+	for _, user := range users {
+		files, documents, err := s.userStorage.Purge(user.ID, user.DeletedAt.Time, ctx)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("failed to purge user %s: %v", user.ID, err))
+			continue
+		}
+
+		s.auditService.Record(enum.AuditUserPurge, enum.AuditTargetUser, user.ID, newUserAuditState(&user), nil, ctx)
+
+		// the user is gone already, so leftover files are only logged
+		for _, file := range files {
+			if serviceErr := s.removeFile(&file, ctx); serviceErr != nil {
+				s.logger.Error(fmt.Sprintf("failed to remove file %s of purged user %s: %v", file.ID, user.ID, serviceErr.Err))
+			}
+		}
+
+		for _, document := range documents {
+			if serviceErr := removeDocumentFile(s.minioService, s.fileStorage, &document, ctx); serviceErr != nil {
+				s.logger.Error(fmt.Sprintf("failed to remove file %s of purged user %s: %v", document.FileID, user.ID, serviceErr.Err))
+			}
+		}
+
+		s.logger.Info(fmt.Sprintf("user %s purged", user.ID))
+	}
+}
+
+// UploadAvatar replaces the picture of the user, it is converted to WebP.
+func (s *UserService) UploadAvatar(id uuid.UUID, file io.Reader, ctx context.Context) *base.ServiceError {
+	user, err := s.userStorage.Retrieve(id, ctx)
+	if err != nil {
 		return base.NewPostgresReadError(err)
 	}
 
+	fileId, serviceErr := s.minioService.UploadAsWebP(ctx, enum.UserAvatar, file)
+	if serviceErr != nil {
+		return serviceErr
+	}
+
+	newFile := &entity.File{
+		Key:    *fileId,
+		Bucket: string(enum.UserAvatar),
+	}
+
+	if err := s.fileStorage.Create(newFile, ctx); err != nil {
+		_ = s.minioService.DeleteWebPFile(ctx, enum.UserAvatar, *fileId)
+		return base.NewPostgresWriteError(err)
+	}
+
+	if err := s.userStorage.UpdateAvatar(user.ID, &newFile.ID, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditUserAvatarUpload, enum.AuditTargetUser, user.ID,
+		map[string]any{"avatar_id": user.AvatarID}, map[string]any{"avatar_id": newFile.ID}, ctx)
+
+	// the new avatar is in place already, so a former one left behind is only logged
+	if serviceErr := s.removeAvatarFile(user.Avatar, ctx); serviceErr != nil {
+		s.logger.Error(fmt.Sprintf("failed to remove former avatar %s of user %s: %v", user.Avatar.ID, user.ID, serviceErr.Err))
+	}
+
+	return nil
+}
+
+// DeleteAvatar removes the picture of the user, the generated one is shown instead.
+func (s *UserService) DeleteAvatar(id uuid.UUID, ctx context.Context) *base.ServiceError {
+	user, err := s.userStorage.Retrieve(id, ctx)
+	if err != nil {
+		return base.NewPostgresReadError(err)
+	}
+
+	if user.Avatar == nil {
+		return nil
+	}
+
+	if err := s.userStorage.UpdateAvatar(user.ID, nil, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditUserAvatarDelete, enum.AuditTargetUser, user.ID,
+		map[string]any{"avatar_id": user.AvatarID}, map[string]any{"avatar_id": nil}, ctx)
+
+	return s.removeAvatarFile(user.Avatar, ctx)
+}
+
+// GetDefaultAvatar returns the PNG picture generated for the user. It depends on the ID only,
+// so it is the same every time and reveals nothing about the user.
+func (s *UserService) GetDefaultAvatar(id uuid.UUID) ([]byte, *base.ServiceError) {
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, helpers.GenerateIdenticon(id[:], defaultAvatarSize)); err != nil {
+		return nil, base.NewReadByteError(err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// userListObject is the user as shown in lists, without roles and account settings.
+func (s *UserService) userListObject(user *entity.User, ctx context.Context) (model.UserObject, *base.ServiceError) {
+	avatarURL, serviceErr := s.getAvatarURL(user, ctx)
+	if serviceErr != nil {
+		return model.UserObject{}, serviceErr
+	}
+
+	return model.UserObject{
+		ID:        user.ID,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		Name:      user.Name,
+		Email:     user.Email,
+		AvatarURL: avatarURL,
+	}, nil
+}
+
+func (s *UserService) getAvatarURL(user *entity.User, ctx context.Context) (string, *base.ServiceError) {
+	if user.Avatar == nil {
+		return fmt.Sprintf(s.avatarConfig.DefaultURL, user.ID), nil
+	}
+
+	return s.minioService.GetWebPFileURL(ctx, enum.UserAvatar, user.Avatar.Key)
+}
+
+func (s *UserService) removeAvatarFile(avatar *entity.File, ctx context.Context) *base.ServiceError {
+	if avatar == nil {
+		return nil
+	}
+
+	return s.removeFile(avatar, ctx)
+}
+
+func (s *UserService) removeFile(file *entity.File, ctx context.Context) *base.ServiceError {
+	if serviceErr := removePicture(s.minioService, file, ctx); serviceErr != nil {
+		return serviceErr
+	}
+
+	if err := s.fileStorage.Delete(file.ID, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
 	return nil
 }
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
+	"github.com/google/uuid"
+	"time"
+)
+
+// UserExportService collects everything stored about a user for the self-service data export.
+// Secrets, i.e. password and token hashes, TOTP and backup codes, are never exported.
+type UserExportService struct {
+	userStorage          *dao.UserStorage
+	companyStorage       *dao.CompanyStorage
+	oidcStorage          *dao.OIDCStorage
+	invitationStorage    *dao.InvitationStorage
+	loginThrottleStorage *dao.LoginThrottleStorage
+	backupCodeStorage    *dao.BackupCodeStorage
+	authService          *AuthService
+	roleService          *RoleService
+	auditService         *AuditService
+}
+
+func NewUserExportService(
+	userStorage *dao.UserStorage,
+	companyStorage *dao.CompanyStorage,
+	oidcStorage *dao.OIDCStorage,
+	invitationStorage *dao.InvitationStorage,
+	loginThrottleStorage *dao.LoginThrottleStorage,
+	backupCodeStorage *dao.BackupCodeStorage,
+	authService *AuthService,
+	roleService *RoleService,
+	auditService *AuditService) *UserExportService {
+	return &UserExportService{
+		userStorage:          userStorage,
+		companyStorage:       companyStorage,
+		oidcStorage:          oidcStorage,
+		invitationStorage:    invitationStorage,
+		loginThrottleStorage: loginThrottleStorage,
+		backupCodeStorage:    backupCodeStorage,
+		authService:          authService,
+		roleService:          roleService,
+		auditService:         auditService,
+	}
+}
+
+func (s *UserExportService) Export(userID uuid.UUID, ctx context.Context) (*model.UserExportObject, *base.ServiceError) {
+	user, err := s.userStorage.Retrieve(userID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	unusedBackupCodes, err := s.backupCodeStorage.CountUnused(userID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	roles, serviceErr := s.roleService.GetUserRoles(userID, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	sessions, serviceErr := s.authService.GetSessions(userID, uuid.Nil, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	identities, err := s.oidcStorage.GetIdentitiesByUserID(userID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	companies, err := s.companyStorage.GetOwned(userID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	invitations, err := s.invitationStorage.GetByInviter(userID, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	lockouts, err := s.loginThrottleStorage.GetLockoutEventsBySubject(enum.ThrottleAccount, user.Email, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	auditEvents, serviceErr := s.auditService.GetUserEvents(userID, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	export := &model.UserExportObject{
+		ExportedAt: time.Now(),
+		Profile: model.UserProfileExportObject{
+			ID:                 user.ID,
+			CreatedAt:          user.CreatedAt,
+			UpdatedAt:          user.UpdatedAt,
+			Name:               user.Name,
+			Email:              user.Email,
+			EmailVerifiedAt:    user.EmailVerifiedAt,
+			PendingEmail:       user.PendingEmail,
+			TwoFactorEnabledAt: user.TOTPEnabledAt,
+			UnusedBackupCodes:  unusedBackupCodes,
+			CompanyID:          user.CompanyID,
+			AvatarID:           user.AvatarID,
+		},
+		Roles:              roles,
+		Sessions:           sessions,
+		ExternalIdentities: make([]model.ExternalIdentityObject, 0, len(identities)),
+		OwnedCompanies:     make([]model.OwnedCompanyExportObject, 0, len(companies)),
+		SentInvitations:    make([]model.InvitationObject, 0, len(invitations)),
+		LockoutEvents:      make([]model.LockoutEventObject, 0, len(lockouts)),
+		AuditEvents:        auditEvents,
+	}
+
+	for _, identity := range identities {
+		export.ExternalIdentities = append(export.ExternalIdentities, model.ExternalIdentityObject{
+			CreatedAt:   identity.CreatedAt,
+			Provider:    identity.Provider,
+			Subject:     identity.Subject,
+			Email:       identity.Email,
+			LastLoginAt: identity.LastLoginAt,
+		})
+	}
+
+	for _, company := range companies {
+		export.OwnedCompanies = append(export.OwnedCompanies, model.OwnedCompanyExportObject{
+			ID:          company.ID,
+			CreatedAt:   company.CreatedAt,
+			Name:        company.Name,
+			Description: company.Description,
+		})
+	}
+
+	for _, invitation := range invitations {
+		export.SentInvitations = append(export.SentInvitations, model.InvitationObject{
+			ID:        invitation.ID,
+			CreatedAt: invitation.CreatedAt,
+			Email:     invitation.Email,
+			Role:      invitation.Role,
+			InvitedBy: invitation.InvitedBy,
+			ExpiresAt: invitation.ExpiresAt,
+		})
+	}
+
+	for _, lockout := range lockouts {
+		export.LockoutEvents = append(export.LockoutEvents, model.LockoutEventObject{
+			ID:          lockout.ID,
+			CreatedAt:   lockout.CreatedAt,
+			Scope:       lockout.Scope,
+			Subject:     lockout.Subject,
+			IPAddress:   lockout.IPAddress,
+			Failures:    lockout.Failures,
+			LockedUntil: lockout.LockedUntil,
+		})
+	}
+
+	return export, nil
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/auth"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/common"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/helpers"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/mail"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	netmail "net/mail"
+	"strings"
+)
+
+const (
+	generatedPasswordLength = 12
+
+	importColumnName  = "name"
+	importColumnEmail = "email"
+	importColumnRole  = "role"
+)
+
+// UserImportService registers users in bulk from CSV files. Imported users get generated passwords
+// by email and have to change them on the first login.
+type UserImportService struct {
+	userStorage    *dao.UserStorage
+	companyStorage *dao.CompanyStorage
+	roleService    *RoleService
+	hasher         *auth.Hasher
+	mailService    *mail.MailService
+	logger         *zap.Logger
+	auditService   *AuditService
+	config         common.UserImportConfig
+}
+
+func NewUserImportService(
+	userStorage *dao.UserStorage,
+	companyStorage *dao.CompanyStorage,
+	roleService *RoleService,
+	hasher *auth.Hasher,
+	mailService *mail.MailService,
+	logger *zap.Logger,
+	auditService *AuditService,
+	config common.UserImportConfig) *UserImportService {
+	return &UserImportService{
+		userStorage:    userStorage,
+		companyStorage: companyStorage,
+		roleService:    roleService,
+		hasher:         hasher,
+		mailService:    mailService,
+		logger:         logger,
+		auditService:   auditService,
+		config:         config,
+	}
+}
+
+// Import registers users listed in the CSV file. The header names the name and email columns and,
+// when importing into a company, the role column. With companyID the users become members of the company.
+// Invalid rows are reported and skipped, the rest are created. A dry run only validates the file.
+func (s *UserImportService) Import(file io.Reader, companyID *uuid.UUID, dryRun bool, ctx context.Context) (*model.UserImportResponse, *base.ServiceError) {
+	if companyID != nil {
+		if _, err := s.companyStorage.Retrieve(*companyID, ctx); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, base.NewNotFoundError(fmt.Errorf("company %s not found", *companyID))
+			}
+			return nil, base.NewPostgresReadError(err)
+		}
+	}
+
+	rows, serviceErr := s.readRows(file, companyID != nil)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	if serviceErr := s.validateRows(rows, companyID != nil, ctx); serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	response := &model.UserImportResponse{
+		DryRun: dryRun,
+		Rows:   rows,
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		if len(row.Errors) > 0 {
+			row.Status = enum.UserImportInvalid
+			response.Failed++
+			continue
+		}
+
+		if dryRun {
+			row.Status = enum.UserImportValid
+			continue
+		}
+
+		if err := s.createUser(row, companyID, ctx); err != nil {
+			row.Status = enum.UserImportFailed
+			row.Errors = append(row.Errors, err.Error())
+			response.Failed++
+			continue
+		}
+
+		row.Status = enum.UserImportCreated
+		response.Created++
+	}
+
+	if !dryRun {
+		s.logger.Info(fmt.Sprintf("user import: %d created, %d failed", response.Created, response.Failed))
+	}
+
+	return response, nil
+}
+
+// readRows parses the CSV file. Malformed lines are kept as rows with an error.
+func (s *UserImportService) readRows(file io.Reader, withRole bool) ([]model.UserImportRowObject, *base.ServiceError) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, newImportFileError(fmt.Errorf("failed to read the header: %w", err), "failed to read the CSV header")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		// spreadsheet editors put a byte order mark at the start of the file
+		column = strings.TrimPrefix(column, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	required := []string{importColumnName, importColumnEmail}
+	if withRole {
+		required = append(required, importColumnRole)
+	}
+
+	for _, column := range required {
+		if _, ok := columns[column]; !ok {
+			return nil, newImportFileError(fmt.Errorf("column %q is missing", column), fmt.Sprintf("the %s column is missing", column))
+		}
+	}
+
+	var rows []model.UserImportRowObject
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if len(rows) == s.config.MaxRows {
+			return nil, newImportFileError(
+				fmt.Errorf("more than %d rows", s.config.MaxRows),
+				fmt.Sprintf("a file can't have more than %d rows", s.config.MaxRows))
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, model.UserImportRowObject{
+				Row:    parseErr.StartLine,
+				Errors: []string{"malformed line"},
+			})
+			continue
+		}
+
+		if err != nil {
+			return nil, newImportFileError(err, "failed to read the file")
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		rows = append(rows, model.UserImportRowObject{
+			Row:   line,
+			Name:  field(importColumnName),
+			Email: field(importColumnEmail),
+			Role:  enum.Role(field(importColumnRole)),
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil, newImportFileError(errors.New("no rows"), "the file has no users")
+	}
+
+	return rows, nil
+}
+
+// validateRows records what is wrong with every row. Emails must be unique within the file and among users.
+func (s *UserImportService) validateRows(rows []model.UserImportRowObject, withRole bool, ctx context.Context) *base.ServiceError {
+	emails := make([]string, 0, len(rows))
+	seen := make(map[string]bool, len(rows))
+
+	for i := range rows {
+		row := &rows[i]
+		if len(row.Errors) > 0 {
+			continue
+		}
+
+		if row.Name == "" {
+			row.Errors = append(row.Errors, "name is required")
+		}
+
+		if address, err := netmail.ParseAddress(row.Email); err != nil || address.Address != row.Email {
+			row.Errors = append(row.Errors, "email is invalid")
+		} else if seen[strings.ToLower(row.Email)] {
+			row.Errors = append(row.Errors, "email is repeated in the file")
+		} else {
+			seen[strings.ToLower(row.Email)] = true
+			emails = append(emails, row.Email)
+		}
+
+		switch {
+		case !withRole && row.Role != "":
+			row.Errors = append(row.Errors, "role can be given only when importing into a company")
+		case withRole && row.Role == "":
+			row.Errors = append(row.Errors, "role is required")
+		case withRole && !row.Role.IsInvitable():
+			row.Errors = append(row.Errors, "unknown company role")
+		}
+	}
+
+	if len(emails) == 0 {
+		return nil
+	}
+
+	taken, err := s.userStorage.GetTakenEmails(emails, ctx)
+	if err != nil {
+		return base.NewPostgresReadError(err)
+	}
+
+	takenSet := make(map[string]bool, len(taken))
+	for _, email := range taken {
+		takenSet[email] = true
+	}
+
+	for i := range rows {
+		if takenSet[rows[i].Email] {
+			rows[i].Errors = append(rows[i].Errors, "email is taken")
+		}
+	}
+
+	return nil
+}
+
+// createUser registers the user of the row with a generated password and the company role in one
+// transaction, then emails the credentials. A user whose email could not be sent is still created,
+// the password can be reset by the user.
+func (s *UserImportService) createUser(row *model.UserImportRowObject, companyID *uuid.UUID, ctx context.Context) error {
+	password, err := helpers.GeneratePassword(generatedPasswordLength, 2, 2, 2)
+	if err != nil {
+		s.logger.Error(row.Email + ": failed to generate password: " + err.Error())
+		return errors.New("failed to generate password")
+	}
+
+	hashPassword, err := s.hasher.Hash(password)
+	if err != nil {
+		s.logger.Error(row.Email + ": failed to hash password: " + err.Error())
+		return errors.New("failed to generate password")
+	}
+
+	// the email is verified by the first sign in with the sent credentials, since delivery isn't guaranteed
+	user := &entity.User{
+		Name:                   row.Name,
+		Email:                  row.Email,
+		Password:               hashPassword,
+		PasswordChangeRequired: true,
+		CompanyID:              companyID,
+	}
+
+	var userRole *entity.UserRole
+	if companyID != nil {
+		roleID, serviceErr := s.roleService.GetRoleID(row.Role, ctx)
+		if serviceErr != nil {
+			s.logger.Error(row.Email + ": failed to find imported role: " + serviceErr.Error())
+			return errors.New("failed to grant the role")
+		}
+
+		userRole = &entity.UserRole{
+			RoleID:    roleID,
+			CompanyID: companyID,
+		}
+	}
+
+	if err := s.userStorage.CreateWithRole(user, userRole, ctx); err != nil {
+		s.logger.Error(row.Email + ": failed to create imported user: " + err.Error())
+		return errors.New("failed to create the user")
+	}
+
+	row.UserID = &user.ID
+	s.auditService.Record(enum.AuditUserImport, enum.AuditTargetUser, user.ID, nil, newUserAuditState(user), ctx)
+	if userRole != nil {
+		s.auditService.Record(enum.AuditRoleGrant, enum.AuditTargetRoleGrant, userRole.ID, nil, roleGrantAuditState(userRole, row.Role), ctx)
+	}
+
+	if serviceErr := s.sendCredentials(user, password); serviceErr != nil {
+		s.logger.Error(row.Email + ": failed to send credentials: " + serviceErr.Error())
+		row.Errors = append(row.Errors, "the credentials were not sent, the user can reset the password")
+	}
+
+	return nil
+}
+
+func (s *UserImportService) sendCredentials(user *entity.User, password string) *base.ServiceError {
+	template, err := mail.LoadTemplate(mail.Credentials)
+	if err != nil {
+		return base.NewSendMessageError(err)
+	}
+
+	message := mail.FormatTemplate(*template, user.Name, user.Email, password, s.config.LoginURL)
+
+	if err := s.mailService.SendMessage(user.Email, "Учётная запись создана", message); err != nil {
+		return base.NewSendMessageError(err)
+	}
+
+	return nil
+}
+
+func newImportFileError(err error, message string) *base.ServiceError {
+	return &base.ServiceError{
+		Err:     err,
+		Blame:   base.BlameUser,
+		Code:    http.StatusBadRequest,
+		Message: message,
+	}
+}
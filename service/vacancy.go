@@ -2,27 +2,38 @@
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/base"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/helpers"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/model"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/storage/dao"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"strings"
 )
 
 type VacancyService struct {
 	logger           *zap.Logger
+	auditService     *AuditService
 	companyStorage   *dao.CompanyStorage
 	vacancyStorage   *dao.VacancyStorage
+	roleService      *RoleService
 	candidateService *CandidateService
 }
 
-func NewVacancyService(logger *zap.Logger, vacancyStorage *dao.VacancyStorage, companyStorage *dao.CompanyStorage, candidateService *CandidateService) *VacancyService {
+func NewVacancyService(logger *zap.Logger, auditService *AuditService, vacancyStorage *dao.VacancyStorage, companyStorage *dao.CompanyStorage, roleService *RoleService, candidateService *CandidateService) *VacancyService {
 	return &VacancyService{
 		logger:           logger,
+		auditService:     auditService,
 		vacancyStorage:   vacancyStorage,
 		companyStorage:   companyStorage,
+		roleService:      roleService,
 		candidateService: candidateService,
 	}
 }
@@ -33,8 +44,14 @@ func (s *VacancyService) CreateVacancy(companyID uuid.UUID, request *model.Creat
 		return nil, base.NewPostgresReadError(err)
 	}
 
+	slug, serviceErr := s.newSlug(company.ID, request.Name, uuid.Nil, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
 	newVacancy := &entity.Vacancy{
 		Name:        request.Name,
+		Slug:        slug,
 		Salary:      request.Salary,
 		City:        request.City,
 		Description: request.Description,
@@ -46,35 +63,136 @@ func (s *VacancyService) CreateVacancy(companyID uuid.UUID, request *model.Creat
 		return nil, base.NewPostgresWriteError(err)
 	}
 
+	s.auditService.Record(enum.AuditVacancyCreate, enum.AuditTargetVacancy, newVacancy.ID, nil, newVacancyAuditState(newVacancy), ctx)
+
 	return &newVacancy.ID, nil
 }
 
-func (s *VacancyService) RetrieveVacancy(vacancyId uuid.UUID, ctx context.Context) (*model.VacancyObject, *base.ServiceError) {
-	vacancy, err := s.vacancyStorage.Retrieve(vacancyId, ctx)
+// UpdateVacancy changes the details of the open vacancy. A new name gives the vacancy a new slug,
+// the former one keeps leading to the vacancy.
+func (s *VacancyService) UpdateVacancy(vacancyID uuid.UUID, request *model.UpdateVacancyRequest, ctx context.Context) *base.ServiceError {
+	vacancy, err := s.vacancyStorage.Retrieve(vacancyID, ctx)
 	if err != nil {
-		return nil, base.NewPostgresReadError(err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return base.NewNotFoundError(fmt.Errorf("vacancy %s not found", vacancyID))
+		}
+		return base.NewPostgresReadError(err)
 	}
 
-	co := make([]model.CandidateObject, 0, len(vacancy.Candidates))
+	if vacancy.ClosedAt != nil {
+		return &base.ServiceError{
+			Err:     fmt.Errorf("vacancy %s is closed", vacancyID),
+			Blame:   base.BlameUser,
+			Code:    http.StatusConflict,
+			Message: "vacancy is closed",
+		}
+	}
+
+	before := newVacancyAuditState(vacancy)
+	formerName, formerSlug := vacancy.Name, vacancy.Slug
 
-	for _, v := range vacancy.Candidates {
-		q, serviceErr := s.candidateService.RetrieveCandidate(v.ID, ctx)
+	if request.Name != nil {
+		vacancy.Name = strings.TrimSpace(*request.Name)
+	}
+
+	if request.Salary != nil {
+		vacancy.Salary = *request.Salary
+	}
+
+	if request.City != nil {
+		vacancy.City = *request.City
+	}
+
+	if request.Description != nil {
+		vacancy.Description = *request.Description
+	}
+
+	if vacancy.Name == "" {
+		return &base.ServiceError{
+			Err:     errors.New("empty vacancy name"),
+			Blame:   base.BlameUser,
+			Code:    http.StatusBadRequest,
+			Message: "vacancy name is required",
+		}
+	}
+
+	if helpers.Slugify(vacancy.Name, entity.VacancySlugFallback) != helpers.Slugify(formerName, entity.VacancySlugFallback) {
+		slug, serviceErr := s.newSlug(vacancy.CompanyID, vacancy.Name, vacancy.ID, ctx)
 		if serviceErr != nil {
-			return nil, serviceErr
+			return serviceErr
 		}
-		co = append(co, *q)
+		vacancy.Slug = slug
 	}
 
-	return &model.VacancyObject{
-		ID:          vacancy.ID,
-		CreatedAt:   vacancy.CreatedAt,
-		UpdatedAt:   vacancy.UpdatedAt,
-		Name:        vacancy.Name,
-		Salary:      vacancy.Salary,
-		City:        vacancy.City,
-		Description: vacancy.Description,
-		Candidates:  co,
-	}, nil
+	if err := s.vacancyStorage.UpdateDetails(vacancy, formerSlug, ctx); err != nil {
+		return base.NewPostgresWriteError(err)
+	}
+
+	s.auditService.Record(enum.AuditVacancyUpdate, enum.AuditTargetVacancy, vacancy.ID, before, newVacancyAuditState(vacancy), ctx)
+
+	return nil
+}
+
+// GetVacancyIDBySlug returns ID of the vacancy and the current slugs of the vacancy and its company.
+// Both slugs may be current or former ones.
+func (s *VacancyService) GetVacancyIDBySlug(companySlug string, vacancySlug string, ctx context.Context) (uuid.UUID, string, string, *base.ServiceError) {
+	company, err := s.companyStorage.RetrieveBySlug(companySlug, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return uuid.Nil, "", "", base.NewNotFoundError(fmt.Errorf("company %q not found", companySlug))
+		}
+		return uuid.Nil, "", "", base.NewPostgresReadError(err)
+	}
+
+	vacancy, err := s.vacancyStorage.RetrieveBySlug(company.ID, vacancySlug, ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return uuid.Nil, "", "", base.NewNotFoundError(fmt.Errorf("vacancy %q of company %s not found", vacancySlug, company.ID))
+		}
+		return uuid.Nil, "", "", base.NewPostgresReadError(err)
+	}
+
+	return vacancy.ID, company.Slug, vacancy.Slug, nil
+}
+
+// newSlug returns a slug for the vacancy name which is not taken by other vacancies of the company.
+func (s *VacancyService) newSlug(companyID uuid.UUID, name string, vacancyID uuid.UUID, ctx context.Context) (string, *base.ServiceError) {
+	slug := helpers.Slugify(name, entity.VacancySlugFallback)
+
+	taken, err := s.vacancyStorage.GetTakenSlugs(companyID, slug, vacancyID, ctx)
+	if err != nil {
+		return "", base.NewPostgresReadError(err)
+	}
+
+	return helpers.UniqueSlug(slug, newSlugSet(taken)), nil
+}
+
+func newVacancyAuditState(vacancy *entity.Vacancy) map[string]any {
+	return map[string]any{
+		"company_id":  vacancy.CompanyID,
+		"name":        vacancy.Name,
+		"slug":        vacancy.Slug,
+		"salary":      vacancy.Salary,
+		"city":        vacancy.City,
+		"description": vacancy.Description,
+	}
+}
+
+// RetrieveVacancy returns the vacancy to the viewer, nil for anonymous users.
+// Personal data of candidates is shown only if the viewer may read candidates of the company.
+func (s *VacancyService) RetrieveVacancy(vacancyId uuid.UUID, viewerID *uuid.UUID, ctx context.Context) (*model.VacancyObject, *base.ServiceError) {
+	vacancy, err := s.vacancyStorage.Retrieve(vacancyId, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	showPersonalData, serviceErr := s.roleService.CanAccess(viewerID, vacancy.CompanyID, enum.PermissionReadCandidates, ctx)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	result := vacancyObject(vacancy, true, showPersonalData)
+	return &result, nil
 }
 
 func (s *VacancyService) GetVacancy(options *dataProcessing.Options, ctx context.Context) ([]model.VacancyObject, *base.ServiceError) {
@@ -86,17 +204,67 @@ func (s *VacancyService) GetVacancy(options *dataProcessing.Options, ctx context
 	result := make([]model.VacancyObject, 0, len(vacancy))
 
 	for _, v := range vacancy {
-		result = append(result, model.VacancyObject{
-			ID:          v.ID,
-			CreatedAt:   v.CreatedAt,
-			UpdatedAt:   v.UpdatedAt,
-			Name:        v.Name,
-			Salary:      v.Salary,
-			City:        v.City,
-			Description: v.Description,
-			Candidates:  nil,
-		})
+		result = append(result, vacancyObject(&v, false, false))
 	}
 
 	return result, nil
 }
+
+// GetCompanyVacancies returns vacancies of the companies grouped by company ID. Vacancies and their candidates
+// are loaded at once and the viewer's permission to read candidates is checked once for all the companies.
+func (s *VacancyService) GetCompanyVacancies(companyIDs []uuid.UUID, viewerID *uuid.UUID, withCandidates bool, ctx context.Context) (map[uuid.UUID][]model.VacancyObject, *base.ServiceError) {
+	result := make(map[uuid.UUID][]model.VacancyObject, len(companyIDs))
+	if len(companyIDs) == 0 {
+		return result, nil
+	}
+
+	vacancies, err := s.vacancyStorage.GetByCompanyIDs(companyIDs, withCandidates, ctx)
+	if err != nil {
+		return nil, base.NewPostgresReadError(err)
+	}
+
+	var readAll bool
+	readable := make(map[uuid.UUID]bool)
+	if withCandidates && viewerID != nil {
+		all, granted, serviceErr := s.roleService.GetCompaniesWithPermission(*viewerID, enum.PermissionReadCandidates, ctx)
+		if serviceErr != nil {
+			return nil, serviceErr
+		}
+
+		readAll = all
+		for _, companyID := range granted {
+			readable[companyID] = true
+		}
+	}
+
+	for _, vacancy := range vacancies {
+		showPersonalData := readAll || readable[vacancy.CompanyID]
+		result[vacancy.CompanyID] = append(result[vacancy.CompanyID], vacancyObject(&vacancy, withCandidates, showPersonalData))
+	}
+
+	return result, nil
+}
+
+// vacancyObject converts the vacancy, candidates are given only if they are loaded.
+func vacancyObject(vacancy *entity.Vacancy, withCandidates bool, showPersonalData bool) model.VacancyObject {
+	result := model.VacancyObject{
+		ID:          vacancy.ID,
+		CreatedAt:   vacancy.CreatedAt,
+		UpdatedAt:   vacancy.UpdatedAt,
+		Name:        vacancy.Name,
+		Slug:        vacancy.Slug,
+		Salary:      vacancy.Salary,
+		City:        vacancy.City,
+		Description: vacancy.Description,
+		ClosedAt:    vacancy.ClosedAt,
+	}
+
+	if withCandidates {
+		result.Candidates = make([]model.CandidateObject, 0, len(vacancy.Candidates))
+		for _, candidate := range vacancy.Candidates {
+			result.Candidates = append(result.Candidates, candidateObject(&candidate, showPersonalData))
+		}
+	}
+
+	return result
+}
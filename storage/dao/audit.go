@@ -0,0 +1,56 @@
+package dao
+
+import (
+	"context"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type AuditStorage struct {
+	db *gorm.DB
+}
+
+func NewAuditStorage(db *gorm.DB) *AuditStorage {
+	return &AuditStorage{db}
+}
+
+func (s *AuditStorage) Create(event *entity.AuditEvent, ctx context.Context) error {
+	return s.db.WithContext(ctx).Create(event).Error
+}
+
+func (s *AuditStorage) Get(options *dataProcessing.Options, ctx context.Context) ([]entity.AuditEvent, int64, error) {
+	var events []entity.AuditEvent
+	tx := s.db.WithContext(ctx).Model(&entity.AuditEvent{})
+
+	tx, total, err := options.UseProcessing(tx)
+	if err != nil {
+		return nil, total, err
+	}
+
+	tx.Find(&events)
+	if tx.Error != nil {
+		return nil, total, tx.Error
+	}
+
+	return events, total, nil
+}
+
+// DeleteOlderThan removes events recorded before the time and returns their number.
+func (s *AuditStorage) DeleteOlderThan(before time.Time, ctx context.Context) (int64, error) {
+	tx := s.db.WithContext(ctx).Unscoped().Where("created_at < ?", before).Delete(&entity.AuditEvent{})
+	return tx.RowsAffected, tx.Error
+}
+
+// GetByUser returns events made by the user or to the user.
+func (s *AuditStorage) GetByUser(userID uuid.UUID, ctx context.Context) ([]entity.AuditEvent, error) {
+	var events []entity.AuditEvent
+	err := s.db.WithContext(ctx).
+		Where("actor_id = ? OR (target_type = ? AND target_id = ?)", userID, enum.AuditTargetUser, userID).
+		Order("created_at").
+		Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,57 @@
+package dao
+
+import (
+	"context"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type BackupCodeStorage struct {
+	db *gorm.DB
+}
+
+func NewBackupCodeStorage(db *gorm.DB) *BackupCodeStorage {
+	return &BackupCodeStorage{db}
+}
+
+// Replace deletes all backup codes of the user and stores the new ones.
+func (s *BackupCodeStorage) Replace(userID uuid.UUID, codes []entity.BackupCode, ctx context.Context) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&entity.BackupCode{}).Error; err != nil {
+			return err
+		}
+
+		if len(codes) == 0 {
+			return nil
+		}
+
+		return tx.Create(&codes).Error
+	})
+}
+
+// Use marks the unused backup code of the user as used. It returns false if there is no such code.
+func (s *BackupCodeStorage) Use(userID uuid.UUID, codeHash string, usedAt time.Time, ctx context.Context) (bool, error) {
+	tx := s.db.WithContext(ctx).
+		Model(&entity.BackupCode{}).
+		Where("user_id = ?", userID).
+		Where("code_hash = ?", codeHash).
+		Where("used_at IS NULL").
+		Update("used_at", usedAt)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected > 0, nil
+}
+
+func (s *BackupCodeStorage) CountUnused(userID uuid.UUID, ctx context.Context) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&entity.BackupCode{}).
+		Where("user_id = ?", userID).
+		Where("used_at IS NULL").
+		Count(&count).Error
+	return count, err
+}
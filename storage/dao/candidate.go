@@ -30,10 +30,15 @@ func (s CandidateStorage) Update(user *entity.Candidate, ctx context.Context) er
 	return s.db.WithContext(ctx).Updates(user).Error
 }
 
-func (s CandidateStorage) Get(options *dataProcessing.Options, ctx context.Context) ([]entity.Candidate, int64, error) {
+// Get returns candidates of the companies, of all companies if companyIDs is nil.
+func (s CandidateStorage) Get(options *dataProcessing.Options, companyIDs []uuid.UUID, ctx context.Context) ([]entity.Candidate, int64, error) {
 	var users []entity.Candidate
 	tx := s.db.WithContext(ctx).Model(&entity.Candidate{})
 
+	if companyIDs != nil {
+		tx = tx.Where("candidates.vacancy_id IN (SELECT id FROM vacancies WHERE company_id IN ?)", companyIDs)
+	}
+
 	tx, total, err := options.UseProcessing(tx)
 	if err != nil {
 		return nil, total, err
@@ -2,12 +2,16 @@
 
 import (
 	"context"
+	"errors"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"time"
 )
 
+var errFormerOwnerInAnotherCompany = errors.New("former owner is a member of another company")
+
 type CompanyStorage struct {
 	db *gorm.DB
 }
@@ -22,7 +26,21 @@ func (s CompanyStorage) Create(company *entity.Company, ctx context.Context) err
 
 func (s CompanyStorage) Retrieve(id uuid.UUID, ctx context.Context) (*entity.Company, error) {
 	var company entity.Company
-	err := s.db.WithContext(ctx).Preload("File").Preload("Users").Preload("Vacancies").First(&company, id).Error
+	err := s.db.WithContext(ctx).Preload("File").First(&company, id).Error
+	return &company, err
+}
+
+// Exists tells whether the company exists and is not archived.
+func (s CompanyStorage) Exists(id uuid.UUID, ctx context.Context) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&entity.Company{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// RetrieveWithArchived returns the company even if it is archived.
+func (s CompanyStorage) RetrieveWithArchived(id uuid.UUID, ctx context.Context) (*entity.Company, error) {
+	var company entity.Company
+	err := s.db.WithContext(ctx).Unscoped().First(&company, id).Error
 	return &company, err
 }
 
@@ -30,9 +48,29 @@ func (s CompanyStorage) Update(user *entity.Company, ctx context.Context) error
 	return s.db.WithContext(ctx).Updates(user).Error
 }
 
+// IsMember tells whether the user is the owner or a member of the company.
+// Members of an archived company stay members until it is purged.
+func (s CompanyStorage) IsMember(companyID uuid.UUID, userID uuid.UUID, ctx context.Context) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).
+		Unscoped().
+		Model(&entity.Company{}).
+		Where("id = ?", companyID).
+		Where("owner = ? OR EXISTS (SELECT 1 FROM users WHERE users.id = ? AND users.company_id = companies.id AND users.deleted_at IS NULL)", userID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetIDByVacancy returns ID of the company the vacancy belongs to.
+func (s CompanyStorage) GetIDByVacancy(vacancyID uuid.UUID, ctx context.Context) (uuid.UUID, error) {
+	var vacancy entity.Vacancy
+	err := s.db.WithContext(ctx).Select("company_id").First(&vacancy, vacancyID).Error
+	return vacancy.CompanyID, err
+}
+
 func (s CompanyStorage) Get(options *dataProcessing.Options, ctx context.Context) ([]entity.Company, int64, error) {
 	var users []entity.Company
-	tx := s.db.WithContext(ctx).Model(&entity.Company{}).Preload("File").Preload("Users").Preload("Vacancies")
+	tx := s.db.WithContext(ctx).Model(&entity.Company{}).Preload("File")
 
 	tx, total, err := options.UseProcessing(tx)
 	if err != nil {
@@ -46,3 +84,313 @@ func (s CompanyStorage) Get(options *dataProcessing.Options, ctx context.Context
 
 	return users, total, nil
 }
+
+// Archive archives the company, closes its vacancies, deletes pending invitations and detaches the logo.
+// Members and their roles are kept, so candidates stay readable until the archive is purged.
+// It returns false if the company is archived already.
+func (s CompanyStorage) Archive(id uuid.UUID, archivedAt time.Time, ctx context.Context) (bool, error) {
+	var archived bool
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Company{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"archived_at": archivedAt,
+			"file_id":     nil,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		archived = result.RowsAffected > 0
+		if !archived {
+			return nil
+		}
+
+		if err := tx.Model(&entity.Vacancy{}).
+			Where("company_id = ?", id).
+			Where("closed_at IS NULL").
+			Update("closed_at", archivedAt).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().
+			Where("company_id = ?", id).
+			Where("accepted_at IS NULL").
+			Delete(&entity.Invitation{}).Error
+	})
+
+	return archived, err
+}
+
+// UpdateDetails sets the name, slug and description of the company. A changed slug keeps the former one as a redirect.
+func (s CompanyStorage) UpdateDetails(company *entity.Company, formerSlug string, ctx context.Context) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Company{}).Where("id = ?", company.ID).Updates(map[string]interface{}{
+			"name":        company.Name,
+			"slug":        company.Slug,
+			"description": company.Description,
+		}).Error; err != nil {
+			return err
+		}
+
+		if formerSlug == "" || formerSlug == company.Slug {
+			return nil
+		}
+
+		// the company may get one of its former slugs back
+		if err := tx.Unscoped().
+			Where("company_id = ?", company.ID).
+			Where("slug = ?", company.Slug).
+			Delete(&entity.CompanySlugRedirect{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&entity.CompanySlugRedirect{
+			Slug:      formerSlug,
+			CompanyID: company.ID,
+		}).Error
+	})
+}
+
+// RetrieveBySlug returns the ID and current slug of the company with the current or a former slug.
+func (s CompanyStorage) RetrieveBySlug(slug string, ctx context.Context) (*entity.Company, error) {
+	var company entity.Company
+	err := s.db.WithContext(ctx).
+		Select("id", "slug").
+		Where("slug = ? OR id IN (SELECT company_id FROM company_slug_redirects WHERE slug = ?)", slug, slug).
+		First(&company).Error
+	return &company, err
+}
+
+// GetTakenSlugs returns the slug and its numbered variants which are or were used by other companies.
+// Former slugs of the company itself are left out, so it can get them back.
+func (s CompanyStorage) GetTakenSlugs(slug string, companyID uuid.UUID, ctx context.Context) ([]string, error) {
+	var slugs, redirects []string
+
+	if err := s.db.WithContext(ctx).
+		Unscoped().
+		Model(&entity.Company{}).
+		Where("slug = ? OR slug LIKE ?", slug, slug+"-%").
+		Where("id <> ?", companyID).
+		Pluck("slug", &slugs).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).
+		Model(&entity.CompanySlugRedirect{}).
+		Where("slug = ? OR slug LIKE ?", slug, slug+"-%").
+		Where("company_id <> ?", companyID).
+		Pluck("slug", &redirects).Error; err != nil {
+		return nil, err
+	}
+
+	return append(slugs, redirects...), nil
+}
+
+// GetArchivedBefore returns companies archived before the time. Companies archived along with
+// a deleted owner are left out, they are purged or restored with the owner.
+func (s CompanyStorage) GetArchivedBefore(before time.Time, ctx context.Context) ([]entity.Company, error) {
+	var companies []entity.Company
+	err := s.db.WithContext(ctx).
+		Unscoped().
+		Where("archived_at < ?", before).
+		Where("NOT EXISTS (SELECT 1 FROM users WHERE users.id = companies.owner AND users.deleted_at IS NOT NULL)").
+		Find(&companies).Error
+	return companies, err
+}
+
+// Purge removes the archived company for good with its vacancies, candidates, invitations, documents and role grants.
+// It returns the documents, their files are left to be removed.
+func (s CompanyStorage) Purge(id uuid.UUID, ctx context.Context) ([]entity.CompanyDocument, error) {
+	var documents []entity.CompanyDocument
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		documents, err = purgeCompanies(tx, []uuid.UUID{id})
+		return err
+	})
+
+	return documents, err
+}
+
+// purgeCompanies deletes the companies with everything belonging to them. Members are kept, they just leave.
+// It returns the deleted documents with their files, which are left to be removed.
+func purgeCompanies(tx *gorm.DB, companyIDs []uuid.UUID) ([]entity.CompanyDocument, error) {
+	var documents []entity.CompanyDocument
+	if err := tx.Unscoped().Preload("File").Where("company_id IN ?", companyIDs).Find(&documents).Error; err != nil {
+		return nil, err
+	}
+
+	vacancies := tx.Unscoped().Model(&entity.Vacancy{}).Select("id").Where("company_id IN ?", companyIDs)
+
+	// members would be deleted by the foreign key cascade otherwise
+	if err := tx.Unscoped().Model(&entity.User{}).Where("company_id IN ?", companyIDs).Update("company_id", nil).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Unscoped().Where("vacancy_id IN (?)", vacancies).Delete(&entity.Candidate{}).Error; err != nil {
+		return nil, err
+	}
+
+	for _, model := range []interface{}{
+		&entity.VacancySlugRedirect{},
+		&entity.Vacancy{},
+		&entity.Invitation{},
+		&entity.CompanyDocument{},
+		&entity.UserRole{},
+		&entity.CompanySlugRedirect{},
+	} {
+		if err := tx.Unscoped().Where("company_id IN ?", companyIDs).Delete(model).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Unscoped().Where("id IN ?", companyIDs).Delete(&entity.Company{}).Error; err != nil {
+		return nil, err
+	}
+
+	return documents, nil
+}
+
+// ExistsOwnedWithMembers tells whether the user owns a company which has other members.
+func (s CompanyStorage) ExistsOwnedWithMembers(userID uuid.UUID, ctx context.Context) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&entity.Company{}).
+		Where("owner = ?", userID).
+		Where("EXISTS (SELECT 1 FROM users WHERE users.company_id = companies.id AND users.id <> ? AND users.deleted_at IS NULL)", userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetOwned returns companies owned by the user.
+func (s CompanyStorage) GetOwned(userID uuid.UUID, ctx context.Context) ([]entity.Company, error) {
+	var companies []entity.Company
+	err := s.db.WithContext(ctx).Where("owner = ?", userID).Order("created_at").Find(&companies).Error
+	return companies, err
+}
+
+// GetMembers returns the owner and members of the company with their roles within it.
+func (s CompanyStorage) GetMembers(companyID uuid.UUID, ownerID uuid.UUID, ctx context.Context) ([]entity.User, error) {
+	var users []entity.User
+	err := s.db.WithContext(ctx).
+		Preload("Roles", "company_id = ?", companyID).
+		Preload("Roles.Role").
+		Where("company_id = ? OR id = ?", companyID, ownerID).
+		Order("created_at").
+		Find(&users).Error
+	return users, err
+}
+
+// RemoveMember takes the user out of the company together with the roles granted within it.
+// It returns false if the user is not a member of the company.
+func (s CompanyStorage) RemoveMember(companyID uuid.UUID, userID uuid.UUID, ctx context.Context) (bool, error) {
+	var removed bool
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.User{}).
+			Where("id = ?", userID).
+			Where("company_id = ?", companyID).
+			Update("company_id", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		removed = result.RowsAffected > 0
+		if !removed {
+			return nil
+		}
+
+		return tx.Unscoped().
+			Where("user_id = ?", userID).
+			Where("company_id = ?", companyID).
+			Delete(&entity.UserRole{}).Error
+	})
+
+	return removed, err
+}
+
+// TransferOwnership makes the member the owner of the company and moves the owner role to them.
+// The former owner stays a member with formerOwnerRoleID or leaves the company if it is nil.
+// It returns false if the owner has changed meanwhile or the former owner is a member of another company.
+func (s CompanyStorage) TransferOwnership(
+	companyID uuid.UUID,
+	formerOwnerID uuid.UUID,
+	newOwnerID uuid.UUID,
+	ownerRoleID uuid.UUID,
+	formerOwnerRoleID *uuid.UUID,
+	ctx context.Context) (bool, error) {
+	var transferred bool
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Company{}).
+			Where("id = ?", companyID).
+			Where("owner = ?", formerOwnerID).
+			Update("owner", newOwnerID)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if err := tx.Unscoped().
+			Where("user_id = ?", formerOwnerID).
+			Where("company_id = ?", companyID).
+			Delete(&entity.UserRole{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().
+			Where("user_id = ?", newOwnerID).
+			Where("company_id = ?", companyID).
+			Where("role_id = ?", ownerRoleID).
+			Delete(&entity.UserRole{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&entity.UserRole{
+			UserID:    newOwnerID,
+			RoleID:    ownerRoleID,
+			CompanyID: &companyID,
+			GrantedBy: &formerOwnerID,
+		}).Error; err != nil {
+			return err
+		}
+
+		if formerOwnerRoleID == nil {
+			if err := tx.Model(&entity.User{}).
+				Where("id = ?", formerOwnerID).
+				Where("company_id = ?", companyID).
+				Update("company_id", nil).Error; err != nil {
+				return err
+			}
+
+			transferred = true
+			return nil
+		}
+
+		result = tx.Model(&entity.User{}).
+			Where("id = ?", formerOwnerID).
+			Where("company_id IS NULL OR company_id = ?", companyID).
+			Update("company_id", companyID)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			// rolls the transfer back
+			return errFormerOwnerInAnotherCompany
+		}
+
+		if err := tx.Create(&entity.UserRole{
+			UserID:    formerOwnerID,
+			RoleID:    *formerOwnerRoleID,
+			CompanyID: &companyID,
+			GrantedBy: &formerOwnerID,
+		}).Error; err != nil {
+			return err
+		}
+
+		transferred = true
+		return nil
+	})
+
+	if errors.Is(err, errFormerOwnerInAnotherCompany) {
+		return false, nil
+	}
+
+	return transferred, err
+}
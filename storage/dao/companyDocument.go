@@ -0,0 +1,54 @@
+package dao
+
+import (
+	"context"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CompanyDocumentStorage struct {
+	db *gorm.DB
+}
+
+func NewCompanyDocumentStorage(db *gorm.DB) *CompanyDocumentStorage {
+	return &CompanyDocumentStorage{db}
+}
+
+func (s *CompanyDocumentStorage) Create(document *entity.CompanyDocument, ctx context.Context) error {
+	return s.db.WithContext(ctx).Create(document).Error
+}
+
+// Retrieve returns the document of the company with its file.
+func (s *CompanyDocumentStorage) Retrieve(id uuid.UUID, companyID uuid.UUID, ctx context.Context) (*entity.CompanyDocument, error) {
+	var document entity.CompanyDocument
+	err := s.db.WithContext(ctx).
+		Preload("File").
+		Where("company_id = ?", companyID).
+		First(&document, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &document, nil
+}
+
+// GetByCompanyID returns documents of the company, the newest first.
+func (s *CompanyDocumentStorage) GetByCompanyID(companyID uuid.UUID, ctx context.Context) ([]entity.CompanyDocument, error) {
+	var documents []entity.CompanyDocument
+	err := s.db.WithContext(ctx).
+		Where("company_id = ?", companyID).
+		Order("created_at DESC").
+		Find(&documents).Error
+	return documents, err
+}
+
+// Delete deletes the document. It returns false if the document is deleted already.
+func (s *CompanyDocumentStorage) Delete(id uuid.UUID, ctx context.Context) (bool, error) {
+	tx := s.db.WithContext(ctx).Unscoped().Delete(&entity.CompanyDocument{}, id)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected == 1, nil
+}
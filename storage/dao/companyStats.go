@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"context"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/google/uuid"
+	"time"
+)
+
+// CompanyStats are totals over vacancies and candidates of a company. Salaries are of open vacancies
+// with the salary given, they are nil if there are none. AverageApplicationAge is in seconds, nil without candidates.
+type CompanyStats struct {
+	OpenVacancies         int64
+	ClosedVacancies       int64
+	MinSalary             *int
+	MaxSalary             *int
+	AverageSalary         *float64
+	Candidates            int64
+	CandidatesSinceWeek   int64
+	CandidatesSinceMonth  int64
+	AverageApplicationAge *float64
+}
+
+// VacancyStats are candidate numbers of a single vacancy. AverageApplicationAge is in seconds, nil without candidates.
+type VacancyStats struct {
+	ID                    uuid.UUID
+	Name                  string
+	Slug                  string
+	Salary                int
+	ClosedAt              *time.Time
+	Candidates            int64
+	CandidatesSinceWeek   int64
+	CandidatesSinceMonth  int64
+	AverageApplicationAge *float64
+}
+
+// GetStats aggregates vacancies and candidates of the company. Candidates applied after weekStart
+// and monthStart are counted as new, the age of applications is measured up to now.
+func (s CompanyStorage) GetStats(companyID uuid.UUID, now time.Time, weekStart time.Time, monthStart time.Time, ctx context.Context) (*CompanyStats, error) {
+	var stats CompanyStats
+
+	if err := s.db.WithContext(ctx).
+		Model(&entity.Vacancy{}).
+		Select(`COUNT(*) FILTER (WHERE closed_at IS NULL) AS open_vacancies,
+			COUNT(*) FILTER (WHERE closed_at IS NOT NULL) AS closed_vacancies,
+			MIN(salary) FILTER (WHERE closed_at IS NULL AND salary > 0) AS min_salary,
+			MAX(salary) FILTER (WHERE closed_at IS NULL AND salary > 0) AS max_salary,
+			AVG(salary) FILTER (WHERE closed_at IS NULL AND salary > 0) AS average_salary`).
+		Where("company_id = ?", companyID).
+		Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+
+	// a scan resets the whole struct, so candidates are scanned separately
+	var candidates struct {
+		Candidates            int64
+		CandidatesSinceWeek   int64
+		CandidatesSinceMonth  int64
+		AverageApplicationAge *float64
+	}
+
+	if err := s.db.WithContext(ctx).
+		Model(&entity.Candidate{}).
+		Select(`COUNT(*) AS candidates,
+			COUNT(*) FILTER (WHERE created_at >= ?) AS candidates_since_week,
+			COUNT(*) FILTER (WHERE created_at >= ?) AS candidates_since_month,
+			AVG(EXTRACT(EPOCH FROM (CAST(? AS timestamptz) - created_at))) AS average_application_age`, weekStart, monthStart, now).
+		Where("vacancy_id IN (SELECT id FROM vacancies WHERE company_id = ? AND deleted_at IS NULL)", companyID).
+		Scan(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	stats.Candidates = candidates.Candidates
+	stats.CandidatesSinceWeek = candidates.CandidatesSinceWeek
+	stats.CandidatesSinceMonth = candidates.CandidatesSinceMonth
+	stats.AverageApplicationAge = candidates.AverageApplicationAge
+
+	return &stats, nil
+}
+
+// GetVacancyStats aggregates candidates of every vacancy of the company, open vacancies come first.
+func (s CompanyStorage) GetVacancyStats(companyID uuid.UUID, now time.Time, weekStart time.Time, monthStart time.Time, ctx context.Context) ([]VacancyStats, error) {
+	var stats []VacancyStats
+	err := s.db.WithContext(ctx).
+		Model(&entity.Vacancy{}).
+		Select(`vacancies.id, vacancies.name, vacancies.slug, vacancies.salary, vacancies.closed_at,
+			COUNT(candidates.id) AS candidates,
+			COUNT(candidates.id) FILTER (WHERE candidates.created_at >= ?) AS candidates_since_week,
+			COUNT(candidates.id) FILTER (WHERE candidates.created_at >= ?) AS candidates_since_month,
+			AVG(EXTRACT(EPOCH FROM (CAST(? AS timestamptz) - candidates.created_at))) AS average_application_age`, weekStart, monthStart, now).
+		Joins("LEFT JOIN candidates ON candidates.vacancy_id = vacancies.id AND candidates.deleted_at IS NULL").
+		Where("vacancies.company_id = ?", companyID).
+		Group("vacancies.id").
+		Order("vacancies.closed_at DESC NULLS FIRST, vacancies.created_at").
+		Scan(&stats).Error
+	return stats, err
+}
@@ -3,6 +3,7 @@
 import (
 	"context"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -17,3 +18,7 @@ func NewFileStorage(db *gorm.DB) *FileStorage {
 func (s *FileStorage) Create(file *entity.File, ctx context.Context) error {
 	return s.db.WithContext(ctx).Create(file).Error
 }
+
+func (s *FileStorage) Delete(id uuid.UUID, ctx context.Context) error {
+	return s.db.WithContext(ctx).Unscoped().Delete(&entity.File{}, id).Error
+}
@@ -0,0 +1,149 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+var errUserInAnotherCompany = errors.New("user is a member of another company")
+
+type InvitationStorage struct {
+	db *gorm.DB
+}
+
+func NewInvitationStorage(db *gorm.DB) *InvitationStorage {
+	return &InvitationStorage{db}
+}
+
+func (s *InvitationStorage) Create(invitation *entity.Invitation, ctx context.Context) error {
+	return s.db.WithContext(ctx).Create(invitation).Error
+}
+
+func (s *InvitationStorage) GetByHash(tokenHash string, ctx context.Context) (*entity.Invitation, error) {
+	var invitation entity.Invitation
+	err := s.db.WithContext(ctx).Preload("Company").Where("token_hash = ?", tokenHash).First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+// GetPendingByCompanyID returns invitations of the company which are neither accepted nor expired.
+func (s *InvitationStorage) GetPendingByCompanyID(companyID uuid.UUID, now time.Time, ctx context.Context) ([]entity.Invitation, error) {
+	var invitations []entity.Invitation
+	err := s.db.WithContext(ctx).
+		Where("company_id = ?", companyID).
+		Where("accepted_at IS NULL").
+		Where("expires_at > ?", now).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+// Accept marks the invitation as accepted and creates the invitee with the company role in one transaction,
+// so a failure leaves the invitation usable. It returns false if the invitation has already been accepted.
+func (s *InvitationStorage) Accept(id uuid.UUID, acceptedAt time.Time, user *entity.User, userRole *entity.UserRole, ctx context.Context) (bool, error) {
+	var accepted bool
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Invitation{}).
+			Where("id = ?", id).
+			Where("accepted_at IS NULL").
+			Update("accepted_at", acceptedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		accepted = result.RowsAffected == 1
+		if !accepted {
+			return nil
+		}
+
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		userRole.UserID = user.ID
+		return tx.Create(userRole).Error
+	})
+
+	return accepted, err
+}
+
+// Join marks the invitation of a registered user as accepted, makes the user a member of the company
+// and grants the company role in one transaction. It returns false if the invitation has already been
+// accepted or the user is a member of a company already, nothing is changed then.
+func (s *InvitationStorage) Join(id uuid.UUID, acceptedAt time.Time, userRole *entity.UserRole, ctx context.Context) (bool, error) {
+	var joined bool
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Invitation{}).
+			Where("id = ?", id).
+			Where("accepted_at IS NULL").
+			Update("accepted_at", acceptedAt)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		result = tx.Model(&entity.User{}).
+			Where("id = ?", userRole.UserID).
+			Where("company_id IS NULL").
+			Update("company_id", userRole.CompanyID)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			// rolls the acceptance back
+			return errUserInAnotherCompany
+		}
+
+		if err := tx.Create(userRole).Error; err != nil {
+			return err
+		}
+
+		joined = true
+		return nil
+	})
+
+	if errors.Is(err, errUserInAnotherCompany) {
+		return false, nil
+	}
+
+	return joined, err
+}
+
+// Delete deletes a pending invitation of the company. It returns false if there is no such invitation.
+func (s *InvitationStorage) Delete(id uuid.UUID, companyID uuid.UUID, ctx context.Context) (bool, error) {
+	tx := s.db.WithContext(ctx).
+		Unscoped().
+		Where("id = ?", id).
+		Where("company_id = ?", companyID).
+		Where("accepted_at IS NULL").
+		Delete(&entity.Invitation{})
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected > 0, nil
+}
+
+// DeletePendingByEmail deletes not accepted invitations of the email into the company.
+func (s *InvitationStorage) DeletePendingByEmail(companyID uuid.UUID, email string, ctx context.Context) error {
+	return s.db.WithContext(ctx).
+		Unscoped().
+		Where("company_id = ?", companyID).
+		Where("email = ?", email).
+		Where("accepted_at IS NULL").
+		Delete(&entity.Invitation{}).Error
+}
+
+// GetByInviter returns all invitations sent by the user.
+func (s *InvitationStorage) GetByInviter(userID uuid.UUID, ctx context.Context) ([]entity.Invitation, error) {
+	var invitations []entity.Invitation
+	err := s.db.WithContext(ctx).Where("invited_by = ?", userID).Order("created_at").Find(&invitations).Error
+	return invitations, err
+}
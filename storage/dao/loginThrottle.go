@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/api/dataProcessing"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type LoginThrottleStorage struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleStorage(db *gorm.DB) *LoginThrottleStorage {
+	return &LoginThrottleStorage{db}
+}
+
+// Get returns the throttle of the subject, or nil if it has no recorded failures.
+func (s *LoginThrottleStorage) Get(scope enum.ThrottleScope, subject string, ctx context.Context) (*entity.LoginThrottle, error) {
+	var throttle entity.LoginThrottle
+	err := s.db.WithContext(ctx).
+		Where("scope = ?", scope).
+		Where("subject = ?", subject).
+		First(&throttle).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &throttle, nil
+}
+
+// RegisterFailure atomically increments the failure counter of the subject and returns the updated throttle.
+// Failures older than windowStart are forgotten, the counter starts over.
+func (s *LoginThrottleStorage) RegisterFailure(scope enum.ThrottleScope, subject string, now time.Time, windowStart time.Time, ctx context.Context) (*entity.LoginThrottle, error) {
+	throttle := entity.LoginThrottle{
+		Scope:         scope,
+		Subject:       subject,
+		Failures:      1,
+		LastFailureAt: now,
+	}
+
+	err := s.db.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "scope"}, {Name: "subject"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failures":        gorm.Expr("CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END", windowStart),
+				"last_failure_at": now,
+				"updated_at":      now,
+			}),
+		},
+		clause.Returning{},
+	).Create(&throttle).Error
+
+	return &throttle, err
+}
+
+func (s *LoginThrottleStorage) Lock(scope enum.ThrottleScope, subject string, until time.Time, ctx context.Context) error {
+	return s.db.WithContext(ctx).
+		Model(&entity.LoginThrottle{}).
+		Where("scope = ?", scope).
+		Where("subject = ?", subject).
+		Update("locked_until", until).Error
+}
+
+func (s *LoginThrottleStorage) Delete(scope enum.ThrottleScope, subject string, ctx context.Context) error {
+	return s.db.WithContext(ctx).
+		Unscoped().
+		Where("scope = ?", scope).
+		Where("subject = ?", subject).
+		Delete(&entity.LoginThrottle{}).Error
+}
+
+// DeleteStale deletes throttles which neither have recent failures nor an active lock.
+func (s *LoginThrottleStorage) DeleteStale(now time.Time, windowStart time.Time, ctx context.Context) error {
+	return s.db.WithContext(ctx).
+		Unscoped().
+		Where("last_failure_at < ?", windowStart).
+		Where("locked_until IS NULL OR locked_until < ?", now).
+		Delete(&entity.LoginThrottle{}).Error
+}
+
+func (s *LoginThrottleStorage) CreateLockoutEvent(event *entity.LockoutEvent, ctx context.Context) error {
+	return s.db.WithContext(ctx).Create(event).Error
+}
+
+func (s *LoginThrottleStorage) GetLockoutEvents(options *dataProcessing.Options, ctx context.Context) ([]entity.LockoutEvent, int64, error) {
+	var events []entity.LockoutEvent
+	tx := s.db.WithContext(ctx).Model(&entity.LockoutEvent{})
+
+	tx, total, err := options.UseProcessing(tx)
+	if err != nil {
+		return nil, total, err
+	}
+
+	tx.Find(&events)
+	if tx.Error != nil {
+		return nil, total, tx.Error
+	}
+
+	return events, total, nil
+}
+
+// GetLockoutEventsBySubject returns lockouts of the subject, e.g. of an account by its email.
+func (s *LoginThrottleStorage) GetLockoutEventsBySubject(scope enum.ThrottleScope, subject string, ctx context.Context) ([]entity.LockoutEvent, error) {
+	var events []entity.LockoutEvent
+	err := s.db.WithContext(ctx).
+		Where("scope = ?", scope).
+		Where("subject = ?", subject).
+		Order("created_at").
+		Find(&events).Error
+	return events, err
+}
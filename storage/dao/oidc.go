@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"context"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type OIDCStorage struct {
+	db *gorm.DB
+}
+
+func NewOIDCStorage(db *gorm.DB) *OIDCStorage {
+	return &OIDCStorage{db}
+}
+
+func (s *OIDCStorage) CreateAuthRequest(request *entity.OIDCAuthRequest, ctx context.Context) error {
+	return s.db.WithContext(ctx).Create(request).Error
+}
+
+// TakeAuthRequest deletes the auth request and returns it, so a state can be redeemed only once.
+// It returns gorm.ErrRecordNotFound if there is no such request.
+func (s *OIDCStorage) TakeAuthRequest(stateHash string, ctx context.Context) (*entity.OIDCAuthRequest, error) {
+	var requests []entity.OIDCAuthRequest
+	err := s.db.WithContext(ctx).
+		Unscoped().
+		Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(requests) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &requests[0], nil
+}
+
+func (s *OIDCStorage) DeleteExpiredAuthRequests(now time.Time, ctx context.Context) error {
+	return s.db.WithContext(ctx).
+		Unscoped().
+		Where("expires_at < ?", now).
+		Delete(&entity.OIDCAuthRequest{}).Error
+}
+
+func (s *OIDCStorage) GetIdentity(provider string, subject string, ctx context.Context) (*entity.ExternalIdentity, error) {
+	var identity entity.ExternalIdentity
+	err := s.db.WithContext(ctx).
+		Where("provider = ?", provider).
+		Where("subject = ?", subject).
+		First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+func (s *OIDCStorage) CreateIdentity(identity *entity.ExternalIdentity, ctx context.Context) error {
+	return s.db.WithContext(ctx).Create(identity).Error
+}
+
+func (s *OIDCStorage) TouchIdentity(id uuid.UUID, email string, loginAt time.Time, ctx context.Context) error {
+	return s.db.WithContext(ctx).Model(&entity.ExternalIdentity{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email":         email,
+		"last_login_at": loginAt,
+	}).Error
+}
+
+func (s *OIDCStorage) GetIdentitiesByUserID(userID uuid.UUID, ctx context.Context) ([]entity.ExternalIdentity, error) {
+	var identities []entity.ExternalIdentity
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(&identities).Error
+	return identities, err
+}
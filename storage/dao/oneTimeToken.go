@@ -0,0 +1,76 @@
+package dao
+
+import (
+	"context"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type OneTimeTokenStorage struct {
+	db *gorm.DB
+}
+
+func NewOneTimeTokenStorage(db *gorm.DB) *OneTimeTokenStorage {
+	return &OneTimeTokenStorage{db}
+}
+
+func (s *OneTimeTokenStorage) Create(token *entity.OneTimeToken, ctx context.Context) error {
+	return s.db.WithContext(ctx).Create(token).Error
+}
+
+func (s *OneTimeTokenStorage) GetByHash(tokenHash string, purpose enum.TokenPurpose, ctx context.Context) (*entity.OneTimeToken, error) {
+	var token entity.OneTimeToken
+	err := s.db.WithContext(ctx).
+		Where("token_hash = ?", tokenHash).
+		Where("purpose = ?", purpose).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// MarkUsed marks token as used. It returns false if the token has already been used,
+// so the same token can't be redeemed by two concurrent requests.
+func (s *OneTimeTokenStorage) MarkUsed(id uuid.UUID, usedAt time.Time, ctx context.Context) (bool, error) {
+	tx := s.db.WithContext(ctx).
+		Model(&entity.OneTimeToken{}).
+		Where("id = ?", id).
+		Where("used_at IS NULL").
+		Update("used_at", usedAt)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected == 1, nil
+}
+
+// ExistsSince tells if a token has been issued to the user for the purpose since the time.
+func (s *OneTimeTokenStorage) ExistsSince(userID uuid.UUID, purpose enum.TokenPurpose, since time.Time, ctx context.Context) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&entity.OneTimeToken{}).
+		Where("user_id = ?", userID).
+		Where("purpose = ?", purpose).
+		Where("created_at > ?", since).
+		Limit(1).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteByUserIDAndPurpose deletes every token of the user issued for the purpose.
+func (s *OneTimeTokenStorage) DeleteByUserIDAndPurpose(userID uuid.UUID, purpose enum.TokenPurpose, ctx context.Context) error {
+	return s.db.WithContext(ctx).
+		Unscoped().
+		Where("user_id = ?", userID).
+		Where("purpose = ?", purpose).
+		Delete(&entity.OneTimeToken{}).Error
+}
+
+func (s *OneTimeTokenStorage) DeleteExpired(now time.Time, ctx context.Context) error {
+	return s.db.WithContext(ctx).Unscoped().Where("expires_at <= ?", now).Delete(&entity.OneTimeToken{}).Error
+}
@@ -0,0 +1,144 @@
+package dao
+
+import (
+	"context"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RoleStorage struct {
+	db *gorm.DB
+}
+
+func NewRoleStorage(db *gorm.DB) *RoleStorage {
+	return &RoleStorage{db}
+}
+
+func (s *RoleStorage) GetRoles(ctx context.Context) ([]entity.Role, error) {
+	var roles []entity.Role
+	err := s.db.WithContext(ctx).Preload("Permissions").Order("name").Find(&roles).Error
+	return roles, err
+}
+
+func (s *RoleStorage) GetRole(name enum.Role, ctx context.Context) (*entity.Role, error) {
+	var role entity.Role
+	err := s.db.WithContext(ctx).Where("name = ?", name).First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+// HasPermission tells whether any role of the user grants the permission. With nil companyID only
+// platform roles are considered, otherwise only roles granted within companyID.
+func (s *RoleStorage) HasPermission(userID uuid.UUID, companyID *uuid.UUID, permission enum.Permission, ctx context.Context) (bool, error) {
+	tx := s.db.WithContext(ctx).
+		Model(&entity.UserRole{}).
+		Joins("JOIN role_permissions ON role_permissions.role_id = user_roles.role_id AND role_permissions.deleted_at IS NULL").
+		Where("user_roles.user_id = ?", userID).
+		Where("role_permissions.permission = ?", permission)
+
+	if companyID != nil {
+		tx = tx.Where("user_roles.company_id = ?", *companyID)
+	} else {
+		tx = tx.Where("user_roles.company_id IS NULL")
+	}
+
+	var count int64
+	err := tx.Count(&count).Error
+	return count > 0, err
+}
+
+// GetCompaniesWithPermission returns companies in which roles of the user grant the permission.
+func (s *RoleStorage) GetCompaniesWithPermission(userID uuid.UUID, permission enum.Permission, ctx context.Context) ([]uuid.UUID, error) {
+	var companyIDs []uuid.UUID
+	err := s.db.WithContext(ctx).
+		Model(&entity.UserRole{}).
+		Joins("JOIN role_permissions ON role_permissions.role_id = user_roles.role_id AND role_permissions.deleted_at IS NULL").
+		Where("user_roles.user_id = ?", userID).
+		Where("user_roles.company_id IS NOT NULL").
+		Where("role_permissions.permission = ?", permission).
+		Distinct().
+		Pluck("user_roles.company_id", &companyIDs).Error
+	return companyIDs, err
+}
+
+// HasRole tells whether the user has the role, in any company for company roles.
+func (s *RoleStorage) HasRole(userID uuid.UUID, role enum.Role, ctx context.Context) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&entity.UserRole{}).
+		Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Where("user_roles.user_id = ?", userID).
+		Where("roles.name = ?", role).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CountUsersWithRole counts grants of the role, for company roles within companyID.
+func (s *RoleStorage) CountUsersWithRole(role enum.Role, companyID *uuid.UUID, ctx context.Context) (int64, error) {
+	tx := s.db.WithContext(ctx).
+		Model(&entity.UserRole{}).
+		Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Where("roles.name = ?", role)
+
+	if companyID != nil {
+		tx = tx.Where("user_roles.company_id = ?", *companyID)
+	}
+
+	var count int64
+	err := tx.Count(&count).Error
+	return count, err
+}
+
+func (s *RoleStorage) GetUserRoles(userID uuid.UUID, ctx context.Context) ([]entity.UserRole, error) {
+	var userRoles []entity.UserRole
+	err := s.db.WithContext(ctx).
+		Preload("Role").
+		Where("user_id = ?", userID).
+		Order("created_at").
+		Find(&userRoles).Error
+	return userRoles, err
+}
+
+func (s *RoleStorage) GetUserRole(id uuid.UUID, userID uuid.UUID, ctx context.Context) (*entity.UserRole, error) {
+	var userRole entity.UserRole
+	err := s.db.WithContext(ctx).
+		Preload("Role").
+		Where("id = ?", id).
+		Where("user_id = ?", userID).
+		First(&userRole).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &userRole, nil
+}
+
+func (s *RoleStorage) ExistsUserRole(userID uuid.UUID, roleID uuid.UUID, companyID *uuid.UUID, ctx context.Context) (bool, error) {
+	tx := s.db.WithContext(ctx).
+		Model(&entity.UserRole{}).
+		Where("user_id = ?", userID).
+		Where("role_id = ?", roleID)
+
+	if companyID != nil {
+		tx = tx.Where("company_id = ?", *companyID)
+	} else {
+		tx = tx.Where("company_id IS NULL")
+	}
+
+	var count int64
+	err := tx.Count(&count).Error
+	return count > 0, err
+}
+
+func (s *RoleStorage) CreateUserRole(userRole *entity.UserRole, ctx context.Context) error {
+	return s.db.WithContext(ctx).Create(userRole).Error
+}
+
+func (s *RoleStorage) DeleteUserRole(id uuid.UUID, ctx context.Context) error {
+	return s.db.WithContext(ctx).Unscoped().Delete(&entity.UserRole{}, id).Error
+}
@@ -0,0 +1,86 @@
+package dao
+
+import (
+	"context"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type ServiceAccountStorage struct {
+	db *gorm.DB
+}
+
+func NewServiceAccountStorage(db *gorm.DB) *ServiceAccountStorage {
+	return &ServiceAccountStorage{db}
+}
+
+func (s *ServiceAccountStorage) Create(account *entity.ServiceAccount, ctx context.Context) error {
+	return s.db.WithContext(ctx).Create(account).Error
+}
+
+func (s *ServiceAccountStorage) Retrieve(id uuid.UUID, ctx context.Context) (*entity.ServiceAccount, error) {
+	var account entity.ServiceAccount
+	err := s.db.WithContext(ctx).Preload("APIKeys").First(&account, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (s *ServiceAccountStorage) Get(ctx context.Context) ([]entity.ServiceAccount, error) {
+	var accounts []entity.ServiceAccount
+	err := s.db.WithContext(ctx).Preload("APIKeys").Order("created_at DESC").Find(&accounts).Error
+	return accounts, err
+}
+
+// Delete deletes the service account together with its API keys. It returns false if there is no such account.
+func (s *ServiceAccountStorage) Delete(id uuid.UUID, ctx context.Context) (bool, error) {
+	var deleted bool
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("service_account_id = ?", id).Delete(&entity.APIKey{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Delete(&entity.ServiceAccount{}, id)
+		deleted = result.RowsAffected > 0
+		return result.Error
+	})
+
+	return deleted, err
+}
+
+func (s *ServiceAccountStorage) CreateAPIKey(key *entity.APIKey, ctx context.Context) error {
+	return s.db.WithContext(ctx).Create(key).Error
+}
+
+func (s *ServiceAccountStorage) GetAPIKeyByPrefix(prefix string, ctx context.Context) (*entity.APIKey, error) {
+	var key entity.APIKey
+	err := s.db.WithContext(ctx).Preload("ServiceAccount").Where("prefix = ?", prefix).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// RevokeAPIKey revokes an active key of the service account. It returns false if there is no such key.
+func (s *ServiceAccountStorage) RevokeAPIKey(id uuid.UUID, serviceAccountID uuid.UUID, revokedAt time.Time, ctx context.Context) (bool, error) {
+	tx := s.db.WithContext(ctx).
+		Model(&entity.APIKey{}).
+		Where("id = ?", id).
+		Where("service_account_id = ?", serviceAccountID).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", revokedAt)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected == 1, nil
+}
+
+func (s *ServiceAccountStorage) TouchAPIKey(id uuid.UUID, usedAt time.Time, ctx context.Context) error {
+	return s.db.WithContext(ctx).Model(&entity.APIKey{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
@@ -5,6 +5,7 @@
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"time"
 )
 
 type SessionStorage struct {
@@ -44,9 +45,10 @@ func (s *SessionStorage) GetByUserIDAndDeviceID(userID uuid.UUID, deviceId strin
 	return session, nil
 }
 
+// GetByUserID returns sessions of the user which were not rotated yet.
 func (s *SessionStorage) GetByUserID(userID uuid.UUID, ctx context.Context) ([]entity.Session, error) {
 	var sessions []entity.Session
-	tx := s.db.WithContext(ctx).Where("user_id = ?", userID).Find(&sessions)
+	tx := s.db.WithContext(ctx).Where("user_id = ?", userID).Where("rotated_at IS NULL").Find(&sessions)
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
@@ -54,6 +56,76 @@ func (s *SessionStorage) GetByUserID(userID uuid.UUID, ctx context.Context) ([]e
 	return sessions, nil
 }
 
+// MarkRotated marks session as rotated. It returns false if the session has already been rotated,
+// so two concurrent refreshes with the same token can't both succeed.
+func (s *SessionStorage) MarkRotated(sessionID uuid.UUID, rotatedAt time.Time, ctx context.Context) (bool, error) {
+	tx := s.db.WithContext(ctx).
+		Model(&entity.Session{}).
+		Where("id = ?", sessionID).
+		Where("rotated_at IS NULL").
+		Update("rotated_at", rotatedAt)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected == 1, nil
+}
+
 func (s *SessionStorage) Delete(sessionID uuid.UUID, ctx context.Context) error {
 	return s.db.WithContext(ctx).Unscoped().Delete(entity.Session{}, sessionID).Error
 }
+
+func (s *SessionStorage) DeleteByFamilyID(familyID uuid.UUID, ctx context.Context) error {
+	return s.db.WithContext(ctx).Unscoped().Where("family_id = ?", familyID).Delete(&entity.Session{}).Error
+}
+
+// DeleteByUserIDAndFamilyID deletes the session family of the user. It returns false if the user has no such family.
+func (s *SessionStorage) DeleteByUserIDAndFamilyID(userID uuid.UUID, familyID uuid.UUID, ctx context.Context) (bool, error) {
+	tx := s.db.WithContext(ctx).
+		Unscoped().
+		Where("user_id = ?", userID).
+		Where("family_id = ?", familyID).
+		Delete(&entity.Session{})
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected > 0, nil
+}
+
+// DeleteByUserIDExceptFamily deletes every session of the user but the ones of the given family.
+// It returns ids of the deleted families.
+func (s *SessionStorage) DeleteByUserIDExceptFamily(userID uuid.UUID, familyID uuid.UUID, ctx context.Context) ([]uuid.UUID, error) {
+	var familyIDs []uuid.UUID
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Session{}).
+			Where("user_id = ?", userID).
+			Where("family_id <> ?", familyID).
+			Distinct("family_id").
+			Pluck("family_id", &familyIDs).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().
+			Where("user_id = ?", userID).
+			Where("family_id <> ?", familyID).
+			Delete(&entity.Session{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return familyIDs, nil
+}
+
+func (s *SessionStorage) DeleteByUserID(userID uuid.UUID, ctx context.Context) error {
+	return s.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Delete(&entity.Session{}).Error
+}
+
+func (s *SessionStorage) DeleteExpiredByUserID(userID uuid.UUID, now time.Time, ctx context.Context) error {
+	return s.db.WithContext(ctx).
+		Unscoped().
+		Where("user_id = ?", userID).
+		Where("expires_at < ? OR absolute_expires_at < ?", now, now).
+		Delete(&entity.Session{}).Error
+}
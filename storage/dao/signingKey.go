@@ -0,0 +1,62 @@
+package dao
+
+import (
+	"context"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"gorm.io/gorm"
+	"time"
+)
+
+type SigningKeyStorage struct {
+	db *gorm.DB
+}
+
+func NewSigningKeyStorage(db *gorm.DB) *SigningKeyStorage {
+	return &SigningKeyStorage{db}
+}
+
+// signingKeyRotationLock is the id of the advisory lock held while a key is created, so a single instance rotates.
+const signingKeyRotationLock = 0x6a77746b
+
+// CreateNext creates the key unless a key of its algorithm was created after the given time.
+// Instances rotating at once are serialized by an advisory lock, so only the first one creates a key.
+func (s *SigningKeyStorage) CreateNext(key *entity.SigningKey, after time.Time, ctx context.Context) (bool, error) {
+	var created bool
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", signingKeyRotationLock).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&entity.SigningKey{}).
+			Where("algorithm = ?", key.Algorithm).
+			Where("created_at > ?", after).
+			Count(&count).Error; err != nil {
+			return err
+		}
+
+		if count > 0 {
+			return nil
+		}
+
+		created = true
+		return tx.Create(key).Error
+	})
+
+	return created, err
+}
+
+// GetValid returns keys of the given algorithm which are not expired at now, newest first.
+func (s *SigningKeyStorage) GetValid(algorithm string, now time.Time, ctx context.Context) ([]entity.SigningKey, error) {
+	var keys []entity.SigningKey
+	err := s.db.WithContext(ctx).
+		Where("algorithm = ?", algorithm).
+		Where("expires_at > ?", now).
+		Order("created_at DESC").
+		Find(&keys).Error
+	return keys, err
+}
+
+func (s *SigningKeyStorage) DeleteExpired(now time.Time, ctx context.Context) error {
+	return s.db.WithContext(ctx).Unscoped().Where("expires_at <= ?", now).Delete(&entity.SigningKey{}).Error
+}
@@ -0,0 +1,38 @@
+package dao
+
+import (
+	"context"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type TokenRevocationStorage struct {
+	db *gorm.DB
+}
+
+func NewTokenRevocationStorage(db *gorm.DB) *TokenRevocationStorage {
+	return &TokenRevocationStorage{db}
+}
+
+func (s *TokenRevocationStorage) Create(revocation *entity.TokenRevocation, ctx context.Context) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(revocation).Error
+}
+
+// Exists reports whether a token with given id, session, user and issue time is covered by any active revocation.
+func (s *TokenRevocationStorage) Exists(tokenID uuid.UUID, sessionID uuid.UUID, userID uuid.UUID, issuedAt time.Time, now time.Time, ctx context.Context) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&entity.TokenRevocation{}).
+		Where("expires_at > ?", now).
+		Where("token_id = ? OR session_id = ? OR (user_id = ? AND revoked_before > ?)", tokenID, sessionID, userID, issuedAt).
+		Limit(1).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (s *TokenRevocationStorage) DeleteExpired(now time.Time, ctx context.Context) error {
+	return s.db.WithContext(ctx).Unscoped().Where("expires_at <= ?", now).Delete(&entity.TokenRevocation{}).Error
+}
@@ -7,6 +7,7 @@
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"time"
 )
 
 type UserStorage struct {
@@ -23,23 +24,254 @@ func (s UserStorage) Create(user *entity.User, ctx context.Context) error {
 	return s.db.WithContext(ctx).Create(user).Error
 }
 
+// CreateWithRole creates the user and grants the role in one transaction, userRole may be nil.
+// The user ID is set on the role.
+func (s UserStorage) CreateWithRole(user *entity.User, userRole *entity.UserRole, ctx context.Context) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		if userRole == nil {
+			return nil
+		}
+
+		userRole.UserID = user.ID
+		return tx.Create(userRole).Error
+	})
+}
+
 func (s UserStorage) Retrieve(id uuid.UUID, ctx context.Context) (*entity.User, error) {
 	var user entity.User
-	err := s.db.WithContext(ctx).Preload("Sessions").First(&user, id).Error
+	err := s.db.WithContext(ctx).Preload("Sessions").Preload("Avatar").First(&user, id).Error
 	return &user, err
 }
 
-func (s UserStorage) DeleteUser(id uuid.UUID, ctx context.Context) error {
-	return s.db.WithContext(ctx).Unscoped().Delete(&entity.User{}, id).Error
+// DeleteUser soft deletes the user and the vacancies of the companies the user owns, the companies are archived.
+// They share the deletion time, so Restore can tell them from the ones deleted or archived on their own.
+func (s UserStorage) DeleteUser(id uuid.UUID, deletedAt time.Time, ctx context.Context) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		owned := tx.Model(&entity.Company{}).Select("id").Where("owner = ?", id)
+
+		if err := tx.Model(&entity.Vacancy{}).
+			Where("company_id IN (?)", owned).
+			Update("deleted_at", deletedAt).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.Company{}).
+			Where("owner = ?", id).
+			Update("archived_at", deletedAt).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.User{}).Where("id = ?", id).Update("deleted_at", deletedAt).Error
+	})
+}
+
+// RetrieveDeleted returns the soft deleted user.
+func (s UserStorage) RetrieveDeleted(id uuid.UUID, ctx context.Context) (*entity.User, error) {
+	var user entity.User
+	err := s.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(&user, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetDeleted returns soft deleted users, the earliest deleted first.
+func (s UserStorage) GetDeleted(ctx context.Context) ([]entity.User, error) {
+	var users []entity.User
+	err := s.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at").Find(&users).Error
+	return users, err
+}
+
+// GetDeletedBefore returns users soft deleted before the time.
+func (s UserStorage) GetDeletedBefore(before time.Time, ctx context.Context) ([]entity.User, error) {
+	var users []entity.User
+	err := s.db.WithContext(ctx).Unscoped().Where("deleted_at < ?", before).Find(&users).Error
+	return users, err
+}
+
+// Restore undoes DeleteUser. It returns false if the user is not deleted at deletedAt.
+func (s UserStorage) Restore(id uuid.UUID, deletedAt time.Time, ctx context.Context) (bool, error) {
+	var restored bool
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().
+			Model(&entity.User{}).
+			Where("id = ?", id).
+			Where("deleted_at = ?", deletedAt).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		restored = result.RowsAffected > 0
+		if !restored {
+			return nil
+		}
+
+		owned := tx.Unscoped().Model(&entity.Company{}).Select("id").Where("owner = ?", id).Where("archived_at = ?", deletedAt)
+
+		if err := tx.Unscoped().
+			Model(&entity.Vacancy{}).
+			Where("company_id IN (?)", owned).
+			Where("deleted_at = ?", deletedAt).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().
+			Model(&entity.Company{}).
+			Where("owner = ?", id).
+			Where("archived_at = ?", deletedAt).
+			Update("archived_at", nil).Error
+	})
+
+	return restored, err
+}
+
+// Purge removes the soft deleted user for good with everything deleted along with it: companies, their vacancies,
+// candidates, invitations, documents and role grants. It returns files of the user and companies and documents
+// of the companies, which are left to be removed.
+func (s UserStorage) Purge(id uuid.UUID, deletedAt time.Time, ctx context.Context) ([]entity.File, []entity.CompanyDocument, error) {
+	var files []entity.File
+	var documents []entity.CompanyDocument
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var companies []entity.Company
+		if err := tx.Unscoped().
+			Where("owner = ?", id).
+			Where("archived_at = ?", deletedAt).
+			Find(&companies).Error; err != nil {
+			return err
+		}
+
+		companyIDs := make([]uuid.UUID, 0, len(companies))
+		fileIDs := make([]uuid.UUID, 0, len(companies)+1)
+		for _, company := range companies {
+			companyIDs = append(companyIDs, company.ID)
+			if company.FileID != nil {
+				fileIDs = append(fileIDs, *company.FileID)
+			}
+		}
+
+		if len(companyIDs) > 0 {
+			var err error
+			if documents, err = purgeCompanies(tx, companyIDs); err != nil {
+				return err
+			}
+		}
+
+		var user entity.User
+		if err := tx.Unscoped().First(&user, id).Error; err != nil {
+			return err
+		}
+
+		if user.AvatarID != nil {
+			fileIDs = append(fileIDs, *user.AvatarID)
+		}
+
+		for _, model := range []interface{}{&entity.Session{}, &entity.OneTimeToken{}} {
+			if err := tx.Unscoped().Where("user_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Unscoped().Delete(&entity.User{}, id).Error; err != nil {
+			return err
+		}
+
+		if len(fileIDs) == 0 {
+			return nil
+		}
+
+		return tx.Where("id IN ?", fileIDs).Find(&files).Error
+	})
+
+	return files, documents, err
 }
 
 func (s UserStorage) Update(user *entity.User, ctx context.Context) error {
 	return s.db.WithContext(ctx).Updates(user).Error
 }
 
-func (s UserStorage) Get(options *dataProcessing.Options, ctx context.Context) ([]entity.User, int64, error) {
+func (s UserStorage) UpdatePassword(id uuid.UUID, password string, ctx context.Context) error {
+	return s.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Update("password", password).Error
+}
+
+// ChangePassword replaces a generated or forgotten password. Both the generated password and the reset
+// link reach the user by email, so the email is verified at changedAt unless it has been already.
+func (s UserStorage) ChangePassword(id uuid.UUID, password string, changedAt time.Time, ctx context.Context) error {
+	return s.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"password":                 password,
+		"password_change_required": false,
+		"email_verified_at":        gorm.Expr("COALESCE(email_verified_at, ?)", changedAt),
+	}).Error
+}
+
+func (s UserStorage) UpdateAvatar(id uuid.UUID, avatarID *uuid.UUID, ctx context.Context) error {
+	return s.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Update("avatar_id", avatarID).Error
+}
+
+func (s UserStorage) UpdatePendingEmail(id uuid.UUID, email string, ctx context.Context) error {
+	return s.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Update("pending_email", email).Error
+}
+
+// VerifyEmail makes the confirmed email the login of the user and clears the pending one.
+func (s UserStorage) VerifyEmail(id uuid.UUID, email string, verifiedAt time.Time, ctx context.Context) error {
+	return s.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email":             email,
+		"pending_email":     "",
+		"email_verified_at": verifiedAt,
+	}).Error
+}
+
+// UpdateTwoFactor sets the TOTP secret of the user and whether two-factor authentication is enabled.
+func (s UserStorage) UpdateTwoFactor(id uuid.UUID, secret string, enabledAt *time.Time, ctx context.Context) error {
+	return s.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"totp_secret":     secret,
+		"totp_enabled_at": enabledAt,
+		"totp_last_step":  0,
+	}).Error
+}
+
+// UseTOTPStep stores the time step of an accepted TOTP code. It returns false if the step
+// or a later one has already been used, so the same code can't be accepted twice.
+func (s UserStorage) UseTOTPStep(id uuid.UUID, step int64, ctx context.Context) (bool, error) {
+	tx := s.db.WithContext(ctx).
+		Model(&entity.User{}).
+		Where("id = ?", id).
+		Where("totp_last_step < ?", step).
+		Update("totp_last_step", step)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected == 1, nil
+}
+
+// ExistsByEmail tells whether the email belongs to a user. Deleted users count, they keep the email until purged.
+func (s UserStorage) ExistsByEmail(email string, ctx context.Context) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Unscoped().Model(&entity.User{}).Where("email = ?", email).Count(&count).Error
+	return count > 0, err
+}
+
+// GetTakenEmails returns those of the emails which belong to users, deleted ones included.
+func (s UserStorage) GetTakenEmails(emails []string, ctx context.Context) ([]string, error) {
+	var taken []string
+	err := s.db.WithContext(ctx).Unscoped().Model(&entity.User{}).Where("email IN ?", emails).Pluck("email", &taken).Error
+	return taken, err
+}
+
+// Get returns users matching the options, limited to members of the company unless companyID is nil.
+func (s UserStorage) Get(options *dataProcessing.Options, companyID *uuid.UUID, ctx context.Context) ([]entity.User, int64, error) {
 	var users []entity.User
-	tx := s.db.WithContext(ctx).Model(&entity.User{})
+	tx := s.db.WithContext(ctx).Model(&entity.User{}).Preload("Avatar")
+	if companyID != nil {
+		tx = tx.Where("company_id = ?", *companyID)
+	}
 
 	tx, total, err := options.UseProcessing(tx)
 	if err != nil {
@@ -67,9 +299,15 @@ func (s UserStorage) GetUser(email string, ctx context.Context) (*entity.User, e
 	return &user, nil
 }
 
-func (s UserStorage) GetByIDList(ids []uuid.UUID, ctx context.Context) ([]entity.User, error) {
+// GetByIDList returns the users, limited to members of the company unless companyID is nil.
+func (s UserStorage) GetByIDList(ids []uuid.UUID, companyID *uuid.UUID, ctx context.Context) ([]entity.User, error) {
 	var users []entity.User
-	err := s.db.WithContext(ctx).Where(ids).Find(&users).Error
+	tx := s.db.WithContext(ctx).Preload("Avatar").Where(ids)
+	if companyID != nil {
+		tx = tx.Where("company_id = ?", *companyID)
+	}
+
+	err := tx.Find(&users).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return []entity.User{}, nil
@@ -78,3 +316,10 @@ func (s UserStorage) GetByIDList(ids []uuid.UUID, ctx context.Context) ([]entity
 	}
 	return users, err
 }
+
+// GetByCompanyIDs returns users of the companies.
+func (s UserStorage) GetByCompanyIDs(companyIDs []uuid.UUID, ctx context.Context) ([]entity.User, error) {
+	var users []entity.User
+	err := s.db.WithContext(ctx).Preload("Avatar").Where("company_id IN ?", companyIDs).Order("created_at").Find(&users).Error
+	return users, err
+}
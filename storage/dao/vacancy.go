@@ -30,9 +30,81 @@ func (s VacancyStorage) Update(user *entity.Vacancy, ctx context.Context) error
 	return s.db.WithContext(ctx).Updates(user).Error
 }
 
+// UpdateDetails sets the details and slug of the vacancy. A changed slug keeps the former one as a redirect.
+func (s VacancyStorage) UpdateDetails(vacancy *entity.Vacancy, formerSlug string, ctx context.Context) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Vacancy{}).Where("id = ?", vacancy.ID).Updates(map[string]interface{}{
+			"name":        vacancy.Name,
+			"slug":        vacancy.Slug,
+			"salary":      vacancy.Salary,
+			"city":        vacancy.City,
+			"description": vacancy.Description,
+		}).Error; err != nil {
+			return err
+		}
+
+		if formerSlug == "" || formerSlug == vacancy.Slug {
+			return nil
+		}
+
+		// the vacancy may get one of its former slugs back
+		if err := tx.Unscoped().
+			Where("company_id = ?", vacancy.CompanyID).
+			Where("slug = ?", vacancy.Slug).
+			Delete(&entity.VacancySlugRedirect{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&entity.VacancySlugRedirect{
+			CompanyID: vacancy.CompanyID,
+			Slug:      formerSlug,
+			VacancyID: vacancy.ID,
+		}).Error
+	})
+}
+
+// RetrieveBySlug returns the ID and current slug of the vacancy of the company with the current or a former slug.
+func (s VacancyStorage) RetrieveBySlug(companyID uuid.UUID, slug string, ctx context.Context) (*entity.Vacancy, error) {
+	var vacancy entity.Vacancy
+	err := s.db.WithContext(ctx).
+		Select("id", "slug", "company_id").
+		Where("company_id = ?", companyID).
+		Where("slug = ? OR id IN (SELECT vacancy_id FROM vacancy_slug_redirects WHERE company_id = ? AND slug = ?)", slug, companyID, slug).
+		First(&vacancy).Error
+	return &vacancy, err
+}
+
+// GetTakenSlugs returns the slug and its numbered variants which are or were used by other vacancies of the company.
+// Former slugs of the vacancy itself are left out, so it can get them back.
+func (s VacancyStorage) GetTakenSlugs(companyID uuid.UUID, slug string, vacancyID uuid.UUID, ctx context.Context) ([]string, error) {
+	var slugs, redirects []string
+
+	if err := s.db.WithContext(ctx).
+		Unscoped().
+		Model(&entity.Vacancy{}).
+		Where("company_id = ?", companyID).
+		Where("slug = ? OR slug LIKE ?", slug, slug+"-%").
+		Where("id <> ?", vacancyID).
+		Pluck("slug", &slugs).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).
+		Model(&entity.VacancySlugRedirect{}).
+		Where("company_id = ?", companyID).
+		Where("slug = ? OR slug LIKE ?", slug, slug+"-%").
+		Where("vacancy_id <> ?", vacancyID).
+		Pluck("slug", &redirects).Error; err != nil {
+		return nil, err
+	}
+
+	return append(slugs, redirects...), nil
+}
+
+// Get returns open vacancies.
 func (s VacancyStorage) Get(options *dataProcessing.Options, ctx context.Context) ([]entity.Vacancy, int64, error) {
 	var users []entity.Vacancy
-	tx := s.db.WithContext(ctx).Model(&entity.Vacancy{}).Preload("Candidates")
+	tx := s.db.WithContext(ctx).Model(&entity.Vacancy{}).Where("closed_at IS NULL")
 
 	tx, total, err := options.UseProcessing(tx)
 	if err != nil {
@@ -46,3 +118,18 @@ func (s VacancyStorage) Get(options *dataProcessing.Options, ctx context.Context
 
 	return users, total, nil
 }
+
+// GetByCompanyIDs returns vacancies of the companies, closed ones included, optionally with their candidates.
+func (s VacancyStorage) GetByCompanyIDs(companyIDs []uuid.UUID, withCandidates bool, ctx context.Context) ([]entity.Vacancy, error) {
+	var vacancies []entity.Vacancy
+	tx := s.db.WithContext(ctx).Where("company_id IN ?", companyIDs).Order("created_at")
+
+	if withCandidates {
+		tx = tx.Preload("Candidates", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		})
+	}
+
+	err := tx.Find(&vacancies).Error
+	return vacancies, err
+}
@@ -4,9 +4,13 @@
 	"errors"
 	"fmt"
 	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/entity"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/domain/enum"
+	"github.com/RucardTomsk/Naimix-Code-BACKEND-GOLANG/internal/helpers"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"strings"
+	"time"
 )
 
 func Migrate(
@@ -16,12 +20,35 @@ func Migrate(
 	adminEmail string,
 	adminPassword string,
 ) error {
+	// users registered before email verification was introduced are trusted
+	backfillEmailVerification := !db.Migrator().HasColumn(&entity.User{}, "EmailVerifiedAt")
+	// company owners and members joined before roles were introduced get their roles granted
+	backfillCompanyRoles := !db.Migrator().HasTable(&entity.UserRole{})
+
 	if err := db.AutoMigrate(
 		&entity.Session{},
+		&entity.SigningKey{},
+		&entity.TokenRevocation{},
+		&entity.OneTimeToken{},
 		&entity.User{},
+		&entity.BackupCode{},
+		&entity.OIDCAuthRequest{},
+		&entity.ExternalIdentity{},
+		&entity.LoginThrottle{},
+		&entity.LockoutEvent{},
+		&entity.ServiceAccount{},
+		&entity.APIKey{},
 		&entity.Company{},
+		&entity.Role{},
+		&entity.RolePermission{},
+		&entity.UserRole{},
+		&entity.Invitation{},
 		&entity.Vacancy{},
+		&entity.CompanySlugRedirect{},
+		&entity.VacancySlugRedirect{},
 		&entity.Candidate{},
+		&entity.CompanyDocument{},
+		&entity.AuditEvent{},
 	); err != nil {
 		//relationship doesn't exist
 		if !strings.Contains(err.Error(), "42P07") {
@@ -30,10 +57,47 @@ func Migrate(
 		}
 	}
 
+	if backfillEmailVerification {
+		if err := emailVerificationMigration(db); err != nil {
+			return err
+		}
+	}
+
 	if err := adminMigration(db, adminID, adminUserName, adminEmail, adminPassword); err != nil {
 		return err
 	}
 
+	if err := sessionMigration(db); err != nil {
+		return err
+	}
+
+	roles, err := roleMigration(db)
+	if err != nil {
+		return err
+	}
+
+	if err := platformAdminPermissionMigration(db, roles); err != nil {
+		return err
+	}
+
+	if err := platformAdminMigration(db, roles, adminID); err != nil {
+		return err
+	}
+
+	if backfillCompanyRoles {
+		if err := companyOwnerMigration(db, roles); err != nil {
+			return err
+		}
+	}
+
+	if err := companyArchiveMigration(db); err != nil {
+		return err
+	}
+
+	if err := slugMigration(db); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -51,10 +115,12 @@ func adminMigration(db *gorm.DB,
 	}
 
 	if tx.RowsAffected == 0 {
+		now := time.Now()
 		user := &entity.User{
-			Email:    adminEmail,
-			Name:     adminUserName,
-			Password: adminPassword,
+			Email:           adminEmail,
+			EmailVerifiedAt: &now,
+			Name:            adminUserName,
+			Password:        adminPassword,
 		}
 		user.ID = adminID
 
@@ -65,3 +131,201 @@ func adminMigration(db *gorm.DB,
 
 	return nil
 }
+
+// emailVerificationMigration marks emails of existing users as verified.
+func emailVerificationMigration(db *gorm.DB) error {
+	return db.Model(&entity.User{}).
+		Where("email_verified_at IS NULL").
+		Update("email_verified_at", gorm.Expr("created_at")).Error
+}
+
+// sessionMigration removes sessions created before session families and expiry were introduced.
+// They can't be refreshed anymore, so keeping them is pointless.
+// Sessions created before activity tracking get their start and last use from the row timestamps.
+func sessionMigration(db *gorm.DB) error {
+	if err := db.Unscoped().Where("family_id IS NULL").Delete(&entity.Session{}).Error; err != nil {
+		return err
+	}
+
+	return db.Model(&entity.Session{}).
+		Where("started_at IS NULL").
+		Updates(map[string]interface{}{
+			"started_at":   gorm.Expr("created_at"),
+			"last_used_at": gorm.Expr("updated_at"),
+		}).Error
+}
+
+var roleDescriptions = map[enum.Role]string{
+	enum.RolePlatformAdmin: "Administrator of the whole platform",
+	enum.RoleCompanyOwner:  "Owner of the company, manages it and its members",
+	enum.RoleRecruiter:     "Manages vacancies and candidates of the company",
+	enum.RoleHiringManager: "Reviews candidates of the company",
+	enum.RoleViewer:        "Reads candidates of the company",
+}
+
+// roleMigration creates missing roles with default permissions and returns IDs of all roles.
+// Permissions of existing roles are left as they are, they may have been changed on purpose.
+func roleMigration(db *gorm.DB) (map[enum.Role]uuid.UUID, error) {
+	roles := make(map[enum.Role]uuid.UUID, len(enum.DefaultRolePermissions))
+
+	for name, permissions := range enum.DefaultRolePermissions {
+		var role entity.Role
+		tx := db.Where("name = ?", name).Limit(1).Find(&role)
+		if tx.Error != nil {
+			return nil, tx.Error
+		}
+
+		if tx.RowsAffected == 0 {
+			role = entity.Role{
+				Name:        name,
+				Description: roleDescriptions[name],
+			}
+			for _, permission := range permissions {
+				role.Permissions = append(role.Permissions, entity.RolePermission{Permission: permission})
+			}
+
+			if err := db.Create(&role).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		roles[name] = role.ID
+	}
+
+	return roles, nil
+}
+
+// platformAdminPermissionMigration adds permissions introduced later to the platform admin role,
+// which is meant to allow everything.
+func platformAdminPermissionMigration(db *gorm.DB, roles map[enum.Role]uuid.UUID) error {
+	for _, permission := range enum.DefaultRolePermissions[enum.RolePlatformAdmin] {
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity.RolePermission{
+			RoleID:     roles[enum.RolePlatformAdmin],
+			Permission: permission,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// platformAdminMigration makes the configured admin a platform administrator unless there is one already.
+func platformAdminMigration(db *gorm.DB, roles map[enum.Role]uuid.UUID, adminID uuid.UUID) error {
+	var count int64
+	if err := db.Model(&entity.UserRole{}).Where("role_id = ?", roles[enum.RolePlatformAdmin]).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	return db.Create(&entity.UserRole{
+		UserID: adminID,
+		RoleID: roles[enum.RolePlatformAdmin],
+	}).Error
+}
+
+// companyOwnerMigration grants the owner role to owners of existing companies.
+func companyOwnerMigration(db *gorm.DB, roles map[enum.Role]uuid.UUID) error {
+	var companies []entity.Company
+	if err := db.Select("id", "owner").Find(&companies).Error; err != nil {
+		return err
+	}
+
+	for _, company := range companies {
+		companyID := company.ID
+		if err := db.Create(&entity.UserRole{
+			UserID:    company.Owner,
+			RoleID:    roles[enum.RoleCompanyOwner],
+			CompanyID: &companyID,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// companyArchiveMigration moves companies deleted before archiving was introduced to the archive.
+func companyArchiveMigration(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&entity.Company{}, "deleted_at") {
+		return nil
+	}
+
+	if err := db.Unscoped().
+		Model(&entity.Company{}).
+		Where("deleted_at IS NOT NULL").
+		Where("archived_at IS NULL").
+		Update("archived_at", gorm.Expr("deleted_at")).Error; err != nil {
+		return err
+	}
+
+	return db.Migrator().DropColumn(&entity.Company{}, "deleted_at")
+}
+
+// slugMigration gives slugs to companies and vacancies created before slugs were introduced.
+func slugMigration(db *gorm.DB) error {
+	var companies []entity.Company
+	if err := db.Unscoped().Where("slug IS NULL OR slug = ''").Order("created_at").Find(&companies).Error; err != nil {
+		return err
+	}
+
+	if len(companies) > 0 {
+		var slugs []string
+		if err := db.Unscoped().Model(&entity.Company{}).Where("slug <> ''").Pluck("slug", &slugs).Error; err != nil {
+			return err
+		}
+
+		taken := make(map[string]bool, len(slugs))
+		for _, slug := range slugs {
+			taken[slug] = true
+		}
+
+		for _, company := range companies {
+			slug := helpers.UniqueSlug(helpers.Slugify(company.Name, entity.CompanySlugFallback), taken)
+			taken[slug] = true
+
+			if err := db.Unscoped().Model(&entity.Company{}).Where("id = ?", company.ID).Update("slug", slug).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	var vacancies []entity.Vacancy
+	if err := db.Where("slug IS NULL OR slug = ''").Order("created_at").Find(&vacancies).Error; err != nil {
+		return err
+	}
+
+	if len(vacancies) == 0 {
+		return nil
+	}
+
+	var taken []entity.Vacancy
+	if err := db.Select("company_id", "slug").Where("slug <> ''").Find(&taken).Error; err != nil {
+		return err
+	}
+
+	// slugs of vacancies are unique within the company
+	takenByCompany := make(map[uuid.UUID]map[string]bool)
+	for _, vacancy := range append(taken, vacancies...) {
+		if takenByCompany[vacancy.CompanyID] == nil {
+			takenByCompany[vacancy.CompanyID] = make(map[string]bool)
+		}
+		if vacancy.Slug != "" {
+			takenByCompany[vacancy.CompanyID][vacancy.Slug] = true
+		}
+	}
+
+	for _, vacancy := range vacancies {
+		slug := helpers.UniqueSlug(helpers.Slugify(vacancy.Name, entity.VacancySlugFallback), takenByCompany[vacancy.CompanyID])
+		takenByCompany[vacancy.CompanyID][slug] = true
+
+		if err := db.Model(&entity.Vacancy{}).Where("id = ?", vacancy.ID).Update("slug", slug).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}